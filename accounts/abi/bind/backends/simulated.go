@@ -31,9 +31,11 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -41,6 +43,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -75,6 +78,7 @@ type SimulatedBackend struct {
 	filterSystem *filters.FilterSystem // for filtering database logs
 
 	config *params.ChainConfig
+	patex  *patexSimulation // Rollup state of a Patex chain, nil if not in Patex mode
 }
 
 // NewSimulatedBackendWithDatabase creates a new binding backend based on the given database
@@ -97,6 +101,7 @@ type simulatedBackendConfig struct {
 	database    ethdb.Database
 	vmConfig    vm.Config
 	consensus   consensus.Engine
+	patex       bool
 }
 
 type SimulatedBackendOpt func(s *simulatedBackendConfig)
@@ -148,14 +153,34 @@ func WithConsensus(consensus consensus.Engine) SimulatedBackendOpt {
 // options that are useful to
 func NewSimulatedBackendWithOpts(opts ...SimulatedBackendOpt) *SimulatedBackend {
 	config := &simulatedBackendConfig{
-		genesis:   core.Genesis{Config: params.AllEthashProtocolChanges, GasLimit: 100000000, Alloc: make(core.GenesisAlloc)},
-		database:  rawdb.NewMemoryDatabase(),
-		consensus: ethash.NewFaker(),
+		genesis:  core.Genesis{Config: params.AllEthashProtocolChanges, GasLimit: 100000000, Alloc: make(core.GenesisAlloc)},
+		database: rawdb.NewMemoryDatabase(),
 	}
 
 	for _, opt := range opts {
 		opt(config)
 	}
+	if config.patex {
+		if config.genesis.Config == nil || !config.genesis.Config.IsPatex() {
+			config.genesis.Config = patexChainConfig()
+		}
+		config.genesis.Difficulty = common.Big0
+		alloc := make(core.GenesisAlloc)
+		for addr, account := range patexPredeploys() {
+			alloc[addr] = account
+		}
+		for addr, account := range config.genesis.Alloc {
+			alloc[addr] = account
+		}
+		config.genesis.Alloc = alloc
+	}
+	if config.consensus == nil {
+		if config.patex {
+			config.consensus = beacon.New(ethash.NewFaker())
+		} else {
+			config.consensus = ethash.NewFaker()
+		}
+	}
 
 	config.genesis.MustCommit(config.database)
 	blockchain, _ := core.NewBlockChain(config.database, config.cacheConfig, &config.genesis, nil, config.consensus, config.vmConfig, nil, nil)
@@ -166,6 +191,13 @@ func NewSimulatedBackendWithOpts(opts ...SimulatedBackendOpt) *SimulatedBackend
 		config:     config.genesis.Config,
 		consensus:  config.consensus,
 	}
+	if config.patex {
+		backend.patex = &patexSimulation{
+			l1BaseFee:     new(big.Int).Set(defaultL1BaseFee),
+			l1FeeOverhead: new(big.Int).Set(defaultL1FeeOverhead),
+			l1FeeScalar:   new(big.Int).Set(defaultL1FeeScalar),
+		}
+	}
 
 	filterBackend := &filterBackend{config.database, blockchain, backend}
 	backend.filterSystem = filters.NewFilterSystem(filterBackend, filters.Config{})
@@ -216,7 +248,9 @@ func (b *SimulatedBackend) Rollback() {
 }
 
 func (b *SimulatedBackend) rollback(parent *types.Block) {
-	blocks, _ := core.GenerateChain(b.config, parent, b.consensus, b.database, 1, func(int, *core.BlockGen) {})
+	blocks, _ := core.GenerateChain(b.config, parent, b.consensus, b.database, 1, func(number int, block *core.BlockGen) {
+		b.addL1InfoDeposit(block)
+	})
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), b.blockchain.StateCache(), nil)
@@ -238,7 +272,7 @@ func (b *SimulatedBackend) Fork(ctx context.Context, parent common.Hash) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if len(b.pendingBlock.Transactions()) != 0 {
+	if b.pendingDirty() {
 		return errors.New("pending block dirty")
 	}
 	block, err := b.blockByHash(ctx, parent)
@@ -749,34 +783,66 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if tx.IsDepositTx() && b.patex == nil {
+		return errNotPatex
+	}
+	return b.sendTransaction(ctx, tx)
+}
+
+func (b *SimulatedBackend) sendTransaction(ctx context.Context, tx *types.Transaction) error {
 	// Get the last block
 	block, err := b.blockByHash(ctx, b.pendingBlock.ParentHash())
 	if err != nil {
 		return fmt.Errorf("could not fetch parent")
 	}
-	// Check transaction validity
-	signer := types.MakeSigner(b.blockchain.Config(), block.Number())
-	sender, err := types.Sender(signer, tx)
-	if err != nil {
-		return fmt.Errorf("invalid transaction: %v", err)
-	}
-	nonce := b.pendingState.GetNonce(sender)
-	if tx.Nonce() != nonce {
-		return fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce)
+	// Check transaction validity, deposits are neither signed nor nonce checked
+	if !tx.IsDepositTx() {
+		signer := types.MakeSigner(b.blockchain.Config(), block.Number())
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("invalid transaction: %v", err)
+		}
+		nonce := b.pendingState.GetNonce(sender)
+		if tx.Nonce() != nonce {
+			return fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce)
+		}
 	}
 	// Include tx in chain
-	blocks, receipts := core.GenerateChain(b.config, block, b.consensus, b.database, 1, func(number int, block *core.BlockGen) {
-		for _, tx := range b.pendingBlock.Transactions() {
+	b.rebuildPending(block, append(append(types.Transactions{}, b.pendingBlock.Transactions()...), tx))
+	return nil
+}
+
+// rebuildPending regenerates the pending block on top of the given parent,
+// including the given transactions.
+func (b *SimulatedBackend) rebuildPending(parent *types.Block, txs types.Transactions) {
+	blocks, receipts := core.GenerateChain(b.config, parent, b.consensus, b.database, 1, func(number int, block *core.BlockGen) {
+		for _, tx := range txs {
 			block.AddTxWithChain(b.blockchain, tx)
 		}
-		block.AddTxWithChain(b.blockchain, tx)
 	})
 	stateDB, _ := b.blockchain.State()
 
 	b.pendingBlock = blocks[0]
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), stateDB.Database(), nil)
 	b.pendingReceipts = receipts[0]
-	return nil
+}
+
+// addL1InfoDeposit opens the given block with an L1 info deposit if the
+// backend simulates a Patex chain.
+func (b *SimulatedBackend) addL1InfoDeposit(block *core.BlockGen) {
+	if b.patex != nil {
+		block.AddTxWithChain(b.blockchain, b.patex.l1InfoDeposit(block.Number(), block.Timestamp()))
+	}
+}
+
+// pendingDirty reports whether the pending block includes any transactions
+// besides the L1 info deposit of a Patex chain.
+func (b *SimulatedBackend) pendingDirty() bool {
+	txs := len(b.pendingBlock.Transactions())
+	if b.patex != nil {
+		txs--
+	}
+	return txs > 0
 }
 
 // FilterLogs executes a log filter operation, blocking during execution and
@@ -880,7 +946,7 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if len(b.pendingBlock.Transactions()) != 0 {
+	if b.pendingDirty() {
 		return errors.New("Could not adjust time on non-empty block")
 	}
 	// Get the last block
@@ -891,6 +957,7 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 
 	blocks, _ := core.GenerateChain(b.config, block, b.consensus, b.database, 1, func(number int, block *core.BlockGen) {
 		block.OffsetTime(int64(adjustment.Seconds()))
+		b.addL1InfoDeposit(block)
 	})
 	stateDB, _ := b.blockchain.State()
 
@@ -993,6 +1060,14 @@ func (fb *filterBackend) ChainConfig() *params.ChainConfig {
 	panic("not supported")
 }
 
+func (fb *filterBackend) HistoricalRouter() *historical.Router {
+	return nil
+}
+
+func (fb *filterBackend) LogIndex() *logindex.Indexer {
+	return nil
+}
+
 func (fb *filterBackend) CurrentHeader() *types.Header {
 	panic("not supported")
 }
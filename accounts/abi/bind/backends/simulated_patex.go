@@ -0,0 +1,304 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// PatexDepositorAddress is the sender of the system deposits of a simulated
+// Patex chain, i.e. the L1 info deposit opening every block and the deposits
+// updating the state of the predeploys.
+var PatexDepositorAddress = common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001")
+
+// patexSystemDepositGas is the gas limit of the system deposits.
+const patexSystemDepositGas = 1_000_000
+
+var (
+	errNotPatex = errors.New("simulated backend is not in Patex mode")
+
+	// setL1BlockValues(uint64,uint64,uint256,bytes32,uint64,bytes32,uint256,uint256)
+	l1InfoSelector = []byte{0x01, 0x5d, 0x8e, 0xb9}
+
+	// Default L1Block oracle values of a simulated Patex chain.
+	defaultL1BaseFee     = big.NewInt(params.GWei)
+	defaultL1FeeOverhead = big.NewInt(188)
+	defaultL1FeeScalar   = big.NewInt(684_000)
+)
+
+// patexSimulation holds the rollup state of a simulated Patex chain that is not
+// part of the chain state itself.
+type patexSimulation struct {
+	l1BaseFee     *big.Int
+	l1FeeOverhead *big.Int
+	l1FeeScalar   *big.Int
+}
+
+// WithPatex runs the simulated backend as a Patex chain. The chain has every
+// Patex fork active from genesis, starts every block with an L1 info deposit
+// and includes stand-ins for the predeploys the protocol depends on:
+//
+//   - the L1Block predeploy, storing the oracle values of the L1 info deposit,
+//   - the shares and gas predeploys, whose state is only updated by the system
+//     through SetSharePrice and SetGasFeeSharing,
+//   - the account configuration predeploy, forwarding calls to the patex
+//     precompile if the account they configure or claim for is the caller.
+//
+// The share price starts at one, so balances initially equal their shares.
+func WithPatex() SimulatedBackendOpt {
+	return func(s *simulatedBackendConfig) {
+		s.patex = true
+	}
+}
+
+// patexChainConfig returns the chain config of a simulated Patex chain.
+func patexChainConfig() *params.ChainConfig {
+	config := *params.AllEthashProtocolChanges
+	config.TerminalTotalDifficulty = common.Big0
+	config.TerminalTotalDifficultyPassed = true
+	config.BedrockBlock = common.Big0
+	config.RegolithTime = new(uint64)
+	config.YieldEventsTime = new(uint64)
+	config.Patex = &params.PatexConfig{
+		EIP1559Elasticity:  6,
+		EIP1559Denominator: 50,
+	}
+	return &config
+}
+
+// patexPredeploys returns the genesis allocation of the predeploy stand-ins.
+func patexPredeploys() core.GenesisAlloc {
+	return core.GenesisAlloc{
+		types.L1BlockAddr: {
+			Code:    l1BlockCode(),
+			Balance: new(big.Int),
+			Storage: map[common.Hash]common.Hash{
+				types.L1BaseFeeSlot: common.BigToHash(defaultL1BaseFee),
+				types.OverheadSlot:  common.BigToHash(defaultL1FeeOverhead),
+				types.ScalarSlot:    common.BigToHash(defaultL1FeeScalar),
+			},
+		},
+		params.PatexSharesAddress: {
+			Code:    systemStorageCode(),
+			Balance: new(big.Int),
+			Storage: map[common.Hash]common.Hash{
+				state.SharePriceSlot: common.BigToHash(common.Big1),
+			},
+		},
+		params.PatexGasAddress: {
+			Code:    systemStorageCode(),
+			Balance: new(big.Int),
+		},
+		params.PatexAccountConfigurationAddress: {
+			Code:    accountConfigurationCode(),
+			Balance: new(big.Int),
+		},
+	}
+}
+
+// onlyDepositorCode is the code prefix reverting any call not made by the
+// depositor. Execution continues at offset 0x1e.
+func onlyDepositorCode() []byte {
+	code := []byte{byte(vm.CALLER), byte(vm.PUSH20)}
+	code = append(code, PatexDepositorAddress.Bytes()...)
+	return append(code,
+		byte(vm.EQ), byte(vm.PUSH1), 0x1e, byte(vm.JUMPI),
+		byte(vm.PUSH1), 0x00, byte(vm.DUP1), byte(vm.REVERT),
+		byte(vm.JUMPDEST), // 0x1e
+	)
+}
+
+// l1BlockCode is the code of the L1Block stand-in. It stores the arguments of
+// setL1BlockValues in the slots of the L1Block predeploy.
+func l1BlockCode() []byte {
+	code := onlyDepositorCode()
+	// slot 0 packs the L1 block number and timestamp
+	code = append(code,
+		byte(vm.PUSH1), 0x24, byte(vm.CALLDATALOAD), byte(vm.PUSH1), 0x40, byte(vm.SHL),
+		byte(vm.PUSH1), 0x04, byte(vm.CALLDATALOAD), byte(vm.OR),
+		byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+	)
+	// slots 1-6 hold the remaining arguments in order
+	for arg := 2; arg < 8; arg++ {
+		code = append(code,
+			byte(vm.PUSH1), byte(4+32*arg), byte(vm.CALLDATALOAD),
+			byte(vm.PUSH1), byte(arg-1), byte(vm.SSTORE),
+		)
+	}
+	return append(code, byte(vm.STOP))
+}
+
+// systemStorageCode is the code of the predeploy stand-ins whose state is only
+// maintained by the system. The calldata is a sequence of (slot, value) pairs
+// that are written to storage.
+func systemStorageCode() []byte {
+	return append(onlyDepositorCode(),
+		byte(vm.PUSH1), 0x00, // offset
+		byte(vm.JUMPDEST), // 0x21: loop while calldatasize > offset
+		byte(vm.DUP1), byte(vm.CALLDATASIZE), byte(vm.GT), byte(vm.ISZERO),
+		byte(vm.PUSH1), 0x37, byte(vm.JUMPI),
+		byte(vm.DUP1), byte(vm.PUSH1), 0x20, byte(vm.ADD), byte(vm.CALLDATALOAD),
+		byte(vm.DUP2), byte(vm.CALLDATALOAD), byte(vm.SSTORE),
+		byte(vm.PUSH1), 0x40, byte(vm.ADD),
+		byte(vm.PUSH1), 0x21, byte(vm.JUMP),
+		byte(vm.JUMPDEST), // 0x37
+		byte(vm.STOP),
+	)
+}
+
+// accountConfigurationCode is the code of the account configuration stand-in.
+// It forwards the calldata to the patex precompile if its first argument, the
+// account to configure or claim for, is the caller, and relays the result.
+func accountConfigurationCode() []byte {
+	return []byte{
+		byte(vm.PUSH1), 0x04, byte(vm.CALLDATALOAD), byte(vm.CALLER), byte(vm.EQ),
+		byte(vm.PUSH1), 0x0c, byte(vm.JUMPI),
+		byte(vm.PUSH1), 0x00, byte(vm.DUP1), byte(vm.REVERT),
+		byte(vm.JUMPDEST), // 0x0c
+		byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.DUP1), byte(vm.CALLDATACOPY),
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0x00,
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH2), 0x01, 0x00, byte(vm.GAS), byte(vm.CALL),
+		byte(vm.RETURNDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.RETURNDATACOPY),
+		byte(vm.PUSH1), 0x2d, byte(vm.JUMPI),
+		byte(vm.RETURNDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.REVERT),
+		byte(vm.JUMPDEST), // 0x2d
+		byte(vm.RETURNDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+	}
+}
+
+// l1InfoDeposit returns the L1 info deposit opening the block with the given
+// number and timestamp, carrying the current L1Block oracle values.
+func (p *patexSimulation) l1InfoDeposit(number *big.Int, time uint64) *types.Transaction {
+	data := make([]byte, 4+32*8)
+	copy(data, l1InfoSelector)
+	new(big.Int).Set(number).FillBytes(data[4 : 4+32])
+	new(big.Int).SetUint64(time).FillBytes(data[4+32 : 4+32*2])
+	p.l1BaseFee.FillBytes(data[4+32*2 : 4+32*3])
+	p.l1FeeOverhead.FillBytes(data[4+32*6 : 4+32*7])
+	p.l1FeeScalar.FillBytes(data[4+32*7 : 4+32*8])
+
+	return types.NewTx(&types.DepositTx{
+		SourceHash: crypto.Keccak256Hash([]byte("L1 info"), number.Bytes()),
+		From:       PatexDepositorAddress,
+		To:         &types.L1BlockAddr,
+		Mint:       nil,
+		Value:      new(big.Int),
+		Gas:        patexSystemDepositGas,
+		Data:       data,
+	})
+}
+
+// SendDeposit includes the given deposit transaction in the pending block. If
+// it has no source hash, a unique one is derived for it.
+func (b *SimulatedBackend) SendDeposit(ctx context.Context, deposit *types.DepositTx) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.patex == nil {
+		return errNotPatex
+	}
+	return b.sendDeposit(ctx, deposit)
+}
+
+func (b *SimulatedBackend) sendDeposit(ctx context.Context, deposit *types.DepositTx) error {
+	if deposit.SourceHash == (common.Hash{}) {
+		deposit.SourceHash = crypto.Keccak256Hash(
+			b.pendingBlock.Number().Bytes(),
+			deposit.From.Bytes(),
+			new(big.Int).SetUint64(b.pendingState.GetNonce(deposit.From)).Bytes(),
+		)
+	}
+	if deposit.Value == nil {
+		deposit.Value = new(big.Int)
+	}
+	return b.sendTransaction(ctx, types.NewTx(deposit))
+}
+
+// sendSystemDeposit includes a deposit by the depositor calling the given
+// predeploy in the pending block.
+func (b *SimulatedBackend) sendSystemDeposit(ctx context.Context, to common.Address, data []byte) error {
+	return b.sendDeposit(ctx, &types.DepositTx{
+		From: PatexDepositorAddress,
+		To:   &to,
+		Gas:  patexSystemDepositGas,
+		Data: data,
+	})
+}
+
+// SetL1BlockValues sets the L1 base fee, fee overhead and fee scalar reported by
+// the L1Block oracle. They apply from the pending block on, whose L1 info
+// deposit is replaced.
+func (b *SimulatedBackend) SetL1BlockValues(ctx context.Context, l1BaseFee, overhead, scalar *big.Int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.patex == nil {
+		return errNotPatex
+	}
+	parent, err := b.blockByHash(ctx, b.pendingBlock.ParentHash())
+	if err != nil {
+		return err
+	}
+	b.patex.l1BaseFee = new(big.Int).Set(l1BaseFee)
+	b.patex.l1FeeOverhead = new(big.Int).Set(overhead)
+	b.patex.l1FeeScalar = new(big.Int).Set(scalar)
+
+	txs := append(types.Transactions{}, b.pendingBlock.Transactions()...)
+	txs[0] = b.patex.l1InfoDeposit(b.pendingBlock.Number(), b.pendingBlock.Time())
+	b.rebuildPending(parent, txs)
+	return nil
+}
+
+// SetSharePrice sets the price of a yield share in the pending block. The
+// balances of accounts with automatic yield change accordingly.
+func (b *SimulatedBackend) SetSharePrice(ctx context.Context, price *big.Int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.patex == nil {
+		return errNotPatex
+	}
+	return b.sendSystemDeposit(ctx, params.PatexSharesAddress,
+		append(state.SharePriceSlot.Bytes(), common.BigToHash(price).Bytes()...))
+}
+
+// SetGasFeeSharing enables or disables the accumulation of the gas fees the
+// given contract is allocated, in the pending block.
+func (b *SimulatedBackend) SetGasFeeSharing(ctx context.Context, contract common.Address, enabled bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.patex == nil {
+		return errNotPatex
+	}
+	gasParams, err := vm.ReadGasParameters(b.pendingState, contract)
+	if err != nil {
+		return err
+	}
+	slot, value := vm.GasParametersSlot(contract), gasParams.PackWithMode(enabled)
+	return b.sendSystemDeposit(ctx, params.PatexGasAddress, append(slot.Bytes(), value.Bytes()...))
+}
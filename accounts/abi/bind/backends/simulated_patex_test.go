@@ -0,0 +1,185 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSimulatedBackendPatex(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		key, _  = crypto.GenerateKey()
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		funds   = big.NewInt(params.Ether)
+		to      = common.HexToAddress("0x1000")
+		minted  = common.HexToAddress("0x2000")
+		nonce   = uint64(0)
+		signer  = types.LatestSignerForChainID(big.NewInt(1337))
+		txCount = 0
+	)
+	sim := NewSimulatedBackendWithOpts(WithPatex(), WithAlloc(core.GenesisAlloc{from: {Balance: funds}}))
+	defer sim.Close()
+
+	send := func(to common.Address, data []byte) *types.Transaction {
+		t.Helper()
+		head, _ := sim.HeaderByNumber(ctx, nil)
+		tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: new(big.Int).Mul(head.BaseFee, big.NewInt(2)),
+			Gas:       200_000,
+			To:        &to,
+			Value:     big.NewInt(1),
+			Data:      data,
+		})
+		if err := sim.SendTransaction(ctx, tx); err != nil {
+			t.Fatalf("failed to send tx: %v", err)
+		}
+		nonce++
+		return tx
+	}
+	receipt := func(tx *types.Transaction) *types.Receipt {
+		t.Helper()
+		receipt, err := sim.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			t.Fatalf("failed to get receipt: %v", err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("tx %d failed", txCount)
+		}
+		txCount++
+		return receipt
+	}
+
+	// Every block opens with the L1 info deposit and transactions pay the L1 fee
+	// of the default oracle values.
+	tx := send(to, nil)
+	sim.Commit()
+	block, _ := sim.BlockByNumber(ctx, nil)
+	if txs := block.Transactions(); len(txs) != 2 || txs[0].Type() != types.DepositTxType {
+		t.Fatalf("block does not start with the L1 info deposit")
+	}
+	rollupGas := tx.RollupDataGas().DataGas(block.Time(), sim.config)
+	want := types.L1Cost(rollupGas, defaultL1BaseFee, defaultL1FeeOverhead, defaultL1FeeScalar)
+	if have := receipt(tx).L1Fee; have == nil || have.Cmp(want) != 0 {
+		t.Fatalf("L1 fee mismatch: have %v, want %v", have, want)
+	}
+	vault, _ := sim.BalanceAt(ctx, params.PatexL1FeeRecipient, nil)
+	if vault.Cmp(want) != 0 {
+		t.Fatalf("L1 fee vault balance mismatch: have %v, want %v", vault, want)
+	}
+
+	// Changed oracle values apply to the pending block.
+	if err := sim.SetL1BlockValues(ctx, big.NewInt(2*params.GWei), big.NewInt(2100), big.NewInt(1_000_000)); err != nil {
+		t.Fatalf("failed to set L1 block values: %v", err)
+	}
+	tx = send(to, nil)
+	sim.Commit()
+	block, _ = sim.BlockByNumber(ctx, nil)
+	rollupGas = tx.RollupDataGas().DataGas(block.Time(), sim.config)
+	want = types.L1Cost(rollupGas, big.NewInt(2*params.GWei), big.NewInt(2100), big.NewInt(1_000_000))
+	if have := receipt(tx).L1Fee; have == nil || have.Cmp(want) != 0 {
+		t.Fatalf("L1 fee mismatch after update: have %v, want %v", have, want)
+	}
+
+	// Raising the share price yields on automatic accounts.
+	before, _ := sim.BalanceAt(ctx, to, nil)
+	if err := sim.SetSharePrice(ctx, big.NewInt(3)); err != nil {
+		t.Fatalf("failed to set share price: %v", err)
+	}
+	sim.Commit()
+	if after, _ := sim.BalanceAt(ctx, to, nil); after.Cmp(new(big.Int).Mul(before, big.NewInt(3))) != 0 {
+		t.Fatalf("balance did not accrue yield: have %v, want %v", after, new(big.Int).Mul(before, big.NewInt(3)))
+	}
+
+	// Deposits mint to their sender.
+	if err := sim.SendDeposit(ctx, &types.DepositTx{From: minted, To: &to, Mint: big.NewInt(300), Gas: 100_000}); err != nil {
+		t.Fatalf("failed to send deposit: %v", err)
+	}
+	sim.Commit()
+	if balance, _ := sim.BalanceAt(ctx, minted, nil); balance.Cmp(big.NewInt(300)) != 0 {
+		t.Fatalf("deposit mint mismatch: have %v, want 300", balance)
+	}
+
+	// Accounts configure their own yield mode through the account configuration
+	// predeploy, which emits the yield event of the patex precompile.
+	configure := append([]byte{0x3b, 0xdb, 0xe9, 0xa5}, common.LeftPadBytes(from.Bytes(), 32)...)
+	configure = append(configure, common.LeftPadBytes([]byte{types.YieldClaimable}, 32)...)
+	tx = send(params.PatexAccountConfigurationAddress, configure)
+	sim.Commit()
+	if logs := receipt(tx).Logs; len(logs) != 1 || logs[0].Topics[0] != types.YieldConfiguredTopic {
+		t.Fatalf("missing yield configured log: %v", logs)
+	}
+	statedb, _ := sim.blockchain.State()
+	if flags := statedb.GetFlags(from); flags != types.YieldClaimable {
+		t.Fatalf("yield mode mismatch: have %d, want %d", flags, types.YieldClaimable)
+	}
+	// Configuring another account is rejected.
+	configure = append([]byte{0x3b, 0xdb, 0xe9, 0xa5}, common.LeftPadBytes(to.Bytes(), 32)...)
+	configure = append(configure, common.LeftPadBytes([]byte{types.YieldDisabled}, 32)...)
+	tx = send(params.PatexAccountConfigurationAddress, configure)
+	sim.Commit()
+	if r, _ := sim.TransactionReceipt(ctx, tx.Hash()); r.Status != types.ReceiptStatusFailed {
+		t.Fatalf("configuring another account succeeded")
+	}
+
+	// Gas fee sharing is enabled by the system.
+	if err := sim.SetGasFeeSharing(ctx, to, true); err != nil {
+		t.Fatalf("failed to enable gas fee sharing: %v", err)
+	}
+	sim.Commit()
+	statedb, _ = sim.blockchain.State()
+	gasParams, err := vm.ReadGasParameters(statedb, to)
+	if err != nil {
+		t.Fatalf("failed to read gas parameters: %v", err)
+	}
+	if !gasParams.Mode() {
+		t.Fatalf("gas fee sharing not enabled")
+	}
+
+	// Predeploy state can't be changed by anyone but the depositor.
+	tx = send(params.PatexSharesAddress, append(state.SharePriceSlot.Bytes(), common.BigToHash(common.Big1).Bytes()...))
+	sim.Commit()
+	if r, _ := sim.TransactionReceipt(ctx, tx.Hash()); r.Status != types.ReceiptStatusFailed {
+		t.Fatalf("share price update by user succeeded")
+	}
+}
+
+func TestSimulatedBackendNotPatex(t *testing.T) {
+	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	if err := sim.SetSharePrice(context.Background(), common.Big2); err != errNotPatex {
+		t.Fatalf("expected %v, got %v", errNotPatex, err)
+	}
+	deposit := types.NewTx(&types.DepositTx{From: testAddr, To: &testAddr, Gas: 21000, Value: new(big.Int)})
+	if err := sim.SendTransaction(context.Background(), deposit); err != errNotPatex {
+		t.Fatalf("expected %v, got %v", errNotPatex, err)
+	}
+}
@@ -104,6 +104,28 @@ type PayloadStatusV1 struct {
 	ValidationError *string      `json:"validationError"`
 }
 
+// PayloadValidationReport is the outcome of executing a payload on top of its
+// parent without importing it.
+type PayloadValidationReport struct {
+	Status          string                `json:"status"`
+	BlockHash       common.Hash           `json:"blockHash"`
+	StateRoot       *common.Hash          `json:"stateRoot"`
+	ReceiptsRoot    *common.Hash          `json:"receiptsRoot"`
+	GasUsed         hexutil.Uint64        `json:"gasUsed"`
+	Transactions    []*TxValidationResult `json:"transactions"`
+	FailedTxIndex   *hexutil.Uint64       `json:"failedTxIndex"`
+	ValidationError *string               `json:"validationError"`
+}
+
+// TxValidationResult is the outcome of executing a single transaction of a
+// payload.
+type TxValidationResult struct {
+	Hash    common.Hash    `json:"hash"`
+	Status  hexutil.Uint64 `json:"status"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	L1Fee   *hexutil.Big   `json:"l1Fee"`
+}
+
 type TransitionConfigurationV1 struct {
 	TerminalTotalDifficulty *hexutil.Big   `json:"terminalTotalDifficulty"`
 	TerminalBlockHash       common.Hash    `json:"terminalBlockHash"`
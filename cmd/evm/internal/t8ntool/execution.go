@@ -83,6 +83,9 @@ type stEnv struct {
 	Withdrawals      []*types.Withdrawal                 `json:"withdrawals,omitempty"`
 	BaseFee          *big.Int                            `json:"currentBaseFee,omitempty"`
 	ParentUncleHash  common.Hash                         `json:"parentUncleHash"`
+	L1BaseFee        *big.Int                            `json:"l1BaseFee,omitempty"`
+	L1FeeOverhead    *big.Int                            `json:"l1FeeOverhead,omitempty"`
+	L1FeeScalar      *big.Int                            `json:"l1FeeScalar,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -98,6 +101,9 @@ type stEnvMarshaling struct {
 	Timestamp        math.HexOrDecimal64
 	ParentTimestamp  math.HexOrDecimal64
 	BaseFee          *math.HexOrDecimal256
+	L1BaseFee        *math.HexOrDecimal256
+	L1FeeOverhead    *math.HexOrDecimal256
+	L1FeeScalar      *math.HexOrDecimal256
 }
 
 type rejectedTx struct {
@@ -144,6 +150,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		Difficulty:  pre.Env.Difficulty,
 		GasLimit:    pre.Env.GasLimit,
 		GetHash:     getHash,
+		L1CostFunc:  pre.Env.l1CostFunc(chainConfig, statedb),
 	}
 	// If currentBaseFee is defined, add it to the vmContext.
 	if pre.Env.BaseFee != nil {
@@ -183,6 +190,11 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		)
 		evm := vm.NewEVM(vmContext, txContext, statedb, chainConfig, vmConfig)
 
+		// Deposits record the nonce of their sender before execution from Regolith on.
+		nonce := tx.Nonce()
+		if msg.IsDepositTx && chainConfig.IsPatexRegolith(vmContext.Time) {
+			nonce = statedb.GetNonce(msg.From)
+		}
 		// (ret []byte, usedGas uint64, failed bool, err error)
 		msgResult, err := core.ApplyMessage(evm, msg, gaspool)
 		if err != nil {
@@ -217,10 +229,13 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 			}
 			receipt.TxHash = tx.Hash()
 			receipt.GasUsed = msgResult.UsedGas
+			if msg.IsDepositTx && chainConfig.IsPatexRegolith(vmContext.Time) {
+				receipt.DepositNonce = &nonce
+			}
 
 			// If the transaction created a contract, store the creation address in the receipt.
 			if msg.To == nil {
-				receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+				receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, nonce)
 			}
 
 			// Set the receipt logs and create the bloom filter.
@@ -291,17 +306,36 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 	return statedb, execRs, nil
 }
 
+// l1CostFunc returns the function computing the L1 data fee of the transactions.
+// If the env provides the L1Block oracle values, the fee is computed from them,
+// otherwise they are read from the L1Block predeploy in the pre-state.
+func (env *stEnv) l1CostFunc(config *params.ChainConfig, statedb *state.StateDB) types.L1CostFunc {
+	if env.L1BaseFee == nil && env.L1FeeOverhead == nil && env.L1FeeScalar == nil {
+		return types.NewL1CostFunc(config, statedb)
+	}
+	l1BaseFee, overhead, scalar := types.ReadL1CostParams(statedb)
+	if env.L1BaseFee != nil {
+		l1BaseFee = env.L1BaseFee
+	}
+	if env.L1FeeOverhead != nil {
+		overhead = env.L1FeeOverhead
+	}
+	if env.L1FeeScalar != nil {
+		scalar = env.L1FeeScalar
+	}
+	return func(blockNum uint64, blockTime uint64, dataGas types.RollupGasData, isDepositTx bool) *big.Int {
+		rollupDataGas := dataGas.DataGas(blockTime, config)
+		if config.Patex == nil || isDepositTx || rollupDataGas == 0 {
+			return nil
+		}
+		return types.L1Cost(rollupDataGas, l1BaseFee, overhead, scalar)
+	}
+}
+
 func MakePreState(db ethdb.Database, accounts core.GenesisAlloc) *state.StateDB {
 	sdb := state.NewDatabaseWithConfig(db, &trie.Config{Preimages: true})
 	statedb, _ := state.New(common.Hash{}, sdb, nil)
-	for addr, a := range accounts {
-		statedb.SetCode(addr, a.Code)
-		statedb.SetNonce(addr, a.Nonce)
-		statedb.SetBalance(addr, a.Balance)
-		for k, v := range a.Storage {
-			statedb.SetState(addr, k, v)
-		}
-	}
+	accounts.Apply(statedb)
 	// Commit and re-open to start with a clean state.
 	root, _ := statedb.Commit(false)
 	statedb, _ = state.New(root, sdb, nil)
@@ -33,6 +33,9 @@ func (s stEnv) MarshalJSON() ([]byte, error) {
 		Withdrawals      []*types.Withdrawal                 `json:"withdrawals,omitempty"`
 		BaseFee          *math.HexOrDecimal256               `json:"currentBaseFee,omitempty"`
 		ParentUncleHash  common.Hash                         `json:"parentUncleHash"`
+		L1BaseFee        *math.HexOrDecimal256               `json:"l1BaseFee,omitempty"`
+		L1FeeOverhead    *math.HexOrDecimal256               `json:"l1FeeOverhead,omitempty"`
+		L1FeeScalar      *math.HexOrDecimal256               `json:"l1FeeScalar,omitempty"`
 	}
 	var enc stEnv
 	enc.Coinbase = common.UnprefixedAddress(s.Coinbase)
@@ -51,6 +54,9 @@ func (s stEnv) MarshalJSON() ([]byte, error) {
 	enc.Withdrawals = s.Withdrawals
 	enc.BaseFee = (*math.HexOrDecimal256)(s.BaseFee)
 	enc.ParentUncleHash = s.ParentUncleHash
+	enc.L1BaseFee = (*math.HexOrDecimal256)(s.L1BaseFee)
+	enc.L1FeeOverhead = (*math.HexOrDecimal256)(s.L1FeeOverhead)
+	enc.L1FeeScalar = (*math.HexOrDecimal256)(s.L1FeeScalar)
 	return json.Marshal(&enc)
 }
 
@@ -73,6 +79,9 @@ func (s *stEnv) UnmarshalJSON(input []byte) error {
 		Withdrawals      []*types.Withdrawal                 `json:"withdrawals,omitempty"`
 		BaseFee          *math.HexOrDecimal256               `json:"currentBaseFee,omitempty"`
 		ParentUncleHash  *common.Hash                        `json:"parentUncleHash"`
+		L1BaseFee        *math.HexOrDecimal256               `json:"l1BaseFee,omitempty"`
+		L1FeeOverhead    *math.HexOrDecimal256               `json:"l1FeeOverhead,omitempty"`
+		L1FeeScalar      *math.HexOrDecimal256               `json:"l1FeeScalar,omitempty"`
 	}
 	var dec stEnv
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -130,5 +139,14 @@ func (s *stEnv) UnmarshalJSON(input []byte) error {
 	if dec.ParentUncleHash != nil {
 		s.ParentUncleHash = *dec.ParentUncleHash
 	}
+	if dec.L1BaseFee != nil {
+		s.L1BaseFee = (*big.Int)(dec.L1BaseFee)
+	}
+	if dec.L1FeeOverhead != nil {
+		s.L1FeeOverhead = (*big.Int)(dec.L1FeeOverhead)
+	}
+	if dec.L1FeeScalar != nil {
+		s.L1FeeScalar = (*big.Int)(dec.L1FeeScalar)
+	}
 	return nil
 }
@@ -353,6 +353,7 @@ func (t *txWithKey) UnmarshalJSON(input []byte) error {
 // For (1), r, s, v, need so be zero, and the `secretKey` needs to be set.
 // If so, we sign it here and now, with the given `secretKey`
 // If the condition above is not met, then it's considered a signed transaction.
+// Deposit transactions carry no signature and are never signed.
 //
 // To manage this, we read the transactions twice, first trying to read the secretKeys,
 // and secondly to read them with the standard tx json format
@@ -362,7 +363,7 @@ func signUnsignedTransactions(txs []*txWithKey, signer types.Signer) (types.Tran
 		tx := txWithKey.tx
 		key := txWithKey.key
 		v, r, s := tx.RawSignatureValues()
-		if key != nil && v.BitLen()+r.BitLen()+s.BitLen() == 0 {
+		if key != nil && tx.Type() != types.DepositTxType && v.BitLen()+r.BitLen()+s.BitLen() == 0 {
 			// This transaction needs to be signed
 			var (
 				signed *types.Transaction
@@ -403,6 +404,18 @@ func (g Alloc) OnAccount(addr common.Address, dumpAccount state.DumpAccount) {
 		Storage: storage,
 		Balance: balance,
 		Nonce:   dumpAccount.Nonce,
+		Flags:   dumpAccount.Flags,
+	}
+	// The raw balance values are only needed to reproduce accounts that hold
+	// yield shares or have a non-default yield mode, for any other account the
+	// balance alone yields the same state.
+	fixed, _ := new(big.Int).SetString(dumpAccount.Fixed, 10)
+	shares, _ := new(big.Int).SetString(dumpAccount.Shares, 10)
+	remainder, _ := new(big.Int).SetString(dumpAccount.Remainder, 10)
+	if dumpAccount.Flags != 0 || fixed.Sign() != 0 || shares.Sign() != 0 {
+		genesisAccount.Fixed = fixed
+		genesisAccount.Shares = shares
+		genesisAccount.Remainder = remainder
 	}
 	g[addr] = genesisAccount
 }
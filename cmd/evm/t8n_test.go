@@ -259,6 +259,14 @@ func TestT8n(t *testing.T) {
 			output: t8nOutput{alloc: true, result: true},
 			expOut: "exp.json",
 		},
+		{ // Test deposit tx, L1 cost from env and yield-bearing alloc
+			base: "./testdata/28",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "Regolith", "",
+			},
+			output: t8nOutput{alloc: true, result: true},
+			expOut: "exp.json",
+		},
 	} {
 		args := []string{"t8n"}
 		args = append(args, tc.output.get()...)
@@ -88,12 +88,24 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolConditionalCostFlag,
+		utils.TxPoolDenyListFlag,
+		utils.TxPoolAllowListFlag,
+		utils.TxPoolMinL1AdjustedTipFlag,
+		utils.TxPoolMaxCalldataSizeFlag,
+		utils.TxPoolMaxL1FeeRatioFlag,
+		utils.TxPoolSenderRateLimitFlag,
+		utils.TxPoolIPRateLimitFlag,
+		utils.TxPoolRateLimitBurstFlag,
 		utils.SyncModeFlag,
 		utils.SyncTargetFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
 		utils.TxLookupLimitFlag,
+		utils.LogIndexFlag,
+		utils.LogIndexLimitFlag,
+		utils.FeeStatsFlag,
 		utils.LightServeFlag,
 		utils.LightIngressFlag,
 		utils.LightEgressFlag,
@@ -132,6 +144,8 @@ var (
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerifyFlag,
 		utils.MinerNewPayloadTimeout,
+		utils.MinerTxOrderingFlag,
+		utils.MinerMaxTxsPerSenderFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -151,11 +165,18 @@ var (
 		utils.GpoPercentileFlag,
 		utils.GpoMaxGasPriceFlag,
 		utils.GpoIgnoreGasPriceFlag,
+		utils.GpoL1AwareFlag,
 		utils.MinerNotifyFullFlag,
 		utils.RollupSequencerHTTPFlag,
+		utils.RollupSequencerRetriesFlag,
+		utils.RollupSequencerHealthCheckFlag,
 		utils.RollupHistoricalRPCFlag,
 		utils.RollupHistoricalRPCTimeoutFlag,
+		utils.RollupHistoricalRPCCacheFlag,
 		utils.RollupDisableTxPoolGossipFlag,
+		utils.RollupConditionalTxsFlag,
+		utils.RollupPayloadTxsFlag,
+		utils.RollupMempoolMirrorFlag,
 		configFileFlag,
 	}, utils.NetworkFlags, utils.DatabasePathFlags)
 
@@ -187,6 +208,12 @@ var (
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
 		utils.AllowUnprotectedTxs,
+		utils.BatchRequestLimit,
+		utils.BatchResponseMaxSize,
+		utils.RPCMethodQuotasFlag,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitBurstFlag,
+		utils.RPCAPIKeysFlag,
 	}
 
 	metricsFlags = []cli.Flag{
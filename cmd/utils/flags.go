@@ -63,6 +63,7 @@ import (
 	"github.com/ethereum/go-ethereum/graphql"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/les"
 	lescatalyst "github.com/ethereum/go-ethereum/les/catalyst"
 	"github.com/ethereum/go-ethereum/log"
@@ -248,6 +249,21 @@ var (
 		Value:    ethconfig.Defaults.TxLookupLimit,
 		Category: flags.EthCategory,
 	}
+	LogIndexFlag = &cli.BoolFlag{
+		Name:     "logindex",
+		Usage:    "Maintain an index of the logs by address and topic for fast log searches",
+		Category: flags.EthCategory,
+	}
+	LogIndexLimitFlag = &cli.Uint64Flag{
+		Name:     "logindex.limit",
+		Usage:    "Number of recent blocks to maintain the log index for (0 = entire chain)",
+		Category: flags.EthCategory,
+	}
+	FeeStatsFlag = &cli.BoolFlag{
+		Name:     "feestats",
+		Usage:    "Record the fees collected by processed blocks, served by patex_getBlockFeeStats",
+		Category: flags.EthCategory,
+	}
 	LightKDFFlag = &cli.BoolFlag{
 		Name:     "lightkdf",
 		Usage:    "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -454,6 +470,52 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolConditionalCostFlag = &cli.Uint64Flag{
+		Name:     "txpool.conditionalcost",
+		Usage:    "Maximum storage roots and slots referenced by the conditionals of all pooled transactions",
+		Value:    ethconfig.Defaults.TxPool.ConditionalCost,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolDenyListFlag = &cli.StringFlag{
+		Name:     "txpool.denylist",
+		Usage:    "Comma separated addresses whose transactions are rejected as sender or recipient",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAllowListFlag = &cli.StringFlag{
+		Name:     "txpool.allowlist",
+		Usage:    "Comma separated senders whose transactions are admitted, all if empty",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolMinL1AdjustedTipFlag = &cli.Uint64Flag{
+		Name:     "txpool.minl1adjustedtip",
+		Usage:    "Minimum effective tip per gas after deducting the L1 fee per gas (wei)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolMaxCalldataSizeFlag = &cli.Uint64Flag{
+		Name:     "txpool.maxcalldatasize",
+		Usage:    "Maximum calldata size of a transaction in bytes (0 = protocol limit)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolMaxL1FeeRatioFlag = &cli.Float64Flag{
+		Name:     "txpool.maxl1feeratio",
+		Usage:    "Maximum share of the L1 fee in the total fee of a transaction (0 = unlimited)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSenderRateLimitFlag = &cli.Float64Flag{
+		Name:     "txpool.senderratelimit",
+		Usage:    "Transactions per second a sender may submit via RPC (0 = unlimited)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolIPRateLimitFlag = &cli.Float64Flag{
+		Name:     "txpool.ipratelimit",
+		Usage:    "Transactions per second a remote address may submit via RPC (0 = unlimited)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolRateLimitBurstFlag = &cli.IntFlag{
+		Name:     "txpool.ratelimitburst",
+		Usage:    "Transactions allowed in a burst above the RPC submission rate limits",
+		Category: flags.TxPoolCategory,
+	}
 
 	// Performance tuning settings
 	CacheFlag = &cli.IntFlag{
@@ -581,6 +643,17 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MinerTxOrderingFlag = &cli.StringFlag{
+		Name:     "miner.txordering",
+		Usage:    "Policy ordering pool transactions in a block (price, arrival, l1tip)",
+		Value:    string(ethconfig.Defaults.Miner.TxOrdering),
+		Category: flags.MinerCategory,
+	}
+	MinerMaxTxsPerSenderFlag = &cli.IntFlag{
+		Name:     "miner.maxtxspersender",
+		Usage:    "Maximum number of pool transactions of a sender in a block (0 = unlimited)",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -802,6 +875,39 @@ var (
 		Usage:    "Allow for unprotected (non EIP155 signed) transactions to be submitted via RPC",
 		Category: flags.APICategory,
 	}
+	BatchRequestLimit = &cli.IntFlag{
+		Name:     "rpc.batch-request-limit",
+		Usage:    "Maximum number of requests in a batch (0=unlimited)",
+		Value:    node.DefaultConfig.BatchRequestLimit,
+		Category: flags.APICategory,
+	}
+	BatchResponseMaxSize = &cli.IntFlag{
+		Name:     "rpc.batch-response-max-size",
+		Usage:    "Maximum number of bytes returned from a batched call, single calls are not limited (0=unlimited)",
+		Value:    node.DefaultConfig.BatchResponseMaxSize,
+		Category: flags.APICategory,
+	}
+	RPCMethodQuotasFlag = &cli.StringFlag{
+		Name:     "rpc.method-quotas",
+		Usage:    "Comma separated limits on concurrently executed calls per method (e.g. eth_getLogs=8,debug_traceTransaction=2)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Maximum number of calls per second per client over HTTP and WebSocket (0=unlimited)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitBurstFlag = &cli.IntFlag{
+		Name:     "rpc.ratelimit.burst",
+		Usage:    "Number of calls a client may make in a burst above the rate limit",
+		Value:    1,
+		Category: flags.APICategory,
+	}
+	RPCAPIKeysFlag = &cli.StringFlag{
+		Name:     "rpc.apikeys",
+		Usage:    "JSON file of API keys and the methods each may call over HTTP and WebSocket (reloadable via admin_reloadAPIKeys, incompatible with GraphQL)",
+		Category: flags.APICategory,
+	}
 	EnablePersonal = &cli.BoolFlag{
 		Name:     "rpc.enabledeprecatedpersonal",
 		Usage:    "Enables the (deprecated) personal namespace",
@@ -915,17 +1021,36 @@ var (
 		Value:    ethconfig.Defaults.GPO.IgnorePrice.Int64(),
 		Category: flags.GasPriceCategory,
 	}
+	GpoL1AwareFlag = &cli.BoolFlag{
+		Name:     "gpo.l1aware",
+		Usage:    "Price the L1 data fee of fee estimates with the recent L1 base fee history instead of the latest value (rollup chains only)",
+		Category: flags.GasPriceCategory,
+	}
 
 	// Rollup Flags
 	RollupSequencerHTTPFlag = &cli.StringFlag{
 		Name:     "rollup.sequencerhttp",
-		Usage:    "HTTP endpoint for the sequencer mempool",
+		Usage:    "HTTP endpoint for the sequencer mempool (comma separated list for failover, in order of preference)",
+		Category: flags.RollupCategory,
+	}
+
+	RollupSequencerRetriesFlag = &cli.IntFlag{
+		Name:     "rollup.sequencerretries",
+		Usage:    "Number of rounds over the sequencer endpoints before forwarding a transaction fails",
+		Value:    3,
+		Category: flags.RollupCategory,
+	}
+
+	RollupSequencerHealthCheckFlag = &cli.DurationFlag{
+		Name:     "rollup.sequencerhealthcheck",
+		Usage:    "Interval of probing the health of the sequencer endpoints",
+		Value:    5 * time.Second,
 		Category: flags.RollupCategory,
 	}
 
 	RollupHistoricalRPCFlag = &cli.StringFlag{
 		Name:     "rollup.historicalrpc",
-		Usage:    "RPC endpoint for historical data.",
+		Usage:    "RPC endpoint for historical data. Accepts a comma separated list of endpoints serving block ranges as <from>-<to>@<url>",
 		Category: flags.RollupCategory,
 	}
 
@@ -936,11 +1061,33 @@ var (
 		Category: flags.RollupCategory,
 	}
 
+	RollupHistoricalRPCCacheFlag = &cli.IntFlag{
+		Name:     "rollup.historicalrpccache",
+		Usage:    "Megabytes of memory allocated to caching historical RPC responses",
+		Value:    historical.DefaultCacheSize,
+		Category: flags.RollupCategory,
+	}
+
 	RollupDisableTxPoolGossipFlag = &cli.BoolFlag{
 		Name:     "rollup.disabletxpoolgossip",
 		Usage:    "Disable transaction pool gossip.",
 		Category: flags.RollupCategory,
 	}
+	RollupConditionalTxsFlag = &cli.BoolFlag{
+		Name:     "rollup.conditionaltxs",
+		Usage:    "Accept transactions with inclusion requirements via eth_sendRawTransactionConditional",
+		Category: flags.RollupCategory,
+	}
+	RollupPayloadTxsFlag = &cli.BoolFlag{
+		Name:     "rollup.payloadtxs",
+		Usage:    "Stream the transactions of payloads under construction via the payloadTransactions subscription, exposing them before they are sealed",
+		Category: flags.RollupCategory,
+	}
+	RollupMempoolMirrorFlag = &cli.StringFlag{
+		Name:     "rollup.mempoolmirror",
+		Usage:    "Websocket or IPC endpoint of the sequencer whose mempool is mirrored into a read-only view of the transaction pool (requires --rollup.disabletxpoolgossip)",
+		Category: flags.RollupCategory,
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = &cli.BoolFlag{
@@ -1253,6 +1400,37 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setRPCLimits applies the access and resource limits of the RPC endpoints from
+// the set command line flags.
+func setRPCLimits(ctx *cli.Context, cfg *node.Config) {
+	if ctx.IsSet(BatchRequestLimit.Name) {
+		cfg.BatchRequestLimit = ctx.Int(BatchRequestLimit.Name)
+	}
+	if ctx.IsSet(BatchResponseMaxSize.Name) {
+		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
+	}
+	if ctx.IsSet(RPCMethodQuotasFlag.Name) {
+		cfg.RPCMethodQuotas = make(map[string]int)
+		for _, entry := range SplitAndTrim(ctx.String(RPCMethodQuotasFlag.Name)) {
+			method, quota, ok := strings.Cut(entry, "=")
+			n, err := strconv.Atoi(strings.TrimSpace(quota))
+			if !ok || err != nil || n <= 0 {
+				Fatalf("Invalid method quota %q in --%s, expected method=limit", entry, RPCMethodQuotasFlag.Name)
+			}
+			cfg.RPCMethodQuotas[strings.TrimSpace(method)] = n
+		}
+	}
+	if ctx.IsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCRateLimit = ctx.Float64(RPCRateLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitBurstFlag.Name) {
+		cfg.RPCRateLimitBurst = ctx.Int(RPCRateLimitBurstFlag.Name)
+	}
+	if ctx.IsSet(RPCAPIKeysFlag.Name) {
+		cfg.APIKeysFile = ctx.String(RPCAPIKeysFlag.Name)
+	}
+}
+
 // setGraphQL creates the GraphQL listener interface string from the set
 // command line flags, returning empty if the GraphQL endpoint is disabled.
 func setGraphQL(ctx *cli.Context, cfg *node.Config) {
@@ -1504,6 +1682,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
 	setWS(ctx, cfg)
+	setRPCLimits(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	SetDataDir(ctx, cfg)
 	setSmartCard(ctx, cfg)
@@ -1601,6 +1780,9 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config, light bool) {
 	if ctx.IsSet(GpoIgnoreGasPriceFlag.Name) {
 		cfg.IgnorePrice = big.NewInt(ctx.Int64(GpoIgnoreGasPriceFlag.Name))
 	}
+	if ctx.IsSet(GpoL1AwareFlag.Name) {
+		cfg.L1Aware = ctx.Bool(GpoL1AwareFlag.Name)
+	}
 }
 
 func setTxPool(ctx *cli.Context, cfg *txpool.Config) {
@@ -1644,6 +1826,46 @@ func setTxPool(ctx *cli.Context, cfg *txpool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolConditionalCostFlag.Name) {
+		cfg.ConditionalCost = ctx.Uint64(TxPoolConditionalCostFlag.Name)
+	}
+	if ctx.IsSet(TxPoolDenyListFlag.Name) {
+		cfg.Admission.DenyList = splitAddresses(ctx, TxPoolDenyListFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAllowListFlag.Name) {
+		cfg.Admission.AllowList = splitAddresses(ctx, TxPoolAllowListFlag.Name)
+	}
+	if ctx.IsSet(TxPoolMinL1AdjustedTipFlag.Name) {
+		cfg.Admission.MinL1AdjustedTip = new(big.Int).SetUint64(ctx.Uint64(TxPoolMinL1AdjustedTipFlag.Name))
+	}
+	if ctx.IsSet(TxPoolMaxCalldataSizeFlag.Name) {
+		cfg.Admission.MaxCalldataSize = ctx.Uint64(TxPoolMaxCalldataSizeFlag.Name)
+	}
+	if ctx.IsSet(TxPoolMaxL1FeeRatioFlag.Name) {
+		cfg.Admission.MaxL1FeeRatio = ctx.Float64(TxPoolMaxL1FeeRatioFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSenderRateLimitFlag.Name) {
+		cfg.Admission.SenderRateLimit = ctx.Float64(TxPoolSenderRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolIPRateLimitFlag.Name) {
+		cfg.Admission.IPRateLimit = ctx.Float64(TxPoolIPRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolRateLimitBurstFlag.Name) {
+		cfg.Admission.RateLimitBurst = ctx.Int(TxPoolRateLimitBurstFlag.Name)
+	}
+}
+
+// splitAddresses parses the comma separated addresses of the given flag.
+func splitAddresses(ctx *cli.Context, name string) []common.Address {
+	var addrs []common.Address
+	for _, account := range strings.Split(ctx.String(name), ",") {
+		trimmed := strings.TrimSpace(account)
+		if !common.IsHexAddress(trimmed) {
+			Fatalf("Invalid account in --%s: %s", name, trimmed)
+		}
+		addrs = append(addrs, common.HexToAddress(trimmed))
+	}
+	return addrs
 }
 
 func setEthash(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1696,6 +1918,16 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNewPayloadTimeout.Name) {
 		cfg.NewPayloadTimeout = ctx.Duration(MinerNewPayloadTimeout.Name)
 	}
+	if ctx.IsSet(MinerTxOrderingFlag.Name) {
+		ordering, err := miner.ParseTxOrdering(ctx.String(MinerTxOrderingFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", MinerTxOrderingFlag.Name, err)
+		}
+		cfg.TxOrdering = ordering
+	}
+	if ctx.IsSet(MinerMaxTxsPerSenderFlag.Name) {
+		cfg.MaxTxsPerSender = ctx.Int(MinerMaxTxsPerSenderFlag.Name)
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1840,6 +2072,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.Bool(LogIndexFlag.Name)
+	}
+	if ctx.IsSet(LogIndexLimitFlag.Name) {
+		cfg.LogIndexLimit = ctx.Uint64(LogIndexLimitFlag.Name)
+	}
+	if ctx.IsSet(FeeStatsFlag.Name) {
+		cfg.FeeStats = ctx.Bool(FeeStatsFlag.Name)
+	}
 	if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.Int(CacheFlag.Name) * ctx.Int(CacheTrieFlag.Name) / 100
 	}
@@ -1903,13 +2144,33 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RollupSequencerHTTPFlag.Name) && !ctx.IsSet(MiningEnabledFlag.Name) {
 		cfg.RollupSequencerHTTP = ctx.String(RollupSequencerHTTPFlag.Name)
 	}
+	if ctx.IsSet(RollupSequencerRetriesFlag.Name) {
+		cfg.RollupSequencerRetries = ctx.Int(RollupSequencerRetriesFlag.Name)
+	}
+	if ctx.IsSet(RollupSequencerHealthCheckFlag.Name) {
+		cfg.RollupSequencerHealthCheckInterval = ctx.Duration(RollupSequencerHealthCheckFlag.Name)
+	}
 	if ctx.IsSet(RollupHistoricalRPCFlag.Name) {
 		cfg.RollupHistoricalRPC = ctx.String(RollupHistoricalRPCFlag.Name)
 	}
 	if ctx.IsSet(RollupHistoricalRPCTimeoutFlag.Name) {
 		cfg.RollupHistoricalRPCTimeout = ctx.Duration(RollupHistoricalRPCTimeoutFlag.Name)
 	}
+	if ctx.IsSet(RollupHistoricalRPCCacheFlag.Name) {
+		cfg.RollupHistoricalRPCCacheSize = ctx.Int(RollupHistoricalRPCCacheFlag.Name)
+	}
 	cfg.RollupDisableTxPoolGossip = ctx.Bool(RollupDisableTxPoolGossipFlag.Name)
+	cfg.RollupConditionalTxs = ctx.Bool(RollupConditionalTxsFlag.Name)
+	cfg.RollupPayloadTxs = ctx.Bool(RollupPayloadTxsFlag.Name)
+	if ctx.IsSet(RollupMempoolMirrorFlag.Name) {
+		if ctx.Bool(MiningEnabledFlag.Name) {
+			Fatalf("Option %q is not supported on a mining node", RollupMempoolMirrorFlag.Name)
+		}
+		if !ctx.Bool(RollupDisableTxPoolGossipFlag.Name) {
+			Fatalf("Option %q requires %q", RollupMempoolMirrorFlag.Name, RollupDisableTxPoolGossipFlag.Name)
+		}
+		cfg.RollupMempoolMirror = ctx.String(RollupMempoolMirrorFlag.Name)
+	}
 	// Override any default configs for hard coded networks.
 	switch {
 	case ctx.Bool(MainnetFlag.Name):
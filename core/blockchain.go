@@ -136,6 +136,7 @@ type CacheConfig struct {
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
+	FeeStats            bool          // Whether to store the fee stats of processed blocks
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
 	SnapshotWait    bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
@@ -442,6 +443,19 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		bc.wg.Add(1)
 		go bc.maintainTxIndex()
 	}
+	// Index the deposits of the blocks written before deposits were indexed.
+	// Blocks above the tail have their deposits indexed as they are written.
+	depositTail := bc.CurrentBlock().Number.Uint64() + 1
+	if tail := rawdb.ReadDepositIndexTail(bc.db); tail != nil && *tail <= depositTail {
+		depositTail = *tail
+	} else {
+		// Either the deposits of all blocks up to the head were written
+		// unindexed, or the chain was rewound below the tail.
+		rawdb.WriteDepositIndexTail(bc.db, depositTail)
+	}
+	bc.wg.Add(1)
+	go bc.indexDeposits(depositTail)
+
 	return bc, nil
 }
 
@@ -724,6 +738,19 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	}
 	// Rewind the header chain, deleting all block bodies until then
 	delFn := func(db ethdb.KeyValueWriter, hash common.Hash, num uint64) {
+		// Remove the deposit indexes pointing to the block, before its body is
+		// deleted. Unlike transaction indexes, they are not subject to a limit
+		// and would linger forever.
+		if body := rawdb.ReadBody(bc.db, hash, num); body != nil {
+			for _, tx := range body.Transactions {
+				if !tx.IsDepositTx() {
+					continue
+				}
+				if entry := rawdb.ReadDepositLookupEntry(bc.db, tx.SourceHash()); entry != nil && *entry == num {
+					rawdb.DeleteDepositLookupEntry(db, tx.SourceHash())
+				}
+			}
+		}
 		// Ignore the error here since light client won't hit this path
 		frozen, _ := bc.db.Ancients()
 		if num+1 <= frozen {
@@ -896,6 +923,7 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	rawdb.WriteHeadFastBlockHash(batch, block.Hash())
 	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	rawdb.WriteDepositLookupEntriesByBlock(batch, block)
 	rawdb.WriteHeadBlockHash(batch, block.Hash())
 
 	// Flush the whole batch into the disk, exit the node if failed
@@ -1037,7 +1065,8 @@ const (
 )
 
 // InsertReceiptChain attempts to complete an already existing header chain with
-// transaction and receipt data.
+// transaction and receipt data. The blocks are not executed, so no fee stats are
+// stored for them.
 func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain []types.Receipts, ancientLimit uint64) (int, error) {
 	// We don't require the chainMu here since we want to maximize the
 	// concurrency of header insertion and receipt insertion.
@@ -1151,6 +1180,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			} else if rawdb.ReadTxIndexTail(bc.db) != nil {
 				rawdb.WriteTxLookupEntriesByBlock(batch, block)
 			}
+			rawdb.WriteDepositLookupEntriesByBlock(batch, block) // Deposit indices are not subject to the lookup limit
 			stats.processed++
 
 			if batch.ValueSize() > ethdb.IdealBatchSize || i == len(blockChain)-1 {
@@ -1233,6 +1263,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			rawdb.WriteBody(batch, block.Hash(), block.NumberU64(), block.Body())
 			rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receiptChain[i])
 			rawdb.WriteTxLookupEntriesByBlock(batch, block) // Always write tx indices for live blocks, we assume they are needed
+			rawdb.WriteDepositLookupEntriesByBlock(batch, block)
 
 			// Write everything belongs to the blocks into the database. So that
 			// we can ensure all components of body is completed(body, receipts,
@@ -1336,7 +1367,7 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 
 // writeBlockWithState writes block, metadata and corresponding state data to the
 // database.
-func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, state *state.StateDB) error {
+func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, feeStats *types.BlockFeeStats, state *state.StateDB) error {
 	// Calculate the total difficulty of the block
 	ptd := bc.GetTd(block.ParentHash(), block.NumberU64()-1)
 	if ptd == nil {
@@ -1353,6 +1384,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	if feeStats != nil && bc.cacheConfig.FeeStats {
+		rawdb.WriteBlockFeeStats(blockBatch, block.Hash(), block.NumberU64(), feeStats)
+	}
 	rawdb.WritePreimages(blockBatch, state.Preimages())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
@@ -1418,20 +1452,21 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 }
 
 // WriteBlockAndSetHead writes the given block and all associated state to the database,
-// and applies the block as the new chain head.
-func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
+// and applies the block as the new chain head. The fee stats of the block are
+// only stored if given, as they are collected while the block is assembled.
+func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, feeStats *types.BlockFeeStats, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
 	if !bc.chainmu.TryLock() {
 		return NonStatTy, errChainStopped
 	}
 	defer bc.chainmu.Unlock()
 
-	return bc.writeBlockAndSetHead(block, receipts, logs, state, emitHeadEvent)
+	return bc.writeBlockAndSetHead(block, receipts, logs, feeStats, state, emitHeadEvent)
 }
 
 // writeBlockAndSetHead is the internal implementation of WriteBlockAndSetHead.
 // This function expects the chain mutex to be held.
-func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
-	if err := bc.writeBlockWithState(block, receipts, state); err != nil {
+func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, feeStats *types.BlockFeeStats, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
+	if err := bc.writeBlockWithState(block, receipts, feeStats, state); err != nil {
 		return NonStatTy, err
 	}
 	currentBlock := bc.CurrentBlock()
@@ -1756,7 +1791,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 
 		// Process block using the parent state as reference point
 		pstart := time.Now()
-		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		receipts, logs, feeStats, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			followupInterrupt.Store(true)
@@ -1796,9 +1831,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 		)
 		if !setHead {
 			// Don't set the head, only insert the block
-			err = bc.writeBlockWithState(block, receipts, statedb)
+			err = bc.writeBlockWithState(block, receipts, feeStats, statedb)
 		} else {
-			status, err = bc.writeBlockAndSetHead(block, receipts, logs, statedb, false)
+			status, err = bc.writeBlockAndSetHead(block, receipts, logs, feeStats, statedb, false)
 		}
 		followupInterrupt.Store(true)
 		if err != nil {
@@ -2176,6 +2211,11 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 	for _, tx := range types.HashDifference(deletedTxs, addedTxs) {
 		rawdb.DeleteTxLookupEntry(indexesBatch, tx)
 	}
+	// Deposits derived from the same L1 origin are usually re-included by the
+	// new chain, only drop the indexes of the ones which are not.
+	for _, sourceHash := range types.HashDifference(depositSources(oldChain), depositSources(newChain)) {
+		rawdb.DeleteDepositLookupEntry(indexesBatch, sourceHash)
+	}
 
 	// Delete all hash markers that are not part of the new canonical chain.
 	// Because the reorg function does not handle new chain head, all hash
@@ -2235,6 +2275,19 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 	return nil
 }
 
+// depositSources returns the source hashes of all deposits in the given blocks.
+func depositSources(blocks types.Blocks) []common.Hash {
+	var sources []common.Hash
+	for _, block := range blocks {
+		for _, tx := range block.Transactions() {
+			if tx.IsDepositTx() {
+				sources = append(sources, tx.SourceHash())
+			}
+		}
+	}
+	return sources
+}
+
 // InsertBlockWithoutSetHead executes the block, runs the necessary verification
 // upon it and then persist the block and the associate state into the database.
 // The key difference between the InsertChain is it won't do the canonical chain
@@ -2432,6 +2485,56 @@ func (bc *BlockChain) maintainTxIndex() {
 	}
 }
 
+// indexDeposits indexes the deposits of the blocks below the given deposit index
+// tail, down to the Bedrock block.
+func (bc *BlockChain) indexDeposits(tail uint64) {
+	defer bc.wg.Done()
+
+	var bottom uint64
+	if bc.chainConfig.BedrockBlock != nil {
+		bottom = bc.chainConfig.BedrockBlock.Uint64()
+	}
+	var (
+		start = time.Now()
+		from  = tail
+		batch = bc.db.NewBatch()
+	)
+loop:
+	for tail > bottom {
+		select {
+		case <-bc.quit:
+			break loop
+		default:
+		}
+		number := tail - 1
+		block := rawdb.ReadBlock(bc.db, rawdb.ReadCanonicalHash(bc.db, number), number)
+		if block == nil {
+			log.Warn("Deposit indexing stopped at missing block", "number", number)
+			break
+		}
+		rawdb.WriteDepositLookupEntriesByBlock(batch, block)
+		tail = number
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			rawdb.WriteDepositIndexTail(batch, tail)
+			if err := batch.Write(); err != nil {
+				log.Error("Failed to write deposit indexes", "err", err)
+				return
+			}
+			batch.Reset()
+		}
+	}
+	if tail == from {
+		return
+	}
+	rawdb.WriteDepositIndexTail(batch, tail)
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write deposit indexes", "err", err)
+		return
+	}
+	log.Info("Indexed deposits", "from", tail, "to", from-1, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error) {
 	rawdb.WriteBadBlock(bc.db, block)
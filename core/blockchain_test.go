@@ -162,7 +162,7 @@ func testBlockChainImport(chain types.Blocks, blockchain *BlockChain) error {
 		if err != nil {
 			return err
 		}
-		receipts, _, usedGas, err := blockchain.processor.Process(block, statedb, vm.Config{})
+		receipts, _, _, usedGas, err := blockchain.processor.Process(block, statedb, vm.Config{})
 		if err != nil {
 			blockchain.reportBlock(block, receipts, err)
 			return err
@@ -4338,3 +4338,261 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// Tests that the fee stats of processed blocks are stored alongside the receipts
+// if enabled.
+func TestBlockFeeStats(t *testing.T) {
+	var (
+		engine  = ethash.NewFaker()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 2, func(i int, b *BlockGen) {
+		for j := 0; j < i+1; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0xaa}, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+			b.AddTx(tx)
+		}
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	if stats := rawdb.ReadBlockFeeStats(chain.db, blocks[0].Hash(), blocks[0].NumberU64()); stats != nil {
+		t.Fatalf("fee stats stored without enabling them")
+	}
+	chain.Stop()
+
+	cacheConfig := *defaultCacheConfig
+	cacheConfig.FeeStats = true
+	chain, err = NewBlockChain(rawdb.NewMemoryDatabase(), &cacheConfig, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	for i, block := range blocks {
+		stats := rawdb.ReadBlockFeeStats(chain.db, block.Hash(), block.NumberU64())
+		if stats == nil {
+			t.Fatalf("block %d: fee stats missing", i)
+		}
+		if stats.UserTxs != uint64(i+1) || stats.Deposits != 0 {
+			t.Errorf("block %d: transaction count mismatch: have %d user txs and %d deposits, want %d and 0", i, stats.UserTxs, stats.Deposits, i+1)
+		}
+		if stats.UserGasUsed != block.GasUsed() {
+			t.Errorf("block %d: gas used mismatch: have %d, want %d", i, stats.UserGasUsed, block.GasUsed())
+		}
+	}
+}
+
+// gasBurner is a tracer burning gas behind the back of the gas tracker at the
+// first step of the execution, forcing the canary fallback of the fee split.
+type gasBurner struct {
+	*logger.StructLogger
+	burnt bool
+}
+
+func (b *gasBurner) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if !b.burnt {
+		scope.Contract.Gas -= 1000
+		b.burnt = true
+	}
+}
+
+// Tests that the fee stats of a Patex block account the L1 fee, the fees
+// claimable by contracts and the base fee recipient's share, and the fee split
+// of the priority fees if the gas accounting fails, matching the balances
+// credited to the fee recipients.
+func TestBlockFeeStatsPatex(t *testing.T) {
+	var (
+		key, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender     = crypto.PubkeyToAddress(key.PublicKey)
+		contract   = common.HexToAddress("0xc0de")
+		coinbase   = common.HexToAddress("0xc0")
+		recipient1 = common.HexToAddress("0xf1")
+		recipient2 = common.HexToAddress("0xf2")
+		l1BaseFee  = big.NewInt(10 * params.GWei)
+		overhead   = big.NewInt(2100)
+		scalar     = big.NewInt(1_000_000)
+		config     = *params.TestChainConfig
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.Patex = &params.PatexConfig{
+		EIP1559Elasticity:  6,
+		EIP1559Denominator: 50,
+		FeeSplit: &params.FeeSplitConfig{
+			CoinbaseWeight: 1,
+			Recipients:     []params.FeeSplitRecipient{{Address: recipient1, Weight: 1}, {Address: recipient2, Weight: 2}},
+		},
+	}
+	header := &types.Header{Number: big.NewInt(1), Time: 1, Difficulty: common.Big0, BaseFee: big.NewInt(params.GWei), GasLimit: 10_000_000, Coinbase: coinbase}
+	tx, _ := types.SignNewTx(key, types.LatestSigner(&config), &types.DynamicFeeTx{
+		ChainID:   config.ChainID,
+		To:        &contract,
+		Gas:       100_000,
+		GasFeeCap: big.NewInt(3 * params.GWei),
+		GasTipCap: big.NewInt(2 * params.GWei),
+		Data:      []byte{0x01, 0x02, 0x03},
+	})
+	run := func(cfg vm.Config) (*types.BlockFeeStats, *types.Receipt, *state.StateDB) {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.AddBalance(sender, big.NewInt(params.Ether))
+		statedb.SetCode(contract, common.FromHex("0x600160005500")) // sstore(0, 1)
+
+		// Keep the L1 block contract from being wiped as an empty account.
+		statedb.SetNonce(types.L1BlockAddr, 1)
+		statedb.SetState(types.L1BlockAddr, types.L1BaseFeeSlot, common.BigToHash(l1BaseFee))
+		statedb.SetState(types.L1BlockAddr, types.OverheadSlot, common.BigToHash(overhead))
+		statedb.SetState(types.L1BlockAddr, types.ScalarSlot, common.BigToHash(scalar))
+
+		// Opt the contract into gas fee accumulation.
+		mode := common.Hash{}
+		mode[0] = 1
+		statedb.SetState(params.PatexGasAddress, vm.GasParametersSlot(contract), mode)
+
+		var (
+			stats   = types.NewBlockFeeStats()
+			usedGas uint64
+		)
+		receipt, err := ApplyTransactionWithFeeStats(&config, nil, &coinbase, new(GasPool).AddGas(header.GasLimit), statedb, header, tx, &usedGas, cfg, stats)
+		if err != nil {
+			t.Fatalf("failed to apply transaction: %v", err)
+		}
+		return stats, receipt, statedb
+	}
+	// Check the stats of a transaction whose gas was attributed to the contract.
+	stats, receipt, statedb := run(vm.Config{})
+	if stats.UserTxs != 1 || stats.UserGasUsed != receipt.GasUsed {
+		t.Errorf("user transaction mismatch: have %d txs using %d gas, want 1 using %d", stats.UserTxs, stats.UserGasUsed, receipt.GasUsed)
+	}
+	dataGas := tx.RollupDataGas().DataGas(header.Time, &config)
+	if stats.RollupDataGas != dataGas || stats.L1GasUsed != dataGas+overhead.Uint64() {
+		t.Errorf("L1 gas mismatch: have data gas %d and L1 gas %d, want %d and %d", stats.RollupDataGas, stats.L1GasUsed, dataGas, dataGas+overhead.Uint64())
+	}
+	if want := new(big.Int).Mul(new(big.Int).SetUint64(dataGas), l1BaseFee); stats.L1DataCost.Cmp(want) != 0 {
+		t.Errorf("L1 data cost mismatch: have %v, want %v", stats.L1DataCost, want)
+	}
+	if want := types.L1Cost(dataGas, l1BaseFee, overhead, scalar); stats.Fees.L1Fee.Cmp(want) != 0 {
+		t.Errorf("L1 fee mismatch: have %v, want %v", stats.Fees.L1Fee, want)
+	}
+	if stats.Fees.Claimable.Sign() <= 0 || stats.Fees.BaseFeeRecipient.Sign() <= 0 {
+		t.Errorf("missing contract fees: have %v claimable and %v unallocated", stats.Fees.Claimable, stats.Fees.BaseFeeRecipient)
+	}
+	if stats.Fees.FeeSplit.Sign() != 0 || stats.Fees.Coinbase.Sign() != 0 {
+		t.Errorf("priority fees split with correct accounting: have %v split and %v to the coinbase", stats.Fees.FeeSplit, stats.Fees.Coinbase)
+	}
+	price := new(big.Int).Add(header.BaseFee, tx.EffectiveGasTipValue(header.BaseFee))
+	paid := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price)
+	if total := new(big.Int).Add(stats.Fees.Claimable, stats.Fees.BaseFeeRecipient); total.Cmp(paid) != 0 {
+		t.Errorf("gas fee mismatch: have %v, want %v", total, paid)
+	}
+	balances := []struct {
+		name    string
+		address common.Address
+		fee     *big.Int
+	}{
+		{"L1 fee", params.PatexL1FeeRecipient, stats.Fees.L1Fee},
+		{"claimable", params.PatexGasAddress, stats.Fees.Claimable},
+		{"base fee recipient", params.PatexBaseFeeRecipient, stats.Fees.BaseFeeRecipient},
+	}
+	for _, b := range balances {
+		if have := statedb.GetBalance(b.address); have.Cmp(b.fee) != 0 {
+			t.Errorf("%s: balance mismatch: have %v, stats %v", b.name, have, b.fee)
+		}
+	}
+	// Check the stats of a transaction taking the canary fallback, splitting
+	// its priority fee.
+	stats, receipt, statedb = run(vm.Config{Tracer: &gasBurner{StructLogger: logger.NewStructLogger(nil)}})
+	if stats.Fees.Claimable.Sign() != 0 {
+		t.Errorf("fees claimable on fallback: have %v", stats.Fees.Claimable)
+	}
+	if stats.Fees.FeeSplit.Sign() <= 0 || stats.Fees.Coinbase.Sign() <= 0 {
+		t.Errorf("missing priority fees: have %v split and %v to the coinbase", stats.Fees.FeeSplit, stats.Fees.Coinbase)
+	}
+	paid = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price)
+	if total := new(big.Int).Add(stats.Fees.BaseFeeRecipient, stats.Fees.FeeSplit); total.Add(total, stats.Fees.Coinbase).Cmp(paid) != 0 {
+		t.Errorf("gas fee mismatch: have %v, want %v", total, paid)
+	}
+	split := new(big.Int).Add(statedb.GetBalance(recipient1), statedb.GetBalance(recipient2))
+	if split.Cmp(stats.Fees.FeeSplit) != 0 {
+		t.Errorf("fee split balance mismatch: have %v, stats %v", split, stats.Fees.FeeSplit)
+	}
+	if have := statedb.GetBalance(coinbase); have.Cmp(stats.Fees.Coinbase) != 0 {
+		t.Errorf("coinbase balance mismatch: have %v, stats %v", have, stats.Fees.Coinbase)
+	}
+	if have := statedb.GetBalance(params.PatexBaseFeeRecipient); have.Cmp(stats.Fees.BaseFeeRecipient) != 0 {
+		t.Errorf("base fee recipient balance mismatch: have %v, stats %v", have, stats.Fees.BaseFeeRecipient)
+	}
+}
+
+// Tests that deposits are indexed by their source hash as blocks are inserted,
+// that rewinding the chain removes the indexes of the dropped blocks and that
+// the blocks below the deposit index tail are indexed in the background.
+func TestDepositIndex(t *testing.T) {
+	var (
+		engine  = ethash.NewFaker()
+		address = common.HexToAddress("0xd0")
+		config  = *params.TestChainConfig
+		gspec   = &Genesis{Config: &config}
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	source := func(i int) common.Hash { return common.BigToHash(big.NewInt(int64(i + 1))) }
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 3, func(i int, b *BlockGen) {
+		b.AddTx(types.NewTx(&types.DepositTx{SourceHash: source(i), From: address, To: &address, Mint: big.NewInt(1), Value: big.NewInt(1), Gas: params.TxGas}))
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	for i, block := range blocks {
+		if tx, _, number, _ := rawdb.ReadDeposit(db, source(i)); tx == nil || number != block.NumberU64() {
+			t.Fatalf("deposit %d: lookup mismatch: have %v in block %d, want block %d", i, tx, number, block.NumberU64())
+		}
+	}
+	// Rewind the chain, the deposits of the dropped blocks must not be found.
+	chain.SetHead(1)
+	for i := range blocks {
+		if entry := rawdb.ReadDepositLookupEntry(db, source(i)); (entry != nil) != (i == 0) {
+			t.Errorf("deposit %d: lookup entry mismatch after rewind: have %v", i, entry)
+		}
+	}
+	chain.Stop()
+
+	// Pretend the remaining block was written before deposits were indexed.
+	rawdb.DeleteDepositLookupEntry(db, source(0))
+	rawdb.WriteDepositIndexTail(db, 2)
+
+	chain, err = NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen chain: %v", err)
+	}
+	defer chain.Stop()
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if tail := rawdb.ReadDepositIndexTail(db); tail != nil && *tail == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("deposit index tail not moved to genesis: %v", rawdb.ReadDepositIndexTail(db))
+		}
+	}
+	if tx, _, number, _ := rawdb.ReadDeposit(db, source(0)); tx == nil || number != 1 {
+		t.Fatalf("deposit of existing block not indexed: have %v in block %d", tx, number)
+	}
+}
@@ -18,7 +18,9 @@ package core
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -101,6 +103,27 @@ var (
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 
+	// ErrGasAllocation is returned if the fees of a transaction cannot be shared
+	// out between the contracts it touched, e.g. because the gas tracker would
+	// allocate more gas than was used. Such a transaction is invalid.
+	ErrGasAllocation = errors.New("gas fee allocation failed")
+
 	// ErrSystemTxNotSupported is returned for any deposit tx with IsSystemTx=true after the Regolith fork
 	ErrSystemTxNotSupported = errors.New("system tx not supported")
 )
+
+// TxApplyError is returned by the state processor if a transaction of a block
+// cannot be applied, identifying the offending transaction.
+type TxApplyError struct {
+	Index int         // Position of the transaction in the block
+	Hash  common.Hash // Hash of the transaction
+	Err   error       // Reason the transaction could not be applied
+}
+
+func (e *TxApplyError) Error() string {
+	return fmt.Sprintf("could not apply tx %d [%v]: %v", e.Index, e.Hash.Hex(), e.Err)
+}
+
+func (e *TxApplyError) Unwrap() error {
+	return e.Err
+}
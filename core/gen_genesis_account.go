@@ -21,7 +21,11 @@ func (g GenesisAccount) MarshalJSON() ([]byte, error) {
 		Storage    map[storageJSON]storageJSON `json:"storage,omitempty"`
 		Balance    *math.HexOrDecimal256       `json:"balance" gencodec:"required"`
 		Nonce      math.HexOrDecimal64         `json:"nonce,omitempty"`
+		Flags      math.HexOrDecimal64         `json:"flags,omitempty"`
 		PrivateKey hexutil.Bytes               `json:"secretKey,omitempty"`
+		Fixed      *math.HexOrDecimal256       `json:"fixed,omitempty"`
+		Shares     *math.HexOrDecimal256       `json:"shares,omitempty"`
+		Remainder  *math.HexOrDecimal256       `json:"remainder,omitempty"`
 	}
 	var enc GenesisAccount
 	enc.Code = g.Code
@@ -33,7 +37,11 @@ func (g GenesisAccount) MarshalJSON() ([]byte, error) {
 	}
 	enc.Balance = (*math.HexOrDecimal256)(g.Balance)
 	enc.Nonce = math.HexOrDecimal64(g.Nonce)
+	enc.Flags = math.HexOrDecimal64(g.Flags)
 	enc.PrivateKey = g.PrivateKey
+	enc.Fixed = (*math.HexOrDecimal256)(g.Fixed)
+	enc.Shares = (*math.HexOrDecimal256)(g.Shares)
+	enc.Remainder = (*math.HexOrDecimal256)(g.Remainder)
 	return json.Marshal(&enc)
 }
 
@@ -44,7 +52,11 @@ func (g *GenesisAccount) UnmarshalJSON(input []byte) error {
 		Storage    map[storageJSON]storageJSON `json:"storage,omitempty"`
 		Balance    *math.HexOrDecimal256       `json:"balance" gencodec:"required"`
 		Nonce      *math.HexOrDecimal64        `json:"nonce,omitempty"`
+		Flags      *math.HexOrDecimal64        `json:"flags,omitempty"`
 		PrivateKey *hexutil.Bytes              `json:"secretKey,omitempty"`
+		Fixed      *math.HexOrDecimal256       `json:"fixed,omitempty"`
+		Shares     *math.HexOrDecimal256       `json:"shares,omitempty"`
+		Remainder  *math.HexOrDecimal256       `json:"remainder,omitempty"`
 	}
 	var dec GenesisAccount
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -66,8 +78,20 @@ func (g *GenesisAccount) UnmarshalJSON(input []byte) error {
 	if dec.Nonce != nil {
 		g.Nonce = uint64(*dec.Nonce)
 	}
+	if dec.Flags != nil {
+		g.Flags = uint8(*dec.Flags)
+	}
 	if dec.PrivateKey != nil {
 		g.PrivateKey = *dec.PrivateKey
 	}
+	if dec.Fixed != nil {
+		g.Fixed = (*big.Int)(dec.Fixed)
+	}
+	if dec.Shares != nil {
+		g.Shares = (*big.Int)(dec.Shares)
+	}
+	if dec.Remainder != nil {
+		g.Remainder = (*big.Int)(dec.Remainder)
+	}
 	return nil
 }
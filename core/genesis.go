@@ -115,6 +115,29 @@ func (ga *GenesisAlloc) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Apply writes the accounts of the allocation into the given state. Balances
+// are set once all storage is in place, so that they are converted into yield
+// shares at the share price of the allocation, if it sets one. Accounts giving
+// their raw balance values are written as is instead, without adjusting the
+// share count of the shares predeploy.
+func (ga *GenesisAlloc) Apply(statedb *state.StateDB) {
+	for addr, account := range *ga {
+		statedb.SetCode(addr, account.Code)
+		statedb.SetNonce(addr, account.Nonce)
+		for key, value := range account.Storage {
+			statedb.SetState(addr, key, value)
+		}
+	}
+	for addr, account := range *ga {
+		if account.Fixed != nil || account.Shares != nil || account.Remainder != nil {
+			statedb.SetBalanceValues(addr, account.Flags, account.Fixed, account.Shares, account.Remainder)
+			continue
+		}
+		statedb.SetFlags(addr, account.Flags)
+		statedb.SetBalance(addr, account.Balance)
+	}
+}
+
 // deriveHash computes the state root according to the genesis specification.
 func (ga *GenesisAlloc) deriveHash() (common.Hash, error) {
 	// Create an ephemeral in-memory database for computing hash,
@@ -124,14 +147,7 @@ func (ga *GenesisAlloc) deriveHash() (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, err
 	}
-	for addr, account := range *ga {
-		statedb.AddBalance(addr, account.Balance)
-		statedb.SetCode(addr, account.Code)
-		statedb.SetNonce(addr, account.Nonce)
-		for key, value := range account.Storage {
-			statedb.SetState(addr, key, value)
-		}
-	}
+	ga.Apply(statedb)
 	return statedb.Commit(false)
 }
 
@@ -143,14 +159,7 @@ func (ga *GenesisAlloc) flush(db ethdb.Database, triedb *trie.Database, blockhas
 	if err != nil {
 		return err
 	}
-	for addr, account := range *ga {
-		statedb.AddBalance(addr, account.Balance)
-		statedb.SetCode(addr, account.Code)
-		statedb.SetNonce(addr, account.Nonce)
-		for key, value := range account.Storage {
-			statedb.SetState(addr, key, value)
-		}
-	}
+	ga.Apply(statedb)
 	root, err := statedb.Commit(false)
 	if err != nil {
 		return err
@@ -213,6 +222,12 @@ type GenesisAccount struct {
 	Nonce      uint64                      `json:"nonce,omitempty"`
 	Flags      uint8                       `json:"flags,omitempty"`
 	PrivateKey []byte                      `json:"secretKey,omitempty"` // for tests
+
+	// The raw yield-bearing balance values, for consensus tests that need an
+	// exact pre-state. If any of them is set, the balance is derived from them.
+	Fixed     *big.Int `json:"fixed,omitempty"`
+	Shares    *big.Int `json:"shares,omitempty"`
+	Remainder *big.Int `json:"remainder,omitempty"`
 }
 
 // field type overrides for gencodec
@@ -232,8 +247,12 @@ type genesisAccountMarshaling struct {
 	Code       hexutil.Bytes
 	Balance    *math.HexOrDecimal256
 	Nonce      math.HexOrDecimal64
+	Flags      math.HexOrDecimal64
 	Storage    map[storageJSON]storageJSON
 	PrivateKey hexutil.Bytes
+	Fixed      *math.HexOrDecimal256
+	Shares     *math.HexOrDecimal256
+	Remainder  *math.HexOrDecimal256
 }
 
 // storageJSON represents a 256 bit byte array, but allows less than 256 bits when
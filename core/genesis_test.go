@@ -26,6 +26,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
@@ -243,3 +245,98 @@ func TestReadWriteGenesisAlloc(t *testing.T) {
 		}
 	}
 }
+
+// Tests that yield balances of the genesis allocation are converted into shares
+// at the share price set by the allocation, regardless of the order in which
+// the accounts are allocated.
+func TestGenesisYieldShares(t *testing.T) {
+	genesis := &Genesis{
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Config:  params.TestChainConfig,
+		Alloc: GenesisAlloc{
+			{1}: {Balance: big.NewInt(10)},
+			{2}: {Balance: big.NewInt(7)},
+			{3}: {Balance: big.NewInt(5)},
+			params.PatexSharesAddress: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					state.SharePriceSlot: common.BigToHash(big.NewInt(3)),
+				},
+			},
+		},
+	}
+	want := common.HexToHash("0x125066cc90c55a185db65236472e59be2d953d453b6f16802d31475203340e44")
+
+	// Allocate repeatedly, the accounts are iterated in random order.
+	var db ethdb.Database
+	for i := 0; i < 16; i++ {
+		db = rawdb.NewMemoryDatabase()
+		if have := genesis.MustCommit(db).Hash(); have != want {
+			t.Fatalf("commit %d: genesis hash mismatch: have %x, want %x", i, have, want)
+		}
+		if have := genesis.ToBlock().Hash(); have != want {
+			t.Fatalf("derivation %d: genesis hash mismatch: have %x, want %x", i, have, want)
+		}
+	}
+	statedb, err := state.New(genesis.ToBlock().Root(), state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, want := range map[common.Address][2]int64{{1}: {3, 1}, {2}: {2, 1}, {3}: {1, 2}} {
+		values := statedb.GetBalanceValues(addr)
+		if values.Shares.Int64() != want[0] || values.Remainder.Int64() != want[1] {
+			t.Errorf("account %x: shares mismatch: have %v+%v, want %d+%d", addr, values.Shares, values.Remainder, want[0], want[1])
+		}
+		if have, want := statedb.GetBalance(addr), genesis.Alloc[addr].Balance; have.Cmp(want) != 0 {
+			t.Errorf("account %x: balance mismatch: have %v, want %v", addr, have, want)
+		}
+	}
+	if have := statedb.GetShareCount(); have.Int64() != 6 {
+		t.Errorf("share count mismatch: have %v, want 6", have)
+	}
+}
+
+// Tests that the yield mode and raw balance values of genesis accounts are
+// written into the genesis state.
+func TestGenesisBalanceValues(t *testing.T) {
+	genesis := &Genesis{
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Config:  params.TestChainConfig,
+		Alloc: GenesisAlloc{
+			{1}: {Balance: big.NewInt(7), Flags: types.YieldDisabled},
+			{2}: {Balance: new(big.Int), Shares: big.NewInt(4), Remainder: big.NewInt(1)},
+			params.PatexSharesAddress: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					state.SharePriceSlot: common.BigToHash(big.NewInt(3)),
+				},
+			},
+		},
+	}
+	db := rawdb.NewMemoryDatabase()
+	block := genesis.MustCommit(db)
+	if have := genesis.ToBlock().Hash(); have != block.Hash() {
+		t.Fatalf("genesis hash mismatch: derived %x, committed %x", have, block.Hash())
+	}
+	statedb, err := state.New(block.Root(), state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr    common.Address
+		want    state.BalanceValues
+		balance int64
+	}{
+		{common.Address{1}, state.BalanceValues{Flags: types.YieldDisabled, Fixed: big.NewInt(7), Shares: new(big.Int), Remainder: new(big.Int)}, 7},
+		{common.Address{2}, state.BalanceValues{Flags: types.YieldAutomatic, Fixed: new(big.Int), Shares: big.NewInt(4), Remainder: big.NewInt(1)}, 13},
+	}
+	for _, tt := range tests {
+		have := statedb.GetBalanceValues(tt.addr)
+		if have.Flags != tt.want.Flags || have.Fixed.Cmp(tt.want.Fixed) != 0 || have.Shares.Cmp(tt.want.Shares) != 0 || have.Remainder.Cmp(tt.want.Remainder) != 0 {
+			t.Errorf("account %x: balance values mismatch: have %+v, want %+v", tt.addr, have, tt.want)
+		}
+		if balance := statedb.GetBalance(tt.addr); balance.Int64() != tt.balance {
+			t.Errorf("account %x: balance mismatch: have %v, want %d", tt.addr, balance, tt.balance)
+		}
+	}
+}
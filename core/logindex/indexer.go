@@ -0,0 +1,336 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package logindex implements a secondary index of the logs of the canonical
+// chain, mapping log addresses and topics to the positions of the logs.
+package logindex
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// backfillBlocks is the number of historical blocks indexed before checking for
+// a new chain head.
+var backfillBlocks = 1024
+
+// Chain is the blockchain whose logs are indexed.
+type Chain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentBlock retrieves the current head block of the canonical chain.
+	CurrentBlock() *types.Header
+
+	// SubscribeChainHeadEvent subscribes to new chain head events.
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Indexer maintains the log index of a range of recent canonical blocks. New
+// blocks are indexed as they become the chain head, the blocks of reorganised
+// chains are unindexed and historical blocks are indexed in the background,
+// down to the configured limit.
+//
+// Blocks whose receipts are no longer available when they are unindexed, such
+// as ones dropped by rewinding the chain, may leave stale entries behind. These
+// only cause false positives, so the logs found by the index must be checked
+// against the canonical receipts.
+type Indexer struct {
+	db     ethdb.Database
+	chain  Chain
+	limit  uint64 // Number of recent blocks to index, all if zero
+	bottom uint64 // First block with receipts available locally
+
+	lock     sync.RWMutex            // Protects the progress, held while the index is modified
+	progress *rawdb.LogIndexProgress // Range of indexed blocks, nil if none
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a log index of the given chain, keeping the logs of the given
+// number of recent blocks indexed, or of all blocks if zero.
+func New(db ethdb.Database, chain Chain, limit uint64) *Indexer {
+	ix := &Indexer{
+		db:       db,
+		chain:    chain,
+		limit:    limit,
+		progress: rawdb.ReadLogIndexProgress(db),
+		quit:     make(chan struct{}),
+	}
+	// Pre-Bedrock blocks are served by the historical backends.
+	if config := chain.Config(); config.BedrockBlock != nil {
+		ix.bottom = config.BedrockBlock.Uint64()
+	}
+	if ix.progress != nil {
+		log.Info("Loaded log index", "tail", ix.progress.Tail, "head", ix.progress.Head, "limit", limit)
+	}
+	ix.wg.Add(1)
+	go ix.loop()
+	return ix
+}
+
+// Close stops maintaining the log index.
+func (ix *Indexer) Close() {
+	close(ix.quit)
+	ix.wg.Wait()
+}
+
+// Range returns the first and last block covered by the log index, or false if
+// no blocks are indexed.
+func (ix *Indexer) Range() (uint64, uint64, bool) {
+	ix.lock.RLock()
+	defer ix.lock.RUnlock()
+
+	if ix.progress == nil {
+		return 0, 0, false
+	}
+	return ix.progress.Tail, ix.progress.Head, true
+}
+
+// loop keeps the log index in sync with the canonical chain. The indexing runs
+// in its own goroutine, so the chain head events are always consumed and block
+// import is never held up by the indexer. Heads arriving meanwhile are merged,
+// only the latest one is followed.
+func (ix *Indexer) loop() {
+	defer ix.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := ix.chain.SubscribeChainHeadEvent(headCh)
+	if sub == nil {
+		return
+	}
+	defer sub.Unsubscribe()
+
+	var (
+		head    *types.Header // Chain head followed by the last indexing run
+		pending *types.Header // Latest chain head arrived while indexing, nil if none
+		done    chan bool     // Result of the running indexing, nil if idle
+	)
+	run := func(header *types.Header) {
+		head, done = header, make(chan bool, 1)
+		go func(done chan bool) {
+			done <- ix.index(header)
+		}(done)
+	}
+	defer func() {
+		if done != nil {
+			<-done
+		}
+	}()
+	run(ix.chain.CurrentBlock())
+	for {
+		select {
+		case ev := <-headCh:
+			if done != nil {
+				pending = ev.Block.Header()
+			} else {
+				run(ev.Block.Header())
+			}
+		case finished := <-done:
+			done = nil
+			if pending != nil {
+				run(pending)
+				pending = nil
+			} else if !finished {
+				// Keep indexing historical blocks in the background.
+				run(head)
+			}
+		case <-sub.Err():
+			return
+		case <-ix.quit:
+			return
+		}
+	}
+}
+
+// index follows the given chain head and indexes a number of historical blocks.
+// It returns true if there are no more historical blocks to be indexed, or if
+// the indexer was closed meanwhile.
+func (ix *Indexer) index(head *types.Header) bool {
+	if !ix.follow(head) {
+		return true
+	}
+	return ix.backfill()
+}
+
+// blockLogs returns the logs of every transaction of a block, or false if its
+// receipts are not available.
+func (ix *Indexer) blockLogs(hash common.Hash, number uint64) ([][]*types.Log, bool) {
+	receipts := rawdb.ReadRawReceipts(ix.db, hash, number)
+	if receipts == nil {
+		return nil, false
+	}
+	logs := make([][]*types.Log, len(receipts))
+	for i, receipt := range receipts {
+		logs[i] = receipt.Logs
+	}
+	return logs, true
+}
+
+// commit writes the batch along with the new progress of the log index, if the
+// batch is large enough or if forced.
+func (ix *Indexer) commit(batch ethdb.Batch, progress *rawdb.LogIndexProgress, force bool) {
+	if !force && batch.ValueSize() < ethdb.IdealBatchSize {
+		return
+	}
+	if batch.ValueSize() == 0 && progress == ix.progress {
+		return
+	}
+	if progress != nil {
+		rawdb.WriteLogIndexProgress(batch, progress)
+	} else {
+		rawdb.DeleteLogIndexProgress(batch)
+	}
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write log index", "err", err)
+	}
+	batch.Reset()
+	ix.progress = progress
+}
+
+// follow unindexes the blocks no longer canonical, indexes the blocks up to the
+// given chain head and unindexes the blocks beyond the limit. It returns false
+// if the indexer was closed meanwhile.
+func (ix *Indexer) follow(head *types.Header) bool {
+	var (
+		batch  = ix.db.NewBatch()
+		number = head.Number.Uint64()
+	)
+	ix.lock.RLock()
+	progress := ix.progress
+	ix.lock.RUnlock()
+
+	// Unindex the blocks of a reorganised chain back to the common ancestor.
+	for progress != nil && (progress.Head > number || rawdb.ReadCanonicalHash(ix.db, progress.Head) != progress.HeadHash) {
+		if logs, ok := ix.blockLogs(progress.HeadHash, progress.Head); ok {
+			rawdb.DeleteLogIndexBlock(batch, progress.Head, logs)
+		}
+		header := rawdb.ReadHeader(ix.db, progress.HeadHash, progress.Head)
+		if header == nil || progress.Head == progress.Tail {
+			progress = nil
+		} else {
+			progress = &rawdb.LogIndexProgress{Tail: progress.Tail, Head: progress.Head - 1, HeadHash: header.ParentHash}
+		}
+		ix.commit(batch, progress, false)
+	}
+	// Index the new canonical blocks. An empty index is started from the head,
+	// the historical blocks are indexed in the background.
+	next := number
+	if progress != nil {
+		next = progress.Head + 1
+	}
+	for ; next <= number; next++ {
+		select {
+		case <-ix.quit:
+			ix.commit(batch, progress, true)
+			return false
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(ix.db, next)
+		if progress != nil {
+			// Stop if the chain was reorganised meanwhile, the new head
+			// event will follow.
+			header := rawdb.ReadHeader(ix.db, hash, next)
+			if header == nil || header.ParentHash != progress.HeadHash {
+				break
+			}
+		}
+		logs, ok := ix.blockLogs(hash, next)
+		if !ok {
+			break
+		}
+		rawdb.WriteLogIndexBlock(batch, next, logs)
+
+		tail := next
+		if progress != nil {
+			tail = progress.Tail
+		}
+		progress = &rawdb.LogIndexProgress{Tail: tail, Head: next, HeadHash: hash}
+		ix.commit(batch, progress, false)
+	}
+	// Unindex the blocks beyond the limit.
+	if progress != nil && ix.limit != 0 && progress.Head >= ix.limit {
+		for tail := progress.Head - ix.limit + 1; progress.Tail < tail; {
+			hash := rawdb.ReadCanonicalHash(ix.db, progress.Tail)
+			if logs, ok := ix.blockLogs(hash, progress.Tail); ok {
+				rawdb.DeleteLogIndexBlock(batch, progress.Tail, logs)
+			}
+			progress = &rawdb.LogIndexProgress{Tail: progress.Tail + 1, Head: progress.Head, HeadHash: progress.HeadHash}
+			ix.commit(batch, progress, false)
+		}
+	}
+	ix.commit(batch, progress, true)
+	return true
+}
+
+// backfill indexes a number of historical blocks below the indexed range. It
+// returns true if there are no more blocks to be indexed.
+func (ix *Indexer) backfill() bool {
+	ix.lock.RLock()
+	progress := ix.progress
+	ix.lock.RUnlock()
+
+	if progress == nil {
+		return true
+	}
+	target := ix.bottom
+	if ix.limit != 0 && progress.Head >= ix.limit && progress.Head-ix.limit+1 > target {
+		target = progress.Head - ix.limit + 1
+	}
+	if progress.Tail <= target {
+		return true
+	}
+	var (
+		start = time.Now()
+		batch = ix.db.NewBatch()
+	)
+	for i := 0; i < backfillBlocks && progress.Tail > target; i++ {
+		number := progress.Tail - 1
+		hash := rawdb.ReadCanonicalHash(ix.db, number)
+		logs, ok := ix.blockLogs(hash, number)
+		if !ok {
+			// The receipts of older blocks are not available, e.g. on a
+			// snap synced node, stop indexing them.
+			log.Info("Log index reached the first block with receipts", "number", progress.Tail)
+			ix.bottom = progress.Tail
+			break
+		}
+		rawdb.WriteLogIndexBlock(batch, number, logs)
+		progress = &rawdb.LogIndexProgress{Tail: number, Head: progress.Head, HeadHash: progress.HeadHash}
+		ix.commit(batch, progress, false)
+	}
+	ix.commit(batch, progress, true)
+
+	if progress.Tail <= target || ix.bottom == progress.Tail {
+		log.Info("Indexed historical logs", "tail", progress.Tail, "head", progress.Head)
+		return true
+	}
+	log.Debug("Indexing historical logs", "tail", progress.Tail, "target", target, "elapsed", common.PrettyDuration(time.Since(start)))
+	return false
+}
@@ -0,0 +1,259 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+
+	// The emitters log their call data as the only topic of a log.
+	emitter1    = common.HexToAddress("0x1111")
+	emitter2    = common.HexToAddress("0x2222")
+	emitterCode = common.FromHex("60003560006000a100")
+
+	testGenesis = &core.Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			testAddr: {Balance: big.NewInt(params.Ether)},
+			emitter1: {Balance: new(big.Int), Code: emitterCode},
+			emitter2: {Balance: new(big.Int), Code: emitterCode},
+		},
+	}
+)
+
+// topic returns the test topic of the given number.
+func topic(n int) common.Hash {
+	return common.BigToHash(big.NewInt(int64(n)))
+}
+
+// emit returns a block generator making the emitters log the topics returned by
+// the given function for every block.
+func emit(topics func(i int) (int, int)) func(int, *core.BlockGen) {
+	return func(i int, gen *core.BlockGen) {
+		signer := types.LatestSigner(testGenesis.Config)
+		topic1, topic2 := topics(i)
+		for _, call := range []struct {
+			to    common.Address
+			topic int
+		}{{emitter1, topic1}, {emitter2, topic2}} {
+			tx, _ := types.SignNewTx(testKey, signer, &types.LegacyTx{
+				Nonce:    gen.TxNonce(testAddr),
+				To:       &call.to,
+				Gas:      100000,
+				GasPrice: gen.BaseFee(),
+				Data:     topic(call.topic).Bytes(),
+			})
+			gen.AddTx(tx)
+		}
+	}
+}
+
+// newTestChain creates a blockchain of the given number of blocks, each of which
+// contains a log of the first emitter with the topic of the parity of the block
+// number, and a log of the second emitter with the topic of the block number.
+func newTestChain(t *testing.T, n int) (*core.BlockChain, ethdb.Database, ethdb.Database, []*types.Block) {
+	genDb, blocks, _ := core.GenerateChainWithGenesis(testGenesis, ethash.NewFaker(), n, emit(func(i int) (int, int) {
+		return (i + 1) % 2, i + 1
+	}))
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, nil, testGenesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	return chain, db, genDb, blocks
+}
+
+// waitIndexed waits until the log index covers exactly the given range.
+func waitIndexed(t *testing.T, ix *Indexer, tail, head uint64) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if have, haveHead, ok := ix.Range(); ok && have == tail && haveHead == head {
+			return
+		}
+	}
+	have, haveHead, ok := ix.Range()
+	t.Fatalf("log index range mismatch: have %d-%d (%t), want %d-%d", have, haveHead, ok, tail, head)
+}
+
+// lookupBlocks returns the numbers of the blocks containing matching logs.
+func lookupBlocks(t *testing.T, ix *Indexer, from, to uint64, addresses []common.Address, topics [][]common.Hash) []uint64 {
+	t.Helper()
+
+	positions, err := ix.Lookup(context.Background(), from, to, addresses, topics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blocks []uint64
+	for _, pos := range positions {
+		blocks = append(blocks, pos.Block)
+	}
+	return blocks
+}
+
+func TestIndexer(t *testing.T) {
+	chain, db, genDb, blocks := newTestChain(t, 8)
+	defer chain.Stop()
+
+	ix := New(db, chain, 0)
+	defer ix.Close()
+	waitIndexed(t, ix, 0, 8)
+
+	tests := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+		from, to  uint64
+		want      []uint64
+	}{
+		{[]common.Address{emitter1}, nil, 0, 8, []uint64{1, 2, 3, 4, 5, 6, 7, 8}},
+		{[]common.Address{emitter1}, [][]common.Hash{{topic(1)}}, 0, 8, []uint64{1, 3, 5, 7}},
+		{[]common.Address{emitter1}, [][]common.Hash{{topic(1)}}, 2, 6, []uint64{3, 5}},
+		{[]common.Address{emitter1, emitter2}, [][]common.Hash{{topic(2), topic(4)}}, 0, 8, []uint64{2, 4}},
+		{nil, [][]common.Hash{{topic(0), topic(3)}}, 0, 8, []uint64{2, 3, 4, 6, 8}},
+		{[]common.Address{emitter2}, [][]common.Hash{{topic(0)}}, 0, 8, nil},
+		{nil, [][]common.Hash{{}, {topic(1)}}, 0, 8, nil},
+	}
+	for i, tt := range tests {
+		if have := lookupBlocks(t, ix, tt.from, tt.to, tt.addresses, tt.topics); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: have %v, want %v", i, have, tt.want)
+		}
+	}
+	if _, err := ix.Lookup(context.Background(), 0, 8, nil, [][]common.Hash{{}}); err != ErrNoCriteria {
+		t.Errorf("lookup without criteria: have %v, want %v", err, ErrNoCriteria)
+	}
+	// Reorganise the chain from block 4, the new blocks making the first
+	// emitter log topic 100.
+	fork, _ := core.GenerateChain(testGenesis.Config, blocks[3], ethash.NewFaker(), genDb, 6, emit(func(i int) (int, int) {
+		return 100, i + 5
+	}))
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatal(err)
+	}
+	waitIndexed(t, ix, 0, 10)
+
+	// Both emitters log topic 1 in block 1.
+	if have, want := lookupBlocks(t, ix, 0, 10, nil, [][]common.Hash{{topic(1)}}), []uint64{1, 1, 3}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs of reorganised chain: have %v, want %v", have, want)
+	}
+	if have, want := lookupBlocks(t, ix, 0, 10, nil, [][]common.Hash{{topic(100)}}), []uint64{5, 6, 7, 8, 9, 10}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs of new chain: have %v, want %v", have, want)
+	}
+	if have, want := lookupBlocks(t, ix, 0, 10, []common.Address{emitter2}, [][]common.Hash{{topic(5)}}), []uint64{5}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs of new chain: have %v, want %v", have, want)
+	}
+}
+
+func TestIndexerLimit(t *testing.T) {
+	chain, db, genDb, blocks := newTestChain(t, 8)
+	defer chain.Stop()
+
+	ix := New(db, chain, 3)
+	waitIndexed(t, ix, 6, 8)
+
+	if have, want := lookupBlocks(t, ix, 0, 8, []common.Address{emitter1}, nil), []uint64{6, 7, 8}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs beyond the limit: have %v, want %v", have, want)
+	}
+	// Extend the chain, the oldest blocks have to be unindexed.
+	more, _ := core.GenerateChain(testGenesis.Config, blocks[7], ethash.NewFaker(), genDb, 2, emit(func(i int) (int, int) {
+		return 0, 0
+	}))
+	if _, err := chain.InsertChain(more); err != nil {
+		t.Fatal(err)
+	}
+	waitIndexed(t, ix, 8, 10)
+
+	if have, want := lookupBlocks(t, ix, 0, 10, []common.Address{emitter1}, nil), []uint64{8, 9, 10}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs beyond the limit: have %v, want %v", have, want)
+	}
+	ix.Close()
+
+	// Restart without a limit, the historical blocks have to be indexed.
+	ix = New(db, chain, 0)
+	defer ix.Close()
+	waitIndexed(t, ix, 0, 10)
+
+	if have, want := lookupBlocks(t, ix, 0, 10, []common.Address{emitter2}, [][]common.Hash{{topic(2)}}), []uint64{2}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs of historical blocks: have %v, want %v", have, want)
+	}
+}
+
+// Tests that blocks can be imported while the indexer is busy backfilling, the
+// chain head events being consumed regardless of the indexing progress.
+func TestIndexerBackfillImport(t *testing.T) {
+	defer func(n int) { backfillBlocks = n }(backfillBlocks)
+	backfillBlocks = 1
+
+	chain, db, genDb, blocks := newTestChain(t, 8)
+	defer chain.Stop()
+
+	ix := New(db, chain, 0)
+	defer ix.Close()
+
+	// Hold up the indexing by blocking its writes, and import blocks one by one,
+	// each of them announcing a new chain head.
+	more, _ := core.GenerateChain(testGenesis.Config, blocks[7], ethash.NewFaker(), genDb, 4, emit(func(i int) (int, int) {
+		return 0, i + 9
+	}))
+	ix.lock.Lock()
+	done := make(chan error, 1)
+	go func() {
+		for _, block := range more {
+			if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		ix.lock.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		ix.lock.Unlock()
+		t.Fatal("block import stalled by the log indexer")
+	}
+	waitIndexed(t, ix, 0, 12)
+
+	if have, want := lookupBlocks(t, ix, 0, 12, []common.Address{emitter2}, [][]common.Hash{{topic(3), topic(11)}}), []uint64{3, 11}; !reflect.DeepEqual(have, want) {
+		t.Errorf("logs of imported and historical blocks: have %v, want %v", have, want)
+	}
+}
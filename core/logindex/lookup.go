@@ -0,0 +1,173 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// ErrNoCriteria is returned when looking up logs without restricting either
+// their addresses or any of their topics.
+var ErrNoCriteria = errors.New("no log criteria")
+
+// Position is the position of a log found in the log index.
+type Position struct {
+	Block uint64 // Number of the block containing the log
+	rawdb.LogIndexEntry
+}
+
+// less orders the positions by block and log index.
+func (p Position) less(other Position) bool {
+	if p.Block != other.Block {
+		return p.Block < other.Block
+	}
+	return p.LogIndex < other.LogIndex
+}
+
+// Selective reports whether the given filter criteria restrict the addresses or
+// any of the topics of the logs, which is required to look them up.
+func Selective(addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		return true
+	}
+	for _, sub := range topics {
+		if len(sub) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the positions of the logs in the blocks [from, to] matching the
+// given filter criteria, ordered by block and log index. The criteria are the
+// same as those of eth_getLogs: a log has to be emitted by any of the addresses
+// and has to have any of the given topics at each position.
+//
+// The range should be within the range of indexed blocks, the logs of other
+// blocks are not found.
+func (ix *Indexer) Lookup(ctx context.Context, from, to uint64, addresses []common.Address, topics [][]common.Hash) ([]Position, error) {
+	// Gather the terms of every criterion, any of which have to match.
+	var clauses [][][]byte
+	if len(addresses) > 0 {
+		clause := make([][]byte, 0, len(addresses))
+		for _, address := range dedup(addresses) {
+			clause = append(clause, rawdb.LogAddressTerm(address))
+		}
+		clauses = append(clauses, clause)
+	}
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			continue // wildcard
+		}
+		clause := make([][]byte, 0, len(sub))
+		for _, topic := range dedup(sub) {
+			clause = append(clause, rawdb.LogTopicTerm(i, topic))
+		}
+		clauses = append(clauses, clause)
+	}
+	if len(clauses) == 0 {
+		return nil, ErrNoCriteria
+	}
+	// Intersect the positions matching the criteria, narrowing down the range
+	// of blocks to the ones matching all previous criteria.
+	var positions []Position
+	for i, clause := range clauses {
+		matches, err := ix.lookupClause(ctx, clause, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			positions = matches
+		} else {
+			positions = intersect(positions, matches)
+		}
+		if len(positions) == 0 {
+			return nil, nil
+		}
+		from, to = positions[0].Block, positions[len(positions)-1].Block
+	}
+	return positions, nil
+}
+
+// lookupClause returns the positions of the logs in the blocks [from, to]
+// matching any of the given terms, ordered by block and log index.
+func (ix *Indexer) lookupClause(ctx context.Context, terms [][]byte, from, to uint64) ([]Position, error) {
+	var (
+		positions []Position
+		err       error
+	)
+	for _, term := range terms {
+		iterErr := rawdb.IterateLogIndex(ix.db, term, from, to, func(number uint64, entries []rawdb.LogIndexEntry) bool {
+			if err = ctx.Err(); err != nil {
+				return false
+			}
+			for _, entry := range entries {
+				positions = append(positions, Position{Block: number, LogIndexEntry: entry})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if iterErr != nil {
+			return nil, iterErr
+		}
+	}
+	// The terms of a clause are the alternative values of the same field, so a
+	// log matches at most one of them.
+	if len(terms) > 1 {
+		sort.Slice(positions, func(i, j int) bool { return positions[i].less(positions[j]) })
+	}
+	return positions, nil
+}
+
+// intersect returns the positions present in both of the ordered lists.
+func intersect(a, b []Position) []Position {
+	var result []Position
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0].less(b[0]):
+			a = a[1:]
+		case b[0].less(a[0]):
+			b = b[1:]
+		default:
+			result = append(result, a[0])
+			a, b = a[1:], b[1:]
+		}
+	}
+	return result
+}
+
+// dedup returns the distinct items of the list.
+func dedup[T comparable](items []T) []T {
+	var (
+		seen   = make(map[T]struct{}, len(items))
+		unique = make([]T, 0, len(items))
+	)
+	for _, item := range items {
+		if _, ok := seen[item]; !ok {
+			seen[item] = struct{}{}
+			unique = append(unique, item)
+		}
+	}
+	return unique
+}
@@ -277,6 +277,25 @@ func WriteTxIndexTail(db ethdb.KeyValueWriter, number uint64) {
 	}
 }
 
+// ReadDepositIndexTail retrieves the number of oldest indexed block
+// whose deposit indices has been indexed.
+func ReadDepositIndexTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(depositIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteDepositIndexTail stores the number of oldest indexed block
+// into database.
+func WriteDepositIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(depositIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the deposit index tail", "err", err)
+	}
+}
+
 // ReadFastTxLookupLimit retrieves the tx lookup limit used in fast sync.
 func ReadFastTxLookupLimit(db ethdb.KeyValueReader) *uint64 {
 	data, _ := db.Get(fastTxLookupLimitKey)
@@ -672,6 +691,39 @@ func DeleteReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadBlockFeeStats retrieves the fee stats of a block, as computed when the
+// block was processed.
+func ReadBlockFeeStats(db ethdb.Reader, hash common.Hash, number uint64) *types.BlockFeeStats {
+	data, _ := db.Get(blockFeeStatsKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	stats := new(types.BlockFeeStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		log.Error("Invalid block fee stats RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return stats
+}
+
+// WriteBlockFeeStats stores the fee stats of a block.
+func WriteBlockFeeStats(db ethdb.KeyValueWriter, hash common.Hash, number uint64, stats *types.BlockFeeStats) {
+	data, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		log.Crit("Failed to encode block fee stats", "err", err)
+	}
+	if err := db.Put(blockFeeStatsKey(number, hash), data); err != nil {
+		log.Crit("Failed to store block fee stats", "err", err)
+	}
+}
+
+// DeleteBlockFeeStats removes the fee stats of a block.
+func DeleteBlockFeeStats(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockFeeStatsKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block fee stats", "err", err)
+	}
+}
+
 // storedReceiptRLP is the storage encoding of a receipt.
 // Re-definition in core/types/receipt.go.
 // TODO: Re-use the existing definition.
@@ -822,6 +874,7 @@ func writeAncientBlock(op ethdb.AncientWriteOp, block *types.Block, header *type
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
+	DeleteBlockFeeStats(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 	DeleteTd(db, hash, number)
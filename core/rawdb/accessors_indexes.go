@@ -18,6 +18,8 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -93,6 +95,63 @@ func DeleteTxLookupEntries(db ethdb.KeyValueWriter, hashes []common.Hash) {
 	}
 }
 
+// ReadDepositLookupEntry retrieves the number of the block containing the
+// deposit transaction with the given source hash.
+func ReadDepositLookupEntry(db ethdb.Reader, sourceHash common.Hash) *uint64 {
+	data, _ := db.Get(depositLookupKey(sourceHash))
+	if len(data) == 0 {
+		return nil
+	}
+	number := new(big.Int).SetBytes(data).Uint64()
+	return &number
+}
+
+// WriteDepositLookupEntriesByBlock stores the block number for every deposit
+// transaction of a block, enabling source hash based deposit lookups.
+func WriteDepositLookupEntriesByBlock(db ethdb.KeyValueWriter, block *types.Block) {
+	numberBytes := block.Number().Bytes()
+	for _, tx := range block.Transactions() {
+		if !tx.IsDepositTx() {
+			continue
+		}
+		if err := db.Put(depositLookupKey(tx.SourceHash()), numberBytes); err != nil {
+			log.Crit("Failed to store deposit lookup entry", "err", err)
+		}
+	}
+}
+
+// DeleteDepositLookupEntry removes the lookup metadata of a deposit.
+func DeleteDepositLookupEntry(db ethdb.KeyValueWriter, sourceHash common.Hash) {
+	if err := db.Delete(depositLookupKey(sourceHash)); err != nil {
+		log.Crit("Failed to delete deposit lookup entry", "err", err)
+	}
+}
+
+// ReadDeposit retrieves the deposit transaction with the given source hash from
+// the database, along with its added positional metadata.
+func ReadDeposit(db ethdb.Reader, sourceHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
+	blockNumber := ReadDepositLookupEntry(db, sourceHash)
+	if blockNumber == nil {
+		return nil, common.Hash{}, 0, 0
+	}
+	blockHash := ReadCanonicalHash(db, *blockNumber)
+	if blockHash == (common.Hash{}) {
+		return nil, common.Hash{}, 0, 0
+	}
+	body := ReadBody(db, blockHash, *blockNumber)
+	if body == nil {
+		log.Error("Deposit referenced missing", "number", *blockNumber, "hash", blockHash)
+		return nil, common.Hash{}, 0, 0
+	}
+	for txIndex, tx := range body.Transactions {
+		if tx.IsDepositTx() && tx.SourceHash() == sourceHash {
+			return tx, blockHash, *blockNumber, uint64(txIndex)
+		}
+	}
+	log.Error("Deposit not found", "number", *blockNumber, "hash", blockHash, "source", sourceHash)
+	return nil, common.Hash{}, 0, 0
+}
+
 // ReadTransaction retrieves a specific transaction from the database, along with
 // its added positional metadata.
 func ReadTransaction(db ethdb.Reader, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
@@ -175,3 +234,149 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// Log index terms are the address of the logs or one of their topics, preceded
+// by the kind of the term. Topics are indexed by their position in the log.
+const (
+	logAddressTerm byte = iota
+	logTopicTerm        // logTopicTerm + position
+)
+
+// LogAddressTerm returns the log index term of the logs emitted by the address.
+func LogAddressTerm(address common.Address) []byte {
+	return append([]byte{logAddressTerm}, address.Bytes()...)
+}
+
+// LogTopicTerm returns the log index term of the logs having the given topic at
+// the given position.
+func LogTopicTerm(position int, topic common.Hash) []byte {
+	return append([]byte{logTopicTerm + byte(position)}, topic.Bytes()...)
+}
+
+// LogIndexEntry is the position of a log within its block.
+type LogIndexEntry struct {
+	TxIndex  uint32 // Index of the transaction emitting the log
+	LogIndex uint32 // Index of the log in the block
+}
+
+// logIndexEntrySize is the size of an encoded log index entry.
+const logIndexEntrySize = 8
+
+// logIndexBlock groups the positions of the logs of a block by their terms. The
+// logs are given per transaction.
+func logIndexBlock(logs [][]*types.Log) map[string][]byte {
+	var (
+		terms = make(map[string][]byte)
+		index uint32
+		enc   [logIndexEntrySize]byte
+	)
+	for txIndex, txLogs := range logs {
+		for _, l := range txLogs {
+			binary.BigEndian.PutUint32(enc[:4], uint32(txIndex))
+			binary.BigEndian.PutUint32(enc[4:], index)
+
+			term := string(LogAddressTerm(l.Address))
+			terms[term] = append(terms[term], enc[:]...)
+			for i, topic := range l.Topics {
+				term := string(LogTopicTerm(i, topic))
+				terms[term] = append(terms[term], enc[:]...)
+			}
+			index++
+		}
+	}
+	return terms
+}
+
+// WriteLogIndexBlock stores the log index entries of all logs of a block. The
+// logs are given per transaction.
+func WriteLogIndexBlock(db ethdb.KeyValueWriter, number uint64, logs [][]*types.Log) {
+	for term, entries := range logIndexBlock(logs) {
+		if err := db.Put(logIndexKey([]byte(term), number), entries); err != nil {
+			log.Crit("Failed to store log index entries", "err", err)
+		}
+	}
+}
+
+// DeleteLogIndexBlock removes the log index entries of all logs of a block. The
+// logs are given per transaction.
+func DeleteLogIndexBlock(db ethdb.KeyValueWriter, number uint64, logs [][]*types.Log) {
+	for term := range logIndexBlock(logs) {
+		if err := db.Delete(logIndexKey([]byte(term), number)); err != nil {
+			log.Crit("Failed to delete log index entries", "err", err)
+		}
+	}
+}
+
+// IterateLogIndex calls fn with the log index entries of the given term for every
+// block in the range [from, to] containing matching logs, in ascending order of
+// the block numbers, until fn returns false.
+func IterateLogIndex(db ethdb.Iteratee, term []byte, from, to uint64, fn func(number uint64, entries []LogIndexEntry) bool) error {
+	prefix := append(append([]byte{}, logIndexPrefix...), term...)
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		value := it.Value()
+		if len(value)%logIndexEntrySize != 0 {
+			return fmt.Errorf("invalid log index entries of block %d: %d bytes", number, len(value))
+		}
+		entries := make([]LogIndexEntry, len(value)/logIndexEntrySize)
+		for i := range entries {
+			entries[i].TxIndex = binary.BigEndian.Uint32(value[i*logIndexEntrySize:])
+			entries[i].LogIndex = binary.BigEndian.Uint32(value[i*logIndexEntrySize+4:])
+		}
+		if !fn(number, entries) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+// LogIndexProgress is the range of canonical blocks whose logs are indexed.
+type LogIndexProgress struct {
+	Tail     uint64      // First indexed block
+	Head     uint64      // Last indexed block
+	HeadHash common.Hash // Hash of the last indexed block
+}
+
+// ReadLogIndexProgress retrieves the range of blocks covered by the log index,
+// nil if there is no log index.
+func ReadLogIndexProgress(db ethdb.KeyValueReader) *LogIndexProgress {
+	data, _ := db.Get(logIndexProgressKey)
+	if len(data) == 0 {
+		return nil
+	}
+	progress := new(LogIndexProgress)
+	if err := rlp.DecodeBytes(data, progress); err != nil {
+		log.Error("Invalid log index progress", "err", err)
+		return nil
+	}
+	return progress
+}
+
+// WriteLogIndexProgress stores the range of blocks covered by the log index.
+func WriteLogIndexProgress(db ethdb.KeyValueWriter, progress *LogIndexProgress) {
+	data, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		log.Crit("Failed to encode log index progress", "err", err)
+	}
+	if err := db.Put(logIndexProgressKey, data); err != nil {
+		log.Crit("Failed to store log index progress", "err", err)
+	}
+}
+
+// DeleteLogIndexProgress removes the range of blocks covered by the log index,
+// marking the log index as empty.
+func DeleteLogIndexProgress(db ethdb.KeyValueWriter) {
+	if err := db.Delete(logIndexProgressKey); err != nil {
+		log.Crit("Failed to delete log index progress", "err", err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"hash"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -136,6 +137,43 @@ func TestLookupStorage(t *testing.T) {
 	}
 }
 
+// Tests that deposits can be looked up by their source hash.
+func TestDepositLookupStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	deposit := types.NewTx(&types.DepositTx{
+		SourceHash: common.HexToHash("0x5ec"),
+		From:       common.BytesToAddress([]byte{0x11}),
+		Mint:       big.NewInt(100),
+		Value:      big.NewInt(100),
+		Gas:        21000,
+	})
+	tx := types.NewTransaction(1, common.BytesToAddress([]byte{0x22}), big.NewInt(222), 2222, big.NewInt(22222), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(314)}, []*types.Transaction{deposit, tx}, nil, nil, newHasher())
+
+	if txn, _, _, _ := ReadDeposit(db, deposit.SourceHash()); txn != nil {
+		t.Fatalf("non existent deposit returned: %v", txn)
+	}
+	WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	WriteBlock(db, block)
+	WriteDepositLookupEntriesByBlock(db, block)
+
+	txn, hash, number, index := ReadDeposit(db, deposit.SourceHash())
+	if txn == nil || txn.Hash() != deposit.Hash() {
+		t.Fatalf("deposit mismatch: have %v, want %v", txn, deposit)
+	}
+	if hash != block.Hash() || number != block.NumberU64() || index != 0 {
+		t.Fatalf("positional metadata mismatch: have %x/%d/%d, want %x/%d/0", hash, number, index, block.Hash(), block.NumberU64())
+	}
+	if entry := ReadDepositLookupEntry(db, common.Hash{}); entry != nil {
+		t.Fatalf("regular transaction indexed as deposit")
+	}
+	DeleteDepositLookupEntry(db, deposit.SourceHash())
+	if txn, _, _, _ := ReadDeposit(db, deposit.SourceHash()); txn != nil {
+		t.Fatalf("deleted deposit returned: %v", txn)
+	}
+}
+
 func TestDeleteBloomBits(t *testing.T) {
 	// Prepare testing data
 	db := NewMemoryDatabase()
@@ -178,3 +216,67 @@ func TestDeleteBloomBits(t *testing.T) {
 	check(1, 1, params.MainnetGenesisHash, true)
 	check(1, 1, params.RinkebyGenesisHash, true)
 }
+
+func TestLogIndexStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		addr1, addr2   = common.BytesToAddress([]byte{0x11}), common.BytesToAddress([]byte{0x22})
+		topic1, topic2 = common.HexToHash("0x01"), common.HexToHash("0x02")
+	)
+	// Two transactions, the first emitting two logs.
+	logs := [][]*types.Log{
+		{{Address: addr1, Topics: []common.Hash{topic1}}, {Address: addr2, Topics: []common.Hash{topic2, topic1}}},
+		{},
+		{{Address: addr1, Topics: []common.Hash{topic2}}},
+	}
+	WriteLogIndexBlock(db, 5, logs)
+	WriteLogIndexBlock(db, 7, logs[2:])
+
+	lookup := func(term []byte, from, to uint64) map[uint64][]LogIndexEntry {
+		found := make(map[uint64][]LogIndexEntry)
+		if err := IterateLogIndex(db, term, from, to, func(number uint64, entries []LogIndexEntry) bool {
+			found[number] = entries
+			return true
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return found
+	}
+	tests := []struct {
+		term     []byte
+		from, to uint64
+		want     map[uint64][]LogIndexEntry
+	}{
+		{LogAddressTerm(addr1), 0, 10, map[uint64][]LogIndexEntry{5: {{0, 0}, {2, 2}}, 7: {{0, 0}}}},
+		{LogAddressTerm(addr1), 6, 10, map[uint64][]LogIndexEntry{7: {{0, 0}}}},
+		{LogAddressTerm(addr1), 0, 6, map[uint64][]LogIndexEntry{5: {{0, 0}, {2, 2}}}},
+		{LogAddressTerm(addr2), 0, 10, map[uint64][]LogIndexEntry{5: {{0, 1}}}},
+		{LogTopicTerm(0, topic1), 0, 10, map[uint64][]LogIndexEntry{5: {{0, 0}}}},
+		{LogTopicTerm(1, topic1), 0, 10, map[uint64][]LogIndexEntry{5: {{0, 1}}}},
+		{LogTopicTerm(0, topic2), 0, 10, map[uint64][]LogIndexEntry{5: {{0, 1}, {2, 2}}, 7: {{0, 0}}}},
+		{LogTopicTerm(1, topic2), 0, 10, map[uint64][]LogIndexEntry{}},
+	}
+	for i, tt := range tests {
+		if have := lookup(tt.term, tt.from, tt.to); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: have %v, want %v", i, have, tt.want)
+		}
+	}
+	DeleteLogIndexBlock(db, 5, logs)
+	if have := lookup(LogAddressTerm(addr1), 0, 10); len(have) != 1 || have[7] == nil {
+		t.Errorf("deleted entries returned: %v", have)
+	}
+	// Check the progress marker.
+	if progress := ReadLogIndexProgress(db); progress != nil {
+		t.Fatalf("progress of empty index: %v", progress)
+	}
+	want := &LogIndexProgress{Tail: 5, Head: 7, HeadHash: common.Hash{7}}
+	WriteLogIndexProgress(db, want)
+	if have := ReadLogIndexProgress(db); !reflect.DeepEqual(have, want) {
+		t.Fatalf("progress mismatch: have %v, want %v", have, want)
+	}
+	DeleteLogIndexProgress(db)
+	if progress := ReadLogIndexProgress(db); progress != nil {
+		t.Fatalf("deleted progress returned: %v", progress)
+	}
+}
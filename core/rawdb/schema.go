@@ -72,9 +72,15 @@ var (
 	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
 	txIndexTailKey = []byte("TransactionIndexTail")
 
+	// depositIndexTailKey tracks the oldest block whose deposits have been indexed.
+	depositIndexTailKey = []byte("DepositIndexTail")
+
 	// fastTxLookupLimitKey tracks the transaction lookup limit during fast sync.
 	fastTxLookupLimitKey = []byte("FastTransactionLookupLimit")
 
+	// logIndexProgressKey tracks the range of canonical blocks whose logs have been indexed.
+	logIndexProgressKey = []byte("LogIndexProgress")
+
 	// badBlockKey tracks the list of bad blocks seen by local
 	badBlockKey = []byte("InvalidBlock")
 
@@ -92,8 +98,11 @@ var (
 
 	blockBodyPrefix     = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	blockFeeStatsPrefix = []byte("f") // blockFeeStatsPrefix + num (uint64 big endian) + hash -> block fee stats
 
 	txLookupPrefix        = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
+	depositLookupPrefix   = []byte("D") // depositLookupPrefix + source hash -> deposit transaction lookup metadata
+	logIndexPrefix        = []byte("L") // logIndexPrefix + term + num (uint64 big endian) -> log positions
 	bloomBitsPrefix       = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
@@ -175,11 +184,26 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// blockFeeStatsKey = blockFeeStatsPrefix + num (uint64 big endian) + hash
+func blockFeeStatsKey(number uint64, hash common.Hash) []byte {
+	return append(append(blockFeeStatsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// depositLookupKey = depositLookupPrefix + source hash
+func depositLookupKey(sourceHash common.Hash) []byte {
+	return append(depositLookupPrefix, sourceHash.Bytes()...)
+}
+
+// logIndexKey = logIndexPrefix + term + num (uint64 big endian)
+func logIndexKey(term []byte, number uint64) []byte {
+	return append(append(append([]byte{}, logIndexPrefix...), term...), encodeBlockNumber(number)...)
+}
+
 // accountSnapshotKey = SnapshotAccountPrefix + hash
 func accountSnapshotKey(hash common.Hash) []byte {
 	return append(SnapshotAccountPrefix, hash.Bytes()...)
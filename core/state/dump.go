@@ -173,6 +173,7 @@ func (s *StateDB) DumpToCollector(c DumpCollector, conf *DumpConfig) (nextKey []
 		}
 		addr := common.BytesToAddress(addrBytes)
 		obj := newObject(s, addr, data)
+		account.Balance = obj.Balance().String()
 		if !conf.SkipCode {
 			account.Code = obj.Code(s.db)
 		}
@@ -438,6 +438,19 @@ func (s *stateObject) SetBalance(amount *big.Int) {
 	s.db.adjustShareCount(prevShares, shares)
 }
 
+// SetBalanceValues overwrites the yield mode and balance values of the account
+// as they are, without adjusting the total share count.
+func (s *stateObject) SetBalanceValues(flags uint8, fixed, shares, remainder *big.Int) {
+	s.db.journal.append(balanceValuesChange{
+		account:       &s.address,
+		prevFlags:     s.data.Flags,
+		prevFixed:     new(big.Int).Set(s.data.Fixed),
+		prevShares:    new(big.Int).Set(s.data.Shares),
+		prevRemainder: new(big.Int).Set(s.data.Remainder),
+	})
+	s.setBalanceValues(flags, fixed, shares, remainder)
+}
+
 func (s *stateObject) setBalanceValues(flags uint8, fixed, shares, remainder *big.Int) {
 	s.data.Flags = flags
 	s.data.Fixed = fixed
@@ -295,6 +295,33 @@ func (s *StateDB) GetStorageRoot(addr common.Address) common.Hash {
 	return common.Hash{}
 }
 
+// CheckKnownAccounts checks the storage of the given accounts against the
+// expected storage roots and slots. The storage root of an account modified
+// since the last root computation is unknown, such accounts never match an
+// expected root.
+func (s *StateDB) CheckKnownAccounts(accounts types.KnownAccounts) error {
+	for addr, account := range accounts {
+		if account.StorageRoot != nil {
+			root := types.EmptyRootHash
+			if obj := s.getStateObject(addr); obj != nil {
+				if len(obj.pendingStorage) > 0 || len(obj.dirtyStorage) > 0 {
+					return fmt.Errorf("storage of account %v modified", addr)
+				}
+				root = obj.Root()
+			}
+			if root != *account.StorageRoot {
+				return fmt.Errorf("storage root mismatch for account %v: have %v, want %v", addr, root, *account.StorageRoot)
+			}
+		}
+		for key, want := range account.StorageSlots {
+			if have := s.GetState(addr, key); have != want {
+				return fmt.Errorf("storage slot %v mismatch for account %v: have %v, want %v", key, addr, have, want)
+			}
+		}
+	}
+	return nil
+}
+
 // TxIndex returns the current transaction index set by Prepare.
 func (s *StateDB) TxIndex() int {
 	return s.txIndex
@@ -427,12 +454,23 @@ func (s *StateDB) GetBalanceValues(addr common.Address) *BalanceValues {
 }
 
 var (
-	sharePriceSlot = common.BigToHash(big.NewInt(1))
+	// SharePriceSlot is the storage slot of the share price in the shares predeploy.
+	SharePriceSlot = common.BigToHash(big.NewInt(1))
 	shareCountSlot = common.BigToHash(big.NewInt(51))
 )
 
 func (s *StateDB) getSharePrice() *big.Int {
-	return s.GetState(params.PatexSharesAddress, sharePriceSlot).Big()
+	return s.GetState(params.PatexSharesAddress, SharePriceSlot).Big()
+}
+
+// GetSharePrice returns the current price of a yield share.
+func (s *StateDB) GetSharePrice() *big.Int {
+	return s.getSharePrice()
+}
+
+// GetShareCount returns the total number of yield shares held by all accounts.
+func (s *StateDB) GetShareCount() *big.Int {
+	return s.GetState(params.PatexSharesAddress, shareCountSlot).Big()
 }
 
 func (s *StateDB) adjustShareCount(pre, post *big.Int) {
@@ -470,6 +508,24 @@ func (s *StateDB) SetFlags(addr common.Address, flags uint8) {
 	}
 }
 
+// SetBalanceValues overwrites the yield mode and raw balance values of the
+// given account. Nil values are treated as zero. Unlike SetBalance, it does
+// not convert between balance and shares, nor does it adjust the total share
+// count, so it is only meant for constructing test pre-states.
+func (s *StateDB) SetBalanceValues(addr common.Address, flags uint8, fixed, shares, remainder *big.Int) {
+	stateObject := s.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetBalanceValues(flags, bigOrZero(fixed), bigOrZero(shares), bigOrZero(remainder))
+	}
+}
+
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(v)
+}
+
 func (s *StateDB) SubClaimableAmount(addr common.Address, value *big.Int) {
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
@@ -31,9 +31,10 @@ import (
 // ExecutionResult includes all output after executing given evm
 // message no matter the execution itself is successful or not.
 type ExecutionResult struct {
-	UsedGas    uint64 // Total used gas but include the refunded gas
-	Err        error  // Any error encountered during the execution(listed in core/vm/errors.go)
-	ReturnData []byte // Returned data from evm(function result or data supplied with revert opcode)
+	UsedGas    uint64            // Total used gas but include the refunded gas
+	Err        error             // Any error encountered during the execution(listed in core/vm/errors.go)
+	ReturnData []byte            // Returned data from evm(function result or data supplied with revert opcode)
+	Fees       *types.FeeCredits // Fees paid by the transaction by recipient, nil for deposits
 }
 
 // Unwrap returns the internal evm error which allows us for further
@@ -489,37 +490,56 @@ func (st *StateTransition) innerTransitionDb() (*ExecutionResult, error) {
 	// are 0. This avoids a negative effectiveTip being applied to
 	// the coinbase when simulating calls.
 	skipTip := st.evm.Config.NoBaseFee && msg.GasFeeCap.Sign() == 0 && msg.GasTipCap.Sign() == 0
+	var (
+		fees        = types.NewFeeCredits()
+		coinbaseFee = new(big.Int)
+		feeShares   []vm.FeeShare
+	)
 	if !skipTip && !isGasAccountingCorrect { // --> default to original behavior is gas accounting is not correct. CANARY
-		// if gas accounting is incorrect, priority fees accumulates to coinbase recipient
+		// if gas accounting is incorrect, priority fees are split between the coinbase and the configured recipients
 		fee := new(big.Int).SetUint64(st.gasUsed())
 		fee.Mul(fee, effectiveTip)
-		halfFee1 := new(big.Int).Div(fee, big.NewInt(2))
-		halfFee2 := new(big.Int).Sub(fee, halfFee1)
-		//first half of fee add to coinbase address
-		st.state.AddBalance(st.evm.Context.Coinbase, halfFee1)
-
-		//second half of fee add to staking rewarder address
-		if st.evm.ChainConfig().ChainID.Cmp(params.MainnetChainID) == 0 {
-			st.state.AddBalance(params.PATEXMainnetStakingRewarderFeeRecipient, halfFee2)
-		} else {
-			st.state.AddBalance(params.PATEXTestnetStakingRewarderFeeRecipient, halfFee2)
+
+		feeSplit := st.evm.ChainConfig().PatexFeeSplit(st.evm.Context.Time)
+		var recipientFees []*big.Int
+		coinbaseFee, recipientFees = feeSplit.Split(fee)
+		st.state.AddBalance(st.evm.Context.Coinbase, coinbaseFee)
+		fees.Coinbase.Add(fees.Coinbase, coinbaseFee)
+		for i, recipient := range feeSplit.Recipients {
+			st.state.AddBalance(recipient.Address, recipientFees[i])
+			fees.FeeSplit.Add(fees.FeeSplit, recipientFees[i])
+			feeShares = append(feeShares, vm.FeeShare{Address: recipient.Address, Fee: recipientFees[i]})
 		}
 	}
 
 	// Check that we are post bedrock to enable pt-geth to be able to create pseudo pre-bedrock blocks (these are pre-bedrock, but don't follow l2 geth rules)
 	// Note patexConfig will not be nil if rules.IsPatexBedrock is true
 	if patexConfig := st.evm.ChainConfig().Patex; patexConfig != nil && rules.IsPatexBedrock {
+		var (
+			allocation *vm.GasAllocation
+			err        error
+		)
 		if !isGasAccountingCorrect {
-			st.state.AddBalance(params.PatexBaseFeeRecipient, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.evm.Context.BaseFee)) // add base fee to base fee recipient
+			allocation = gasTracker.FallbackAllocation(st.gasUsed(), userRefund, st.evm.Context.BaseFee, coinbaseFee, feeShares)
+			st.state.AddBalance(params.PatexBaseFeeRecipient, allocation.UnallocatedFee) // add base fee to base fee recipient
 		} else if skipTip { // just distribute base fee back to holders --> should only happen on simulation
-			gasTracker.AllocateDevGas(st.evm.Context.BaseFee, userRefund, st.state, st.evm.Context.Time)
+			allocation, err = gasTracker.AllocateDevGas(st.evm.Context.BaseFee, userRefund, st.state, st.evm.Context.Time)
 		} else { // distribute patex fee = base + tip back to holders
 			patexFee := new(big.Int).Add(effectiveTip, st.evm.Context.BaseFee)
-			gasTracker.AllocateDevGas(patexFee, userRefund, st.state, st.evm.Context.Time)
+			allocation, err = gasTracker.AllocateDevGas(patexFee, userRefund, st.state, st.evm.Context.Time)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrGasAllocation, err)
+		}
+		if tracer, ok := st.evm.Config.Tracer.(vm.GasAllocationLogger); ok {
+			tracer.CaptureGasAllocation(allocation)
 		}
+		fees.BaseFeeRecipient.Add(fees.BaseFeeRecipient, allocation.UnallocatedFee)
+		fees.Claimable.Add(fees.Claimable, allocation.ClaimableFee)
 
 		if cost := st.evm.Context.L1CostFunc(st.evm.Context.BlockNumber.Uint64(), st.evm.Context.Time, st.msg.RollupDataGas, st.msg.IsDepositTx); cost != nil {
 			st.state.AddBalance(params.PatexL1FeeRecipient, cost)
+			fees.L1Fee.Add(fees.L1Fee, cost)
 		}
 	}
 
@@ -527,6 +547,7 @@ func (st *StateTransition) innerTransitionDb() (*ExecutionResult, error) {
 		UsedGas:    st.gasUsed(),
 		Err:        vmerr,
 		ReturnData: ret,
+		Fees:       fees,
 	}, nil
 }
 
@@ -0,0 +1,229 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ratelimit"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// AdmissionConfig are the policies a sequencer applies on top of the generic
+// validity rules before admitting transactions into the pool. The zero value
+// disables all of them.
+type AdmissionConfig struct {
+	DenyList  []common.Address `toml:",omitempty"` // Senders and recipients whose transactions are rejected
+	AllowList []common.Address `toml:",omitempty"` // Senders whose transactions are admitted, all if empty
+
+	MinL1AdjustedTip *big.Int `toml:",omitempty"` // Minimum effective tip per gas after deducting the L1 fee per gas
+	MaxCalldataSize  uint64   `toml:",omitempty"` // Maximum calldata size in bytes, the protocol limit if zero
+	MaxL1FeeRatio    float64  `toml:",omitempty"` // Maximum share of the L1 fee in the total fee, unlimited if zero
+
+	SenderRateLimit float64 `toml:",omitempty"` // Transactions per second a sender may submit via RPC, unlimited if zero
+	IPRateLimit     float64 `toml:",omitempty"` // Transactions per second a remote address may submit via RPC, unlimited if zero
+	RateLimitBurst  int     `toml:",omitempty"` // Submissions allowed in a burst above the rate limits
+}
+
+var (
+	// ErrAddressDenied is returned if the sender or recipient of a transaction
+	// is on the deny list.
+	ErrAddressDenied = errors.New("address denied")
+
+	// ErrSenderNotAllowed is returned if an allow list is configured and the
+	// sender of a transaction is not on it.
+	ErrSenderNotAllowed = errors.New("sender not allowed")
+
+	// ErrL1AdjustedTipTooLow is returned if the effective tip of a transaction
+	// less its L1 fee per gas is below the configured minimum.
+	ErrL1AdjustedTipTooLow = errors.New("tip after L1 fee too low")
+
+	// ErrCalldataTooLarge is returned if the calldata of a transaction exceeds
+	// the configured maximum size.
+	ErrCalldataTooLarge = errors.New("calldata too large")
+
+	// ErrL1FeeRatioTooHigh is returned if the L1 fee of a transaction exceeds
+	// the configured share of its total fee.
+	ErrL1FeeRatioTooHigh = errors.New("L1 fee share of total fee too high")
+
+	// ErrSubmissionRateLimited is returned if a sender or remote address submits
+	// transactions faster than the configured rate.
+	ErrSubmissionRateLimited = errors.New("submission rate limit exceeded")
+)
+
+// admissionPolicies maps the errors of the admission policies to their
+// JSON-RPC error codes and rejection meters.
+var admissionPolicies = map[error]struct {
+	code  int
+	meter metrics.Meter
+}{
+	ErrAddressDenied:         {-38001, metrics.NewRegisteredMeter("txpool/admission/denied", nil)},
+	ErrSenderNotAllowed:      {-38002, metrics.NewRegisteredMeter("txpool/admission/notallowed", nil)},
+	ErrL1AdjustedTipTooLow:   {-38003, metrics.NewRegisteredMeter("txpool/admission/lowtip", nil)},
+	ErrCalldataTooLarge:      {-38004, metrics.NewRegisteredMeter("txpool/admission/calldata", nil)},
+	ErrL1FeeRatioTooHigh:     {-38005, metrics.NewRegisteredMeter("txpool/admission/l1fee", nil)},
+	ErrSubmissionRateLimited: {-38006, metrics.NewRegisteredMeter("txpool/admission/ratelimit", nil)},
+}
+
+// AdmissionError is returned if a transaction is rejected by an admission
+// policy. It carries the JSON-RPC error code of the policy.
+type AdmissionError struct {
+	err  error
+	code int
+}
+
+// reject records the rejection of a transaction by the given policy and
+// returns the error describing it.
+func reject(policy error, format string, args ...interface{}) error {
+	p := admissionPolicies[policy]
+	p.meter.Mark(1)
+	return &AdmissionError{err: fmt.Errorf("%w: "+format, append([]interface{}{policy}, args...)...), code: p.code}
+}
+
+func (e *AdmissionError) Error() string  { return e.err.Error() }
+func (e *AdmissionError) ErrorCode() int { return e.code }
+func (e *AdmissionError) Unwrap() error  { return e.err }
+
+// admission enforces the admission policies of the pool.
+type admission struct {
+	config AdmissionConfig
+	deny   map[common.Address]struct{}
+	allow  map[common.Address]struct{}
+
+	senders *ratelimit.Limiter[common.Address] // Submission rates of the senders, nil if unlimited
+	remotes *ratelimit.Limiter[string]         // Submission rates of the remote hosts, nil if unlimited
+}
+
+func newAdmission(config AdmissionConfig) *admission {
+	a := &admission{
+		config: config,
+		deny:   make(map[common.Address]struct{}, len(config.DenyList)),
+		allow:  make(map[common.Address]struct{}, len(config.AllowList)),
+	}
+	for _, addr := range config.DenyList {
+		a.deny[addr] = struct{}{}
+	}
+	for _, addr := range config.AllowList {
+		a.allow[addr] = struct{}{}
+	}
+	if config.SenderRateLimit > 0 {
+		a.senders = ratelimit.New[common.Address](config.SenderRateLimit, config.RateLimitBurst)
+	}
+	if config.IPRateLimit > 0 {
+		a.remotes = ratelimit.New[string](config.IPRateLimit, config.RateLimitBurst)
+	}
+	return a
+}
+
+// validateBasics checks the policies that depend on the transaction alone.
+func (a *admission) validateBasics(tx *types.Transaction, from common.Address) error {
+	if _, ok := a.deny[from]; ok {
+		return reject(ErrAddressDenied, "sender %v", from)
+	}
+	if to := tx.To(); to != nil {
+		if _, ok := a.deny[*to]; ok {
+			return reject(ErrAddressDenied, "recipient %v", *to)
+		}
+	}
+	if len(a.allow) > 0 {
+		if _, ok := a.allow[from]; !ok {
+			return reject(ErrSenderNotAllowed, "sender %v", from)
+		}
+	}
+	if limit := a.config.MaxCalldataSize; limit > 0 && uint64(len(tx.Data())) > limit {
+		return reject(ErrCalldataTooLarge, "size %d, limit %d", len(tx.Data()), limit)
+	}
+	return nil
+}
+
+// checksFees reports whether any of the fee policies is enabled.
+func (a *admission) checksFees() bool {
+	return (a.config.MinL1AdjustedTip != nil && a.config.MinL1AdjustedTip.Sign() > 0) || a.config.MaxL1FeeRatio > 0
+}
+
+// validateFees checks the policies that depend on the L1 fee of the transaction
+// and the base fee of the pending block.
+func (a *admission) validateFees(tx *types.Transaction, l1Cost *big.Int, baseFee *big.Int) error {
+	if l1Cost == nil {
+		l1Cost = new(big.Int)
+	}
+	if min := a.config.MinL1AdjustedTip; min != nil && min.Sign() > 0 {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			tip = new(big.Int) // fee cap below base fee, the tip is the first to go
+		}
+		if tx.Gas() > 0 {
+			tip.Sub(tip, new(big.Int).Div(l1Cost, new(big.Int).SetUint64(tx.Gas())))
+		}
+		if tip.Cmp(min) < 0 {
+			return reject(ErrL1AdjustedTipTooLow, "tip %v, minimum %v", tip, min)
+		}
+	}
+	if ratio := a.config.MaxL1FeeRatio; ratio > 0 && l1Cost.Sign() > 0 {
+		// The total fee is bounded by the fee cap, the L1 fee is charged on top.
+		total := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		total.Add(total, l1Cost)
+
+		share, _ := new(big.Float).Quo(new(big.Float).SetInt(l1Cost), new(big.Float).SetInt(total)).Float64()
+		if share > ratio {
+			return reject(ErrL1FeeRatioTooHigh, "share %.4f, limit %.4f", share, ratio)
+		}
+	}
+	return nil
+}
+
+// allowSubmission checks the submission rate limits of the sender and the
+// remote address a transaction was submitted from.
+func (a *admission) allowSubmission(from common.Address, remote string) error {
+	if a.senders != nil && !a.senders.Allow(from) {
+		return reject(ErrSubmissionRateLimited, "sender %v", from)
+	}
+	if a.remotes != nil && remote != "" {
+		if host := ratelimit.Host(remote); !a.remotes.Allow(host) {
+			return reject(ErrSubmissionRateLimited, "address %v", host)
+		}
+	}
+	return nil
+}
+
+// pendingBaseFee returns the base fee of the block following the current head,
+// nil before London.
+func (pool *TxPool) pendingBaseFee() *big.Int {
+	head := pool.currentHead.Load()
+	if head == nil || head.BaseFee == nil || !pool.chainconfig.IsLondon(new(big.Int).Add(head.Number, common.Big1)) {
+		return nil
+	}
+	return misc.CalcBaseFee(pool.chainconfig, head)
+}
+
+// CheckSubmission checks the submission rate limits for a transaction submitted
+// via RPC from the given remote address, which may be empty if unknown. The
+// stateless validity checks are done first, so invalid transactions are not
+// counted against the limits.
+func (pool *TxPool) CheckSubmission(tx *types.Transaction, remote string) error {
+	if err := pool.validateTxBasics(tx, true); err != nil {
+		return err
+	}
+	// Signature has been checked already, this cannot error.
+	from, _ := types.Sender(pool.signer, tx)
+	return pool.admission.allowSubmission(from, remote)
+}
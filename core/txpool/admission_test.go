@@ -0,0 +1,153 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// setupAdmissionPool creates a pool with the given admission policies and a
+// transaction of a funded sender.
+func setupAdmissionPool(t *testing.T, admission AdmissionConfig, key *ecdsa.PrivateKey) (*TxPool, *types.Transaction) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(10000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Admission = admission
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	<-pool.initDoneCh
+	t.Cleanup(pool.Stop)
+
+	tx := transaction(0, 100000, key)
+	from, _ := deriveSender(tx)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+	return pool, tx
+}
+
+func TestAdmissionAddressLists(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Transactions to a denied recipient are rejected.
+	pool, tx := setupAdmissionPool(t, AdmissionConfig{DenyList: []common.Address{{}}}, key)
+	err := pool.AddLocal(tx)
+	if !errors.Is(err, ErrAddressDenied) {
+		t.Fatalf("want %v have %v", ErrAddressDenied, err)
+	}
+	var admissionErr *AdmissionError
+	if !errors.As(err, &admissionErr) || admissionErr.ErrorCode() != -38001 {
+		t.Fatalf("unexpected error code: %v", err)
+	}
+	// Senders not on the allow list are rejected.
+	pool, tx = setupAdmissionPool(t, AdmissionConfig{AllowList: []common.Address{{0x1}}}, key)
+	if err := pool.AddLocal(tx); !errors.Is(err, ErrSenderNotAllowed) {
+		t.Fatalf("want %v have %v", ErrSenderNotAllowed, err)
+	}
+	pool, tx = setupAdmissionPool(t, AdmissionConfig{AllowList: []common.Address{from}}, key)
+	if err := pool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+}
+
+func TestAdmissionCalldataSize(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	pool, tx := setupAdmissionPool(t, AdmissionConfig{MaxCalldataSize: 100}, key)
+	if err := pool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	key, _ = crypto.GenerateKey()
+	large := pricedDataTransaction(0, 1000000, big.NewInt(1), key, 101)
+	from, _ := deriveSender(large)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+	if err := pool.AddLocal(large); !errors.Is(err, ErrCalldataTooLarge) {
+		t.Fatalf("want %v have %v", ErrCalldataTooLarge, err)
+	}
+}
+
+func TestAdmissionFees(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	tx := dynamicFeeTx(0, 100000, big.NewInt(20), big.NewInt(10), key)
+	baseFee := big.NewInt(5)
+
+	tests := []struct {
+		config AdmissionConfig
+		l1Cost *big.Int
+		want   error
+	}{
+		// Tip of 10 less an L1 fee of 5 per gas
+		{config: AdmissionConfig{MinL1AdjustedTip: big.NewInt(5)}, l1Cost: big.NewInt(500000)},
+		{config: AdmissionConfig{MinL1AdjustedTip: big.NewInt(6)}, l1Cost: big.NewInt(500000), want: ErrL1AdjustedTipTooLow},
+		{config: AdmissionConfig{MinL1AdjustedTip: big.NewInt(10)}},
+		// Total fee of 2000000 at the fee cap plus the L1 fee
+		{config: AdmissionConfig{MaxL1FeeRatio: 0.5}, l1Cost: big.NewInt(2000000)},
+		{config: AdmissionConfig{MaxL1FeeRatio: 0.5}, l1Cost: big.NewInt(2000001), want: ErrL1FeeRatioTooHigh},
+		{config: AdmissionConfig{MaxL1FeeRatio: 0.5}},
+	}
+	for i, test := range tests {
+		err := newAdmission(test.config).validateFees(tx, test.l1Cost, baseFee)
+		if !errors.Is(err, test.want) {
+			t.Errorf("test %d: want %v have %v", i, test.want, err)
+		}
+	}
+}
+
+func TestAdmissionRateLimits(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	pool, tx := setupAdmissionPool(t, AdmissionConfig{SenderRateLimit: 0.001, IPRateLimit: 0.001, RateLimitBurst: 2}, key)
+
+	// Invalid transactions are rejected before they count against the limits.
+	if err := pool.CheckSubmission(transaction(0, 100, key), "10.0.0.1:1234"); !errors.Is(err, core.ErrIntrinsicGas) {
+		t.Fatalf("want %v have %v", core.ErrIntrinsicGas, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := pool.CheckSubmission(tx, "10.0.0.1:1234"); err != nil {
+			t.Fatalf("submission %d: unexpected error: %v", i, err)
+		}
+	}
+	if err := pool.CheckSubmission(tx, "10.0.0.2:1234"); !errors.Is(err, ErrSubmissionRateLimited) {
+		t.Fatalf("want %v have %v", ErrSubmissionRateLimited, err)
+	}
+	// Other senders are limited by their remote address only.
+	key, _ = crypto.GenerateKey()
+	other := transaction(0, 100000, key)
+	if err := pool.CheckSubmission(other, "10.0.0.1:4321"); !errors.Is(err, ErrSubmissionRateLimited) {
+		t.Fatalf("want %v have %v", ErrSubmissionRateLimited, err)
+	}
+	if err := pool.CheckSubmission(other, "10.0.0.2:1234"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// mirror is a read-only view of the pending transactions of another pool, such
+// as the mempool of the sequencer on a replica that doesn't take part in
+// transaction gossip. The mirrored transactions are served along with the own
+// ones of the pool, but they are neither validated nor subject to the pool
+// limits and admission policies, as the mirrored pool admitted them already.
+//
+// The view is protected by the pool lock. Pools not mirroring any other have no
+// view, the read methods can be called on nil.
+type mirror struct {
+	all     map[common.Hash]*types.Transaction
+	senders map[common.Address]*sortedMap
+}
+
+func newMirror() *mirror {
+	return &mirror{
+		all:     make(map[common.Hash]*types.Transaction),
+		senders: make(map[common.Address]*sortedMap),
+	}
+}
+
+// put adds a transaction to the view, replacing the one of the same sender and
+// nonce. It returns false if the transaction was already mirrored.
+func (m *mirror) put(from common.Address, tx *types.Transaction) bool {
+	if _, ok := m.all[tx.Hash()]; ok {
+		return false
+	}
+	txs := m.senders[from]
+	if txs == nil {
+		txs = newSortedMap()
+		m.senders[from] = txs
+	}
+	if old := txs.Get(tx.Nonce()); old != nil {
+		delete(m.all, old.Hash())
+	}
+	txs.Put(tx)
+	m.all[tx.Hash()] = tx
+	return true
+}
+
+// forward drops the transactions whose nonces are used up in the given state,
+// i.e. the ones included into the chain or replaced by included ones.
+func (m *mirror) forward(statedb *state.StateDB) {
+	if m == nil {
+		return
+	}
+	for addr, txs := range m.senders {
+		for _, tx := range txs.Forward(statedb.GetNonce(addr)) {
+			delete(m.all, tx.Hash())
+		}
+		if txs.Len() == 0 {
+			delete(m.senders, addr)
+		}
+	}
+}
+
+// merge returns the given transactions of a sender along with the mirrored ones,
+// sorted by nonce. Mirrored transactions take precedence over own ones of the
+// same nonce.
+func (m *mirror) merge(addr common.Address, txs types.Transactions) types.Transactions {
+	if m == nil {
+		return txs
+	}
+	mirrored := m.senders[addr]
+	if mirrored == nil {
+		return txs
+	}
+	merged := newSortedMap()
+	for _, tx := range txs {
+		merged.Put(tx)
+	}
+	for _, tx := range mirrored.Flatten() {
+		merged.Put(tx)
+	}
+	return merged.Flatten()
+}
+
+// mergeAll merges the mirrored transactions into the given pending ones of the
+// pool, grouped by sender.
+func (m *mirror) mergeAll(pending map[common.Address]types.Transactions) {
+	if m == nil {
+		return
+	}
+	for addr := range m.senders {
+		pending[addr] = m.merge(addr, pending[addr])
+	}
+}
+
+// get returns a mirrored transaction, or nil if it isn't mirrored.
+func (m *mirror) get(hash common.Hash) *types.Transaction {
+	if m == nil {
+		return nil
+	}
+	return m.all[hash]
+}
+
+// nonce returns the next nonce of an account given the one of the pool, with the
+// mirrored transactions applied on top.
+func (m *mirror) nonce(addr common.Address, nonce uint64) uint64 {
+	if m == nil {
+		return nonce
+	}
+	if mirrored := m.senders[addr]; mirrored != nil {
+		if next := mirrored.LastElement().Nonce() + 1; next > nonce {
+			nonce = next
+		}
+	}
+	return nonce
+}
+
+// AddMirrored adds pending transactions of a mirrored pool to the read-only view
+// of the pool, replacing the mirrored ones of the same sender and nonce. New
+// transactions are announced like the own pending ones of the pool.
+func (pool *TxPool) AddMirrored(txs []*types.Transaction) {
+	pool.mu.Lock()
+	if pool.mirror == nil {
+		pool.mirror = newMirror()
+		pool.mirrored.Store(true)
+	}
+	added := pool.addMirroredLocked(txs)
+	pool.mu.Unlock()
+
+	if len(added) > 0 {
+		pool.txFeed.Send(core.NewTxsEvent{Txs: added})
+	}
+}
+
+// SetMirrored replaces the read-only view of the pool with the given pending
+// transactions of the mirrored pool, dropping the ones no longer pending there.
+func (pool *TxPool) SetMirrored(txs []*types.Transaction) {
+	pool.mu.Lock()
+	old := pool.mirror
+	pool.mirror = newMirror()
+	pool.mirrored.Store(true)
+	added := pool.addMirroredLocked(txs)
+	pool.mu.Unlock()
+
+	// Only announce the transactions that weren't mirrored before.
+	announce := added[:0]
+	for _, tx := range added {
+		if old.get(tx.Hash()) == nil {
+			announce = append(announce, tx)
+		}
+	}
+	if len(announce) > 0 {
+		pool.txFeed.Send(core.NewTxsEvent{Txs: announce})
+	}
+}
+
+// addMirroredLocked adds transactions to the read-only view, skipping the ones
+// with invalid signatures or nonces used up already. It returns the ones not
+// mirrored before.
+func (pool *TxPool) addMirroredLocked(txs []*types.Transaction) []*types.Transaction {
+	var added []*types.Transaction
+	for _, tx := range txs {
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil || tx.Nonce() < pool.currentState.GetNonce(from) {
+			continue
+		}
+		if pool.mirror.put(from, tx) {
+			added = append(added, tx)
+		}
+	}
+	return added
+}
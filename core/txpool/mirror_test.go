@@ -0,0 +1,98 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that mirrored transactions are served along with the own ones of the
+// pool without being validated, and that they leave the view once included or
+// dropped by the mirrored pool.
+func TestMirroredTransactions(t *testing.T) {
+	t.Parallel()
+
+	// Deny the recipient of the test transactions and don't fund the sender,
+	// the pool itself would reject the transactions.
+	funded, _ := crypto.GenerateKey()
+	pool, _ := setupAdmissionPool(t, AdmissionConfig{DenyList: []common.Address{{}}}, funded)
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	events := make(chan core.NewTxsEvent, 4)
+	sub := pool.SubscribeNewTxsEvent(events)
+	defer sub.Unsubscribe()
+
+	tx0, tx1 := transaction(0, 100000, key), transaction(1, 100000, key)
+	if pool.Get(tx0.Hash()) != nil || pool.Nonce(from) != 0 || pool.mirrored.Load() {
+		t.Fatal("pool mirrors before any mirrored transactions were added")
+	}
+	pool.AddMirrored([]*types.Transaction{tx0, tx1})
+	if ev := <-events; len(ev.Txs) != 2 {
+		t.Fatalf("announced transaction count mismatch: have %d, want 2", len(ev.Txs))
+	}
+	if pending := pool.Pending(true)[from]; len(pending) != 2 || pending[0] != tx0 || pending[1] != tx1 {
+		t.Fatalf("pending transactions mismatch: have %v", pending)
+	}
+	if pending, _ := pool.ContentFrom(from); len(pending) != 2 {
+		t.Fatalf("pending content mismatch: have %d transactions, want 2", len(pending))
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending count mismatch: have %d, want 2", pending)
+	}
+	if nonce := pool.Nonce(from); nonce != 2 {
+		t.Fatalf("pending nonce mismatch: have %d, want 2", nonce)
+	}
+	// Mirrored transactions replace the ones of the same nonce.
+	replacement := pricedTransaction(1, 100000, big.NewInt(2), key)
+	pool.AddMirrored([]*types.Transaction{tx0, replacement})
+	if ev := <-events; len(ev.Txs) != 1 || ev.Txs[0] != replacement {
+		t.Fatalf("announced transactions mismatch: have %v", ev.Txs)
+	}
+	if pool.Has(tx1.Hash()) || !pool.Has(replacement.Hash()) {
+		t.Fatalf("replaced transaction still mirrored")
+	}
+	// Included transactions leave the view with the new head.
+	testSetNonce(pool, from, 1)
+	<-pool.requestReset(nil, nil)
+	if pool.Get(tx0.Hash()) != nil {
+		t.Fatalf("included transaction still mirrored")
+	}
+	if pending := pool.Pending(false)[from]; len(pending) != 1 || pending[0] != replacement {
+		t.Fatalf("pending transactions mismatch: have %v", pending)
+	}
+	// Transactions dropped by the mirrored pool leave the view on a resync.
+	tx2 := transaction(2, 100000, key)
+	pool.SetMirrored([]*types.Transaction{tx2})
+	if ev := <-events; len(ev.Txs) != 1 || ev.Txs[0] != tx2 {
+		t.Fatalf("announced transactions mismatch: have %v", ev.Txs)
+	}
+	if pool.Has(replacement.Hash()) || !pool.Has(tx2.Hash()) {
+		t.Fatalf("resynced view mismatch")
+	}
+	// None of the transactions entered the pool itself.
+	if pool.all.Count() != 0 {
+		t.Fatalf("mirrored transactions added to the pool: %d", pool.all.Count())
+	}
+}
@@ -95,6 +95,14 @@ var (
 	// ErrOverdraft is returned if a transaction would cause the senders balance to go negative
 	// thus invalidating a potential large number of transactions.
 	ErrOverdraft = errors.New("transaction would cause overdraft")
+
+	// ErrConditionalFailed is returned if the inclusion requirements attached to
+	// a transaction are not met by the current chain head.
+	ErrConditionalFailed = errors.New("transaction conditional failed")
+
+	// ErrConditionalOverflow is returned if the conditionals of the pooled
+	// transactions already reference as much storage as the pool checks.
+	ErrConditionalOverflow = errors.New("conditional transactions are at capacity")
 )
 
 var (
@@ -143,6 +151,10 @@ var (
 	slotsGauge   = metrics.NewRegisteredGauge("txpool/slots", nil)
 
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
+
+	// conditionalFailedMeter counts the transactions dropped on a reset because
+	// their conditionals can no longer be met
+	conditionalFailedMeter = metrics.NewRegisteredMeter("txpool/conditional/failed", nil)
 )
 
 // TxStatus is the current status of a transaction as seen by the pool.
@@ -181,6 +193,10 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	ConditionalCost uint64 // Maximum storage roots and slots referenced by the conditionals of all transactions
+
+	Admission AdmissionConfig // Sequencer admission policies
 }
 
 // DefaultConfig contains the default configurations for the transaction
@@ -198,6 +214,8 @@ var DefaultConfig = Config{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	ConditionalCost: 10 * types.MaxConditionalCost,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -236,6 +254,14 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.ConditionalCost < 1 {
+		log.Warn("Sanitizing invalid txpool conditional cost", "provided", conf.ConditionalCost, "updated", DefaultConfig.ConditionalCost)
+		conf.ConditionalCost = DefaultConfig.ConditionalCost
+	}
+	if conf.Admission.MaxL1FeeRatio < 0 || conf.Admission.MaxL1FeeRatio > 1 {
+		log.Warn("Sanitizing invalid txpool L1 fee ratio", "provided", conf.Admission.MaxL1FeeRatio, "updated", 0)
+		conf.Admission.MaxL1FeeRatio = 0
+	}
 	return conf
 }
 
@@ -261,12 +287,15 @@ type TxPool struct {
 	eip1559  atomic.Bool // Fork indicator whether we are using EIP-1559 type transactions.
 	shanghai atomic.Bool // Fork indicator whether we are in the Shanghai stage.
 
-	currentState  *state.StateDB // Current state in the blockchain head
-	pendingNonces *noncer        // Pending state tracking virtual nonces
-	currentMaxGas atomic.Uint64  // Current gas limit for transaction caps
+	currentHead   atomic.Pointer[types.Header] // Current head of the blockchain
+	currentState  *state.StateDB               // Current state in the blockchain head
+	pendingNonces *noncer                      // Pending state tracking virtual nonces
+	currentMaxGas atomic.Uint64                // Current gas limit for transaction caps
 
 	l1CostFn func(dataGas types.RollupGasData, isDepositTx bool) *big.Int // Current L1 fee cost function
 
+	admission *admission // Sequencer admission policies
+
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *journal    // Journal of local transaction to back up to disk
 
@@ -275,6 +304,9 @@ type TxPool struct {
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *lookup                      // All transactions to allow lookups
 	priced  *pricedList                  // All transactions sorted by price
+	mirror  *mirror                      // Read-only view of a mirrored pool, e.g. the sequencer mempool, nil if none
+
+	mirrored atomic.Bool // Whether a pool is mirrored, to skip looking up the mirror without it
 
 	chainHeadCh     chan core.ChainHeadEvent
 	chainHeadSub    event.Subscription
@@ -317,6 +349,7 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 		reorgShutdownCh: make(chan struct{}),
 		initDoneCh:      make(chan struct{}),
 		gasPrice:        new(big.Int).SetUint64(config.PriceLimit),
+		admission:       newAdmission(config.Admission),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -485,7 +518,7 @@ func (pool *TxPool) Nonce(addr common.Address) uint64 {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	return pool.pendingNonces.get(addr)
+	return pool.mirror.nonce(addr, pool.pendingNonces.get(addr))
 }
 
 // Stats retrieves the current pool stats, namely the number of pending and the
@@ -494,7 +527,15 @@ func (pool *TxPool) Stats() (int, int) {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	return pool.stats()
+	pending, queued := pool.stats()
+	if pool.mirror != nil {
+		for hash := range pool.mirror.all {
+			if pool.all.Get(hash) == nil {
+				pending++
+			}
+		}
+	}
+	return pending, queued
 }
 
 // stats retrieves the current pool stats, namely the number of pending and the
@@ -521,6 +562,7 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	for addr, list := range pool.pending {
 		pending[addr] = list.Flatten()
 	}
+	pool.mirror.mergeAll(pending)
 	queued := make(map[common.Address]types.Transactions, len(pool.queue))
 	for addr, list := range pool.queue {
 		queued[addr] = list.Flatten()
@@ -538,6 +580,8 @@ func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.
 	if list, ok := pool.pending[addr]; ok {
 		pending = list.Flatten()
 	}
+	pending = pool.mirror.merge(addr, pending)
+
 	var queued types.Transactions
 	if list, ok := pool.queue[addr]; ok {
 		queued = list.Flatten()
@@ -551,7 +595,7 @@ func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.
 //
 // The enforceTips parameter can be used to do an extra filtering on the pending
 // transactions and only return those whose **effective** tip is large enough in
-// the next pending execution environment.
+// the next pending execution environment. Mirrored transactions are not filtered.
 func (pool *TxPool) Pending(enforceTips bool) map[common.Address]types.Transactions {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
@@ -573,6 +617,7 @@ func (pool *TxPool) Pending(enforceTips bool) map[common.Address]types.Transacti
 			pending[addr] = txs
 		}
 	}
+	pool.mirror.mergeAll(pending)
 	return pending
 }
 
@@ -648,9 +693,15 @@ func (pool *TxPool) validateTxBasics(tx *types.Transaction, local bool) error {
 		return core.ErrTipAboveFeeCap
 	}
 	// Make sure the transaction is signed properly.
-	if _, err := types.Sender(pool.signer, tx); err != nil {
+	from, err := types.Sender(pool.signer, tx)
+	if err != nil {
 		return ErrInvalidSender
 	}
+	// Apply the admission policies of the sequencer, to local transactions too
+	// as these include the ones submitted via RPC.
+	if err := pool.admission.validateBasics(tx, from); err != nil {
+		return err
+	}
 	// Drop non-local transactions under our own minimal accepted gas price or tip
 	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
 		return ErrUnderpriced
@@ -685,6 +736,11 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if balance.Cmp(cost) < 0 {
 		return core.ErrInsufficientFunds
 	}
+	if pool.admission.checksFees() {
+		if err := pool.admission.validateFees(tx, pool.l1CostFn(tx.RollupDataGas(), tx.IsDepositTx()), pool.pendingBaseFee()); err != nil {
+			return err
+		}
+	}
 
 	// Verify that replacing transactions will not result in overdraft
 	list := pool.pending[from]
@@ -703,9 +759,51 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 			return ErrOverdraft
 		}
 	}
+	// Ensure the inclusion requirements can still be met
+	if cond := tx.Conditional(); cond != nil {
+		if uint64(pool.all.ConditionalCost()+cond.Cost()) > pool.config.ConditionalCost {
+			return ErrConditionalOverflow
+		}
+		if err := pool.checkConditional(cond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConditional checks whether a transaction conditional may still be met by
+// a block on top of the current head.
+func (pool *TxPool) checkConditional(cond *types.TransactionConditional) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	if head := pool.currentHead.Load(); head != nil && cond.Expired(head) {
+		return fmt.Errorf("%w: block range expired", ErrConditionalFailed)
+	}
+	if err := pool.currentState.CheckKnownAccounts(cond.KnownAccounts); err != nil {
+		return fmt.Errorf("%w: %v", ErrConditionalFailed, err)
+	}
 	return nil
 }
 
+// dropFailedConditionals removes all transactions whose conditionals can no
+// longer be met after a reset. Any subsequent transactions of the same sender
+// are moved back to the future queue.
+func (pool *TxPool) dropFailedConditionals() {
+	var failed []common.Hash
+	for _, tx := range pool.all.Conditionals() {
+		if err := pool.checkConditional(tx.Conditional()); err != nil {
+			log.Trace("Dropping transaction with failed conditional", "hash", tx.Hash(), "err", err)
+			failed = append(failed, tx.Hash())
+		}
+	}
+
+	for _, hash := range failed {
+		pool.removeTx(hash, true)
+	}
+	conditionalFailedMeter.Mark(int64(len(failed)))
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -1075,6 +1173,8 @@ func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 			status[i] = TxStatusPending
 		} else if txList := pool.queue[from]; txList != nil && txList.txs.items[tx.Nonce()] != nil {
 			status[i] = TxStatusQueued
+		} else if pool.mirror.get(hash) != nil {
+			status[i] = TxStatusPending
 		}
 		// implicit else: the tx may have been included into a block between
 		// checking pool.Get and obtaining the lock. In that case, TxStatusUnknown is correct
@@ -1083,15 +1183,25 @@ func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 	return status
 }
 
-// Get returns a transaction if it is contained in the pool and nil otherwise.
+// Get returns a transaction if it is contained in the pool, or mirrored into
+// it, and nil otherwise.
 func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
-	return pool.all.Get(hash)
+	if tx := pool.all.Get(hash); tx != nil {
+		return tx
+	}
+	if !pool.mirrored.Load() {
+		return nil
+	}
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.mirror.get(hash)
 }
 
 // Has returns an indicator whether txpool has a transaction cached with the
 // given hash.
 func (pool *TxPool) Has(hash common.Hash) bool {
-	return pool.all.Get(hash) != nil
+	return pool.Get(hash) != nil
 }
 
 // removeTx removes a single transaction from the queue, moving all subsequent
@@ -1266,6 +1376,8 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	if reset != nil {
 		// Reset from the old head to the new, rescheduling any reorged transactions
 		pool.reset(reset.oldHead, reset.newHead)
+		pool.dropFailedConditionals()
+		pool.mirror.forward(pool.currentState)
 
 		// Nonces were reset, discard any events that became stale
 		for addr := range events {
@@ -1410,6 +1522,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 		log.Error("Failed to reset txpool state", "err", err)
 		return
 	}
+	pool.currentHead.Store(newHead)
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
 	if !pool.chainconfig.IsPatex() {
@@ -1807,13 +1920,17 @@ type lookup struct {
 	lock    sync.RWMutex
 	locals  map[common.Hash]*types.Transaction
 	remotes map[common.Hash]*types.Transaction
+
+	conditionals    map[common.Hash]*types.Transaction // Transactions carrying a conditional
+	conditionalCost int                                // Storage referenced by the conditionals
 }
 
 // newLookup returns a new lookup structure.
 func newLookup() *lookup {
 	return &lookup{
-		locals:  make(map[common.Hash]*types.Transaction),
-		remotes: make(map[common.Hash]*types.Transaction),
+		locals:       make(map[common.Hash]*types.Transaction),
+		remotes:      make(map[common.Hash]*types.Transaction),
+		conditionals: make(map[common.Hash]*types.Transaction),
 	}
 }
 
@@ -1899,6 +2016,27 @@ func (t *lookup) Slots() int {
 	return t.slots
 }
 
+// Conditionals returns the transactions carrying a conditional.
+func (t *lookup) Conditionals() []*types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	txs := make([]*types.Transaction, 0, len(t.conditionals))
+	for _, tx := range t.conditionals {
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// ConditionalCost returns the number of storage roots and slots referenced by
+// the conditionals of the transactions.
+func (t *lookup) ConditionalCost() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.conditionalCost
+}
+
 // Add adds a transaction to the lookup.
 func (t *lookup) Add(tx *types.Transaction, local bool) {
 	t.lock.Lock()
@@ -1907,6 +2045,10 @@ func (t *lookup) Add(tx *types.Transaction, local bool) {
 	t.slots += numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	if cond := tx.Conditional(); cond != nil {
+		t.conditionals[tx.Hash()] = tx
+		t.conditionalCost += cond.Cost()
+	}
 	if local {
 		t.locals[tx.Hash()] = tx
 	} else {
@@ -1930,6 +2072,10 @@ func (t *lookup) Remove(hash common.Hash) {
 	t.slots -= numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	if cond := tx.Conditional(); cond != nil {
+		delete(t.conditionals, hash)
+		t.conditionalCost -= cond.Cost()
+	}
 	delete(t.locals, hash)
 	delete(t.remotes, hash)
 }
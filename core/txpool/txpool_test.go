@@ -322,6 +322,73 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// Tests that transactions with conditionals are only accepted while they can be
+// met, and are dropped on a reset once they can't.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	var (
+		from, _  = deriveSender(transaction(0, 0, key))
+		contract = common.HexToAddress("0xc0de")
+		slot     = common.HexToHash("0x01")
+	)
+	testAddBalance(pool, from, big.NewInt(1000000))
+	pool.mu.Lock()
+	pool.currentState.SetNonce(contract, 1)
+	pool.currentState.SetState(contract, slot, common.HexToHash("0x01"))
+	pool.mu.Unlock()
+
+	conditional := func(nonce uint64, value common.Hash) *types.Transaction {
+		tx := transaction(nonce, 100000, key)
+		tx.SetConditional(&types.TransactionConditional{
+			KnownAccounts: types.KnownAccounts{contract: {StorageSlots: map[common.Hash]common.Hash{slot: value}}},
+		})
+		return tx
+	}
+	if err := pool.AddLocal(conditional(0, common.HexToHash("0x02"))); !errors.Is(err, ErrConditionalFailed) {
+		t.Fatalf("want %v have %v", ErrConditionalFailed, err)
+	}
+	if err := pool.AddLocal(conditional(0, common.HexToHash("0x01"))); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if err := pool.AddLocal(transaction(1, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Fatalf("pool stats mismatch: pending %d, queued %d, want 2 and 0", pending, queued)
+	}
+	// Change the slot and ensure the conditional transaction is dropped, moving
+	// the subsequent one back into the queue.
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, common.HexToHash("0x02"))
+	pool.mu.Unlock()
+
+	<-pool.requestReset(nil, nil)
+	if pending, queued := pool.Stats(); pending != 0 || queued != 1 {
+		t.Fatalf("pool stats mismatch: pending %d, queued %d, want 0 and 1", pending, queued)
+	}
+	if cost := pool.all.ConditionalCost(); cost != 0 {
+		t.Fatalf("conditional cost of dropped transaction retained: %d", cost)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Ensure the pool only checks as much storage as configured.
+	pool.mu.Lock()
+	pool.config.ConditionalCost = 1
+	pool.mu.Unlock()
+
+	if err := pool.AddLocal(conditional(0, common.HexToHash("0x02"))); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if err := pool.AddLocal(conditional(2, common.HexToHash("0x02"))); !errors.Is(err, ErrConditionalOverflow) {
+		t.Fatalf("want %v have %v", ErrConditionalOverflow, err)
+	}
+}
+
 func TestQueue(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,123 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// FeeCredits are the fees paid by transactions, split by the recipient they
+// were credited to.
+type FeeCredits struct {
+	L1Fee            *big.Int // L1 data fee, sent to the PatexL1FeeRecipient
+	BaseFeeRecipient *big.Int // Fees not claimable by any contract, sent to the PatexBaseFeeRecipient
+	Claimable        *big.Int // Fees claimable by contracts, credited to the PatexGasAddress
+	FeeSplit         *big.Int // Priority fees sent to the fee split recipients, e.g. the staking rewarder
+	Coinbase         *big.Int // Priority fees sent to the coinbase
+}
+
+// NewFeeCredits returns empty fee credits.
+func NewFeeCredits() *FeeCredits {
+	return &FeeCredits{
+		L1Fee:            new(big.Int),
+		BaseFeeRecipient: new(big.Int),
+		Claimable:        new(big.Int),
+		FeeSplit:         new(big.Int),
+		Coinbase:         new(big.Int),
+	}
+}
+
+// Add adds the given fee credits to c.
+func (c *FeeCredits) Add(other *FeeCredits) {
+	c.L1Fee.Add(c.L1Fee, other.L1Fee)
+	c.BaseFeeRecipient.Add(c.BaseFeeRecipient, other.BaseFeeRecipient)
+	c.Claimable.Add(c.Claimable, other.Claimable)
+	c.FeeSplit.Add(c.FeeSplit, other.FeeSplit)
+	c.Coinbase.Add(c.Coinbase, other.Coinbase)
+}
+
+// BlockFeeStats summarizes the fees collected by the transactions of a block,
+// keeping deposits apart from user transactions as deposits pay no fees.
+type BlockFeeStats struct {
+	Deposits       uint64
+	DepositGasUsed uint64
+	UserTxs        uint64
+	UserGasUsed    uint64
+
+	// RollupDataGas is the data gas of the user transactions, L1GasUsed the
+	// L1 gas they were charged for including the fixed overhead. L1DataCost is
+	// the data gas priced at the L1 base fee, an estimate of the cost of
+	// posting the transactions to L1 to compare the L1 fee against.
+	RollupDataGas uint64
+	L1GasUsed     uint64
+	L1DataCost    *big.Int
+
+	Fees FeeCredits
+}
+
+// NewBlockFeeStats returns empty block fee stats.
+func NewBlockFeeStats() *BlockFeeStats {
+	return &BlockFeeStats{L1DataCost: new(big.Int), Fees: *NewFeeCredits()}
+}
+
+// AddTransaction accounts an executed transaction. The L1 cost parameters are
+// read from the given state, which must already include the L1 attributes of
+// the block.
+func (s *BlockFeeStats) AddTransaction(config *params.ChainConfig, time uint64, tx *Transaction, gasUsed uint64, fees *FeeCredits, statedb StateGetter) {
+	if tx.IsDepositTx() {
+		s.Deposits++
+		s.DepositGasUsed += gasUsed
+		return
+	}
+	s.UserTxs++
+	s.UserGasUsed += gasUsed
+	if fees != nil {
+		s.Fees.Add(fees)
+	}
+	if config.Patex == nil {
+		return
+	}
+	dataGas := tx.RollupDataGas().DataGas(time, config)
+	l1BaseFee, overhead, _ := ReadL1CostParams(statedb)
+
+	s.RollupDataGas += dataGas
+	if fees != nil && fees.L1Fee.Sign() > 0 {
+		s.L1GasUsed += dataGas + overhead.Uint64()
+	}
+	s.L1DataCost.Add(s.L1DataCost, new(big.Int).Mul(new(big.Int).SetUint64(dataGas), l1BaseFee))
+}
+
+// Copy returns a deep copy of the stats.
+func (s *BlockFeeStats) Copy() *BlockFeeStats {
+	cpy := NewBlockFeeStats()
+	cpy.Add(s)
+	return cpy
+}
+
+// Add adds the stats of another block to s.
+func (s *BlockFeeStats) Add(other *BlockFeeStats) {
+	s.Deposits += other.Deposits
+	s.DepositGasUsed += other.DepositGasUsed
+	s.UserTxs += other.UserTxs
+	s.UserGasUsed += other.UserGasUsed
+	s.RollupDataGas += other.RollupDataGas
+	s.L1GasUsed += other.L1GasUsed
+	s.L1DataCost.Add(s.L1DataCost, other.L1DataCost)
+	s.Fees.Add(&other.Fees)
+}
@@ -17,6 +17,7 @@
 package types
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -65,15 +66,22 @@ func NewL1CostFunc(config *params.ChainConfig, statedb StateGetter) L1CostFunc {
 			return nil
 		}
 		if blockNum != cacheBlockNum {
-			l1BaseFee = statedb.GetState(L1BlockAddr, L1BaseFeeSlot).Big()
-			overhead = statedb.GetState(L1BlockAddr, OverheadSlot).Big()
-			scalar = statedb.GetState(L1BlockAddr, ScalarSlot).Big()
+			l1BaseFee, overhead, scalar = ReadL1CostParams(statedb)
 			cacheBlockNum = blockNum
 		}
 		return L1Cost(rollupDataGas, l1BaseFee, overhead, scalar)
 	}
 }
 
+// ReadL1CostParams reads the L1 base fee, overhead and scalar that the L1Block
+// predeploy currently stores for the L1 cost computation.
+func ReadL1CostParams(statedb StateGetter) (l1BaseFee, overhead, scalar *big.Int) {
+	l1BaseFee = statedb.GetState(L1BlockAddr, L1BaseFeeSlot).Big()
+	overhead = statedb.GetState(L1BlockAddr, OverheadSlot).Big()
+	scalar = statedb.GetState(L1BlockAddr, ScalarSlot).Big()
+	return l1BaseFee, overhead, scalar
+}
+
 func L1Cost(rollupDataGas uint64, l1BaseFee, overhead, scalar *big.Int) *big.Int {
 	l1GasUsed := new(big.Int).SetUint64(rollupDataGas)
 	l1GasUsed = l1GasUsed.Add(l1GasUsed, overhead)
@@ -81,3 +89,26 @@ func L1Cost(rollupDataGas uint64, l1BaseFee, overhead, scalar *big.Int) *big.Int
 	l1Cost = l1Cost.Mul(l1Cost, scalar)
 	return l1Cost.Div(l1Cost, big.NewInt(1_000_000))
 }
+
+// L1FeeHistory contains the L1 data fee parameters of a range of blocks on a
+// rollup chain, as set by their L1 info deposits. Each slice holds one value per
+// block, ordered from the oldest block of the range. Blocks without an L1 info
+// deposit report zero values.
+type L1FeeHistory struct {
+	L1BaseFee []*big.Int // L1 base fee the data of the block's transactions is priced with
+	Overhead  []*big.Int // Fixed L1 gas overhead charged per transaction
+	Scalar    []*big.Int // Fee scalar, scaled by 1e6
+}
+
+// L1InfoCostParams extracts the L1 base fee, overhead and scalar from the calldata
+// of an L1 info deposit transaction, i.e. the values it writes into the L1Block
+// predeploy at the start of the block.
+func L1InfoCostParams(data []byte) (l1BaseFee, overhead, scalar *big.Int, err error) {
+	if len(data) < 4+32*8 { // function selector + 8 arguments to setL1BlockValues
+		return nil, nil, nil, fmt.Errorf("L1 info tx only has %d bytes, cannot read gas price parameters", len(data))
+	}
+	l1BaseFee = new(big.Int).SetBytes(data[4+32*2 : 4+32*3]) // arg index 2
+	overhead = new(big.Int).SetBytes(data[4+32*6 : 4+32*7])  // arg index 6
+	scalar = new(big.Int).SetBytes(data[4+32*7 : 4+32*8])    // arg index 7
+	return l1BaseFee, overhead, scalar, nil
+}
@@ -61,6 +61,9 @@ type Transaction struct {
 
 	// cache of RollupGasData details to compute the gas the tx takes on L1 for its share of rollup data
 	rollupGas atomic.Value
+
+	// local inclusion requirements, see TransactionConditional
+	conditional atomic.Pointer[TransactionConditional]
 }
 
 // NewTx creates a new transaction.
@@ -382,6 +385,18 @@ func (tx *Transaction) RollupDataGas() RollupGasData {
 	return out
 }
 
+// Conditional returns the inclusion requirements attached to the transaction,
+// or nil if it may be included unconditionally.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	return tx.conditional.Load()
+}
+
+// SetConditional attaches inclusion requirements to the transaction. They are
+// not part of the transaction encoding and are lost when it is copied.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional.Store(cond)
+}
+
 // RawSignatureValues returns the V, R, S signature values of the transaction.
 // The return values should not be modified by the caller.
 func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
@@ -465,6 +480,11 @@ func (tx *Transaction) Hash() common.Hash {
 	return h
 }
 
+// Time returns the time the transaction was first seen locally.
+func (tx *Transaction) Time() time.Time {
+	return tx.time
+}
+
 // Size returns the true encoded storage size of the transaction, either by encoding
 // and returning it, or returning a previously cached value.
 func (tx *Transaction) Size() uint64 {
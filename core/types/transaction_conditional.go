@@ -0,0 +1,156 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+//go:generate go run github.com/fjl/gencodec -type TransactionConditional -field-override transactionConditionalMarshaling -out gen_transaction_conditional_json.go
+
+// MaxConditionalCost is the maximum number of storage roots and slots a
+// transaction conditional may reference.
+const MaxConditionalCost = 1000
+
+var (
+	// ErrConditionalTooExpensive is returned if a conditional references more
+	// storage than MaxConditionalCost.
+	ErrConditionalTooExpensive = errors.New("transaction conditional too expensive")
+
+	// ErrConditionalInvalidRange is returned if a conditional's lower bound
+	// exceeds its upper bound.
+	ErrConditionalInvalidRange = errors.New("transaction conditional has empty range")
+)
+
+// KnownAccount is the expected storage of an account, given either as the
+// storage root or as a set of storage slots.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// MarshalJSON encodes the storage root as a hash and the storage slots as an
+// object mapping slots to values.
+func (ka KnownAccount) MarshalJSON() ([]byte, error) {
+	if ka.StorageRoot != nil {
+		return json.Marshal(ka.StorageRoot)
+	}
+	return json.Marshal(ka.StorageSlots)
+}
+
+// UnmarshalJSON decodes either a storage root or a set of storage slots.
+func (ka *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		ka.StorageRoot, ka.StorageSlots = &root, nil
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return errors.New("known account must be a storage root or a map of storage slots")
+	}
+	ka.StorageRoot, ka.StorageSlots = nil, slots
+	return nil
+}
+
+// KnownAccounts maps accounts to their expected storage.
+type KnownAccounts map[common.Address]KnownAccount
+
+// TransactionConditional is a set of requirements on the including block and
+// the state it executes on. A transaction carrying a conditional may only be
+// included in a block satisfying all of them. Conditionals are local to the
+// node and never part of the transaction encoding.
+type TransactionConditional struct {
+	KnownAccounts  KnownAccounts `json:"knownAccounts"`
+	BlockNumberMin *big.Int      `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *big.Int      `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64       `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64       `json:"timestampMax,omitempty"`
+}
+
+// field type overrides for gencodec
+type transactionConditionalMarshaling struct {
+	BlockNumberMin *hexutil.Big
+	BlockNumberMax *hexutil.Big
+	TimestampMin   *hexutil.Uint64
+	TimestampMax   *hexutil.Uint64
+}
+
+// Cost returns the number of storage roots and slots the conditional references.
+func (c *TransactionConditional) Cost() int {
+	cost := 0
+	for _, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		}
+		cost += len(account.StorageSlots)
+	}
+	return cost
+}
+
+// Validate checks that the conditional is satisfiable at all.
+func (c *TransactionConditional) Validate() error {
+	if c.Cost() > MaxConditionalCost {
+		return fmt.Errorf("%w: cost %d, limit %d", ErrConditionalTooExpensive, c.Cost(), MaxConditionalCost)
+	}
+	if c.BlockNumberMin != nil && c.BlockNumberMax != nil && c.BlockNumberMin.Cmp(c.BlockNumberMax) > 0 {
+		return fmt.Errorf("%w: block number min %v > max %v", ErrConditionalInvalidRange, c.BlockNumberMin, c.BlockNumberMax)
+	}
+	if c.TimestampMin != nil && c.TimestampMax != nil && *c.TimestampMin > *c.TimestampMax {
+		return fmt.Errorf("%w: timestamp min %d > max %d", ErrConditionalInvalidRange, *c.TimestampMin, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckBlockNumber checks whether a block with the given number satisfies the
+// block number range of the conditional.
+func (c *TransactionConditional) CheckBlockNumber(number *big.Int) error {
+	if c.BlockNumberMin != nil && number.Cmp(c.BlockNumberMin) < 0 {
+		return fmt.Errorf("block number %v below minimum %v", number, c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && number.Cmp(c.BlockNumberMax) > 0 {
+		return fmt.Errorf("block number %v above maximum %v", number, c.BlockNumberMax)
+	}
+	return nil
+}
+
+// CheckTimestamp checks whether a block with the given timestamp satisfies the
+// timestamp range of the conditional.
+func (c *TransactionConditional) CheckTimestamp(time uint64) error {
+	if c.TimestampMin != nil && time < *c.TimestampMin {
+		return fmt.Errorf("timestamp %d below minimum %d", time, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && time > *c.TimestampMax {
+		return fmt.Errorf("timestamp %d above maximum %d", time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// Expired reports whether no block after the given head can satisfy the block
+// number and timestamp ranges of the conditional anymore.
+func (c *TransactionConditional) Expired(head *Header) bool {
+	if c.BlockNumberMax != nil && head.Number.Cmp(c.BlockNumberMax) >= 0 {
+		return true
+	}
+	return c.TimestampMax != nil && head.Time >= *c.TimestampMax
+}
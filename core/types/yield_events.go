@@ -0,0 +1,102 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// YieldConfiguredTopic is the topic of the log emitted by the patex precompile
+	// whenever the yield mode of an account changes:
+	//
+	//	YieldConfigured(address indexed account, uint8 previousFlags, uint8 flags)
+	YieldConfiguredTopic = crypto.Keccak256Hash([]byte("YieldConfigured(address,uint8,uint8)"))
+
+	// YieldClaimedTopic is the topic of the log emitted by the patex precompile
+	// whenever claimable yield is paid out:
+	//
+	//	YieldClaimed(address indexed account, address indexed recipient, uint256 amount)
+	YieldClaimedTopic = crypto.Keccak256Hash([]byte("YieldClaimed(address,address,uint256)"))
+
+	errNotYieldEvent = errors.New("log is not a yield event")
+)
+
+// YieldConfigured is the decoded form of a YieldConfigured log.
+type YieldConfigured struct {
+	Account       common.Address
+	PreviousFlags uint8
+	Flags         uint8
+}
+
+// YieldClaimed is the decoded form of a YieldClaimed log.
+type YieldClaimed struct {
+	Account   common.Address
+	Recipient common.Address
+	Amount    *big.Int
+}
+
+// NewYieldConfiguredLog creates the log reporting a yield mode change of account.
+func NewYieldConfiguredLog(account common.Address, previousFlags, flags uint8) *Log {
+	data := make([]byte, 64)
+	data[31] = previousFlags
+	data[63] = flags
+	return &Log{
+		Address: params.PatexPrecompileAddress,
+		Topics:  []common.Hash{YieldConfiguredTopic, common.BytesToHash(account.Bytes())},
+		Data:    data,
+	}
+}
+
+// NewYieldClaimedLog creates the log reporting a claim of amount from the
+// claimable yield of account, paid out to recipient.
+func NewYieldClaimedLog(account, recipient common.Address, amount *big.Int) *Log {
+	return &Log{
+		Address: params.PatexPrecompileAddress,
+		Topics:  []common.Hash{YieldClaimedTopic, common.BytesToHash(account.Bytes()), common.BytesToHash(recipient.Bytes())},
+		Data:    common.BigToHash(amount).Bytes(),
+	}
+}
+
+// ParseYieldConfiguredLog decodes a log created by NewYieldConfiguredLog.
+func ParseYieldConfiguredLog(log *Log) (*YieldConfigured, error) {
+	if log.Address != params.PatexPrecompileAddress || len(log.Topics) != 2 || log.Topics[0] != YieldConfiguredTopic || len(log.Data) != 64 {
+		return nil, errNotYieldEvent
+	}
+	return &YieldConfigured{
+		Account:       common.BytesToAddress(log.Topics[1].Bytes()),
+		PreviousFlags: log.Data[31],
+		Flags:         log.Data[63],
+	}, nil
+}
+
+// ParseYieldClaimedLog decodes a log created by NewYieldClaimedLog.
+func ParseYieldClaimedLog(log *Log) (*YieldClaimed, error) {
+	if log.Address != params.PatexPrecompileAddress || len(log.Topics) != 3 || log.Topics[0] != YieldClaimedTopic || len(log.Data) != 32 {
+		return nil, errNotYieldEvent
+	}
+	return &YieldClaimed{
+		Account:   common.BytesToAddress(log.Topics[1].Bytes()),
+		Recipient: common.BytesToAddress(log.Topics[2].Bytes()),
+		Amount:    new(big.Int).SetBytes(log.Data),
+	}, nil
+}
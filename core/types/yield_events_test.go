@@ -0,0 +1,54 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestYieldEventLogs(t *testing.T) {
+	var (
+		account   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		recipient = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	)
+	configured, err := ParseYieldConfiguredLog(NewYieldConfiguredLog(account, YieldDisabled, YieldClaimable))
+	if err != nil {
+		t.Fatalf("failed to parse configured log: %v", err)
+	}
+	if configured.Account != account || configured.PreviousFlags != YieldDisabled || configured.Flags != YieldClaimable {
+		t.Errorf("configured log mismatch: %+v", configured)
+	}
+	claimed, err := ParseYieldClaimedLog(NewYieldClaimedLog(account, recipient, big.NewInt(1234)))
+	if err != nil {
+		t.Fatalf("failed to parse claimed log: %v", err)
+	}
+	if claimed.Account != account || claimed.Recipient != recipient || claimed.Amount.Cmp(big.NewInt(1234)) != 0 {
+		t.Errorf("claimed log mismatch: %+v", claimed)
+	}
+	// Logs of the wrong kind or from other contracts must be rejected
+	if _, err := ParseYieldConfiguredLog(NewYieldClaimedLog(account, recipient, common.Big1)); err == nil {
+		t.Error("parsed claimed log as configured log")
+	}
+	forged := NewYieldClaimedLog(account, recipient, common.Big1)
+	forged.Address = account
+	if _, err := ParseYieldClaimedLog(forged); err == nil {
+		t.Error("parsed log emitted by a contract")
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/blake2b"
 	"github.com/ethereum/go-ethereum/crypto/bls12381"
@@ -94,6 +95,23 @@ var PrecompiledContractsBerlin = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{1, 0}): &patex{},
 }
 
+// PrecompiledContractsPatexYieldEvents contains the default set of pre-compiled
+// contracts used after the Patex yield events fork, where the patex precompile
+// reports account configuration changes and claims as logs. The fork requires
+// Berlin, the set is a superset of the Berlin one.
+var PrecompiledContractsPatexYieldEvents = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}):    &ecrecover{},
+	common.BytesToAddress([]byte{2}):    &sha256hash{},
+	common.BytesToAddress([]byte{3}):    &ripemd160hash{},
+	common.BytesToAddress([]byte{4}):    &dataCopy{},
+	common.BytesToAddress([]byte{5}):    &bigModExp{eip2565: true},
+	common.BytesToAddress([]byte{6}):    &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}):    &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}):    &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}):    &blake2F{},
+	common.BytesToAddress([]byte{1, 0}): &patex{emitEvents: true},
+}
+
 // PrecompiledContractsBLS contains the set of pre-compiled Ethereum
 // contracts specified in EIP-2537. These are exported for testing purposes.
 var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
@@ -1157,7 +1175,17 @@ func (s *solidityInput) readU256() (*big.Int, error) {
 	return new(big.Int).SetBytes(data), nil
 }
 
-type patex struct{}
+type patex struct {
+	emitEvents  bool   // Whether configuration changes and claims are logged
+	blockNumber uint64 // Number of the block the logs are emitted in
+}
+
+// addLog adds a yield event to the logs of the state, annotated with the block
+// number like the logs emitted by the EVM itself.
+func (b *patex) addLog(db StateDB, log *types.Log) {
+	log.BlockNumber = b.blockNumber
+	db.AddLog(log)
+}
 
 var (
 	// wr selectors
@@ -1233,6 +1261,9 @@ func (b *patex) Run(caller common.Address, input []byte, db StateDB, readOnly bo
 		if amount.Sign() > 0 {
 			db.SubClaimableAmount(contract, amount)
 			db.AddBalance(recipient, amount)
+			if b.emitEvents {
+				b.addLog(db, types.NewYieldClaimedLog(contract, recipient, amount))
+			}
 		}
 
 		return common.BigToHash(amount).Bytes(), nil
@@ -1254,7 +1285,11 @@ func (b *patex) Run(caller common.Address, input []byte, db StateDB, readOnly bo
 			return nil, ErrExecutionReverted
 		}
 
+		previous := db.GetFlags(contract)
 		db.SetFlags(contract, flags)
+		if b.emitEvents && previous != flags {
+			b.addLog(db, types.NewYieldConfiguredLog(contract, previous, flags))
+		}
 
 		balance := db.GetBalance(contract)
 		return common.BigToHash(balance).Bytes(), nil
@@ -43,6 +43,9 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	switch {
 	case evm.chainRules.IsBerlin:
 		precompiles = PrecompiledContractsBerlin
+		if evm.chainRules.IsPatexYieldEvents {
+			precompiles = PrecompiledContractsPatexYieldEvents
+		}
 	case evm.chainRules.IsIstanbul:
 		precompiles = PrecompiledContractsIstanbul
 	case evm.chainRules.IsByzantium:
@@ -51,6 +54,10 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 		precompiles = PrecompiledContractsHomestead
 	}
 	p, ok := precompiles[addr]
+	// The logs of the patex precompile need the number of the current block.
+	if px, isPatex := p.(*patex); isPatex && px.emitEvents {
+		p = evm.patex
+	}
 	return p, ok
 }
 
@@ -111,6 +118,9 @@ type EVM struct {
 	chainConfig *params.ChainConfig
 	// chain rules contains the chain rules for the current epoch
 	chainRules params.Rules
+	// patex is the patex precompile logging yield events in the current block,
+	// nil before the yield events fork
+	patex *patex
 	// virtual machine configuration options used to initialise the
 	// evm.
 	Config Config
@@ -145,6 +155,9 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 		chainConfig: chainConfig,
 		chainRules:  chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time),
 	}
+	if evm.chainRules.IsBerlin && evm.chainRules.IsPatexYieldEvents {
+		evm.patex = &patex{emitEvents: true, blockNumber: blockCtx.BlockNumber.Uint64()}
+	}
 	evm.interpreter = NewEVMInterpreter(evm)
 	return evm
 }
@@ -477,7 +490,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	evm.Context.Transfer(evm.StateDB, caller.Address(), address, value)
 
 	// contracts initialized to yield disabled
-	evm.StateDB.SetFlags(address, types.YieldDisabled)
+	evm.setFlags(address, types.YieldDisabled)
 
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
@@ -553,5 +566,24 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *
 	return evm.create(caller, codeAndHash, gas, endowment, contractAddr, CREATE2, gasTracker)
 }
 
+// setFlags sets the yield mode of addr. Once the Patex yield events fork is
+// active, an actual change of the mode is reported as a log of the patex
+// precompile so that it is visible without diffing state.
+func (evm *EVM) setFlags(addr common.Address, flags uint8) {
+	if !evm.chainRules.IsPatexYieldEvents {
+		evm.StateDB.SetFlags(addr, flags)
+		return
+	}
+	previous := evm.StateDB.GetFlags(addr)
+	evm.StateDB.SetFlags(addr, flags)
+	if previous != flags {
+		log := types.NewYieldConfiguredLog(addr, previous, flags)
+		// This is a non-consensus field, but assigned here because
+		// core/state doesn't know the current block number.
+		log.BlockNumber = evm.Context.BlockNumber.Uint64()
+		evm.StateDB.AddLog(log)
+	}
+}
+
 // ChainConfig returns the environment's chain configuration
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
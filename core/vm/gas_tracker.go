@@ -1,15 +1,33 @@
 package vm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 )
 
+var (
+	gasRefundClampedCounter     = metrics.NewRegisteredCounter("vm/gastracker/refund/clamped", nil)
+	gasParamsSaturatedCounter   = metrics.NewRegisteredCounter("vm/gastracker/params/saturated", nil)
+	gasParamsInvalidCounter     = metrics.NewRegisteredCounter("vm/gastracker/params/invalid", nil)
+	gasAccountingInflateCounter = metrics.NewRegisteredCounter("vm/gastracker/accounting/inflation", nil)
+)
+
+// Bounds of the fields packed into the gas parameters storage slot. Values
+// exceeding them saturate at the bound when packed.
+var (
+	maxPackedEtherBalance = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 12*8), common.Big1)
+	maxPackedEtherSeconds = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 15*8), common.Big1)
+	maxPackedLastUpdated  = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 4*8), common.Big1)
+)
+
 type GasParameters struct {
 	mode         bool
 	lastUpdated  *big.Int
@@ -17,6 +35,48 @@ type GasParameters struct {
 	etherSeconds *big.Int
 }
 
+// Mode returns whether the contract accumulates the gas fees it is allocated.
+func (p *GasParameters) Mode() bool { return p.mode }
+
+// EtherBalance returns the gas fees accumulated by the contract, in wei.
+func (p *GasParameters) EtherBalance() *big.Int { return new(big.Int).Set(p.etherBalance) }
+
+// EtherSeconds returns the accumulated ether seconds as of LastUpdated.
+func (p *GasParameters) EtherSeconds() *big.Int { return new(big.Int).Set(p.etherSeconds) }
+
+// LastUpdated returns the timestamp of the last fee allocation to the contract.
+func (p *GasParameters) LastUpdated() uint64 { return p.lastUpdated.Uint64() }
+
+// EtherSecondsAt returns the ether seconds accumulated up to the given timestamp,
+// i.e. the stored ether seconds plus the ether balance held since LastUpdated.
+func (p *GasParameters) EtherSecondsAt(timestamp uint64) *big.Int {
+	etherSeconds := new(big.Int).Set(p.etherSeconds)
+	if elapsed := new(big.Int).Sub(new(big.Int).SetUint64(timestamp), p.lastUpdated); elapsed.Sign() > 0 {
+		etherSeconds.Add(etherSeconds, elapsed.Mul(elapsed, p.etherBalance))
+	}
+	return etherSeconds
+}
+
+// ReadGasParameters returns the decoded gas parameters of the given contract,
+// as stored in the gas predeploy.
+func ReadGasParameters(state StateDB, contractAddress common.Address) (*GasParameters, error) {
+	return readGasParameters(state, contractAddress)
+}
+
+// GasParametersSlot returns the storage slot of the gas predeploy that holds the
+// packed gas parameters of the given contract.
+func GasParametersSlot(contractAddress common.Address) common.Hash {
+	return getContractStorageSlot(contractAddress)
+}
+
+// PackWithMode returns the packed storage value of the gas parameters with the
+// mode replaced by the given one.
+func (p *GasParameters) PackWithMode(mode bool) common.Hash {
+	packed := *p
+	packed.mode = mode
+	return common.BytesToHash(pack(&packed))
+}
+
 type GasTracker struct {
 	allocations map[common.Address]uint64
 	gasUsed     uint64
@@ -35,20 +95,86 @@ func (gtm *GasTracker) UseGas(address common.Address, amount uint64) {
 	gtm.allocations[address] += amount
 }
 
+// RefundGas returns gas previously attributed to address. A refund exceeding
+// the gas attributed to the address (or in total) is clamped to it; the tracker
+// then disagrees with the EVM and the transaction takes the canary fallback.
 func (gtm *GasTracker) RefundGas(address common.Address, amount uint64) {
-	// sanity check
-	if amount > gtm.gasUsed || amount > gtm.allocations[address] {
-		panic(fmt.Sprintf("refund exceeds gas used: gasUsed=%d, allocation=%d, amount=%d", gtm.gasUsed, gtm.allocations[address], amount))
+	if allocation := gtm.allocations[address]; amount > allocation {
+		gasRefundClampedCounter.Inc(1)
+		amount = allocation
+	}
+	if amount > gtm.gasUsed {
+		gasRefundClampedCounter.Inc(1)
+		amount = gtm.gasUsed
 	}
-
 	gtm.gasUsed -= amount
 	gtm.allocations[address] -= amount
 }
 
-func (gtm *GasTracker) AllocateDevGas(gasPrice *big.Int, refund uint64, state StateDB, timestamp uint64) {
+// GasAllocation describes how the fees of a transaction were shared out
+// between the contracts it touched.
+type GasAllocation struct {
+	// AccountingCorrect reports whether the gas attributed by the tracker matched
+	// the gas used by the EVM. If it did not, the canary fallback was taken and
+	// no fees were credited to contracts.
+	AccountingCorrect bool
+	EVMGasUsed        uint64 // Gas used by the transaction before refunds
+	TrackerGasUsed    uint64 // Gas attributed to contracts by the tracker
+	Refund            uint64 // Gas refunded to the sender
+
+	GasPrice       *big.Int                // Wei per scaled gas unit
+	Contracts      []ContractGasAllocation // Per-contract allocations, sorted by address
+	UnallocatedGas *big.Int                // Scaled gas units not credited to any contract
+	UnallocatedFee *big.Int                // Wei sent to the PatexBaseFeeRecipient
+	ClaimableFee   *big.Int                // Wei sent to the PatexGasAddress
+
+	// The priority fee is only split between the coinbase and the fee split
+	// recipients on the canary fallback, it is shared out with the base fee
+	// otherwise.
+	CoinbaseFee *big.Int   // Wei of the priority fee sent to the coinbase
+	FeeSplit    []FeeShare // Wei of the priority fee sent to the fee split recipients
+}
+
+// FeeShare is the share of the priority fee of one fee split recipient.
+type FeeShare struct {
+	Address common.Address
+	Fee     *big.Int
+}
+
+// ContractGasAllocation is the share of the transaction fees of one contract.
+type ContractGasAllocation struct {
+	Address      common.Address
+	GasUsed      uint64   // Gas attributed to the contract, before refunds
+	ScaledGas    *big.Int // Gas units after the refund was deducted proportionally
+	Accumulating bool     // Whether the contract accumulates gas fees
+	Fee          *big.Int // Wei credited to the contract
+}
+
+// GasAllocationLogger is an optional interface of EVMLogger implementations
+// that are informed about how the fees of a transaction are shared out.
+type GasAllocationLogger interface {
+	CaptureGasAllocation(allocation *GasAllocation)
+}
+
+// AllocateDevGas credits the fees of the gas used, net of refund, to the
+// contracts that accumulate gas fees and reports the resulting allocation.
+// The allocation is computed in full before any state is modified, so if it
+// fails the state is left untouched and the error is returned instead.
+func (gtm *GasTracker) AllocateDevGas(gasPrice *big.Int, refund uint64, state StateDB, timestamp uint64) (*GasAllocation, error) {
+	allocation := &GasAllocation{
+		AccountingCorrect: true,
+		EVMGasUsed:        gtm.gasUsed,
+		TrackerGasUsed:    gtm.gasUsed,
+		Refund:            refund,
+		GasPrice:          new(big.Int).Set(gasPrice),
+		UnallocatedGas:    new(big.Int),
+		UnallocatedFee:    new(big.Int),
+		ClaimableFee:      new(big.Int),
+		CoinbaseFee:       new(big.Int),
+	}
 	// net gas used is 0 or gas consumed is <= refund
 	if gtm.gasUsed == 0 || gtm.gasUsed <= refund {
-		return
+		return allocation, nil
 	}
 
 	remainingGas := new(big.Int).SetUint64(gtm.gasUsed - refund)
@@ -56,32 +182,59 @@ func (gtm *GasTracker) AllocateDevGas(gasPrice *big.Int, refund uint64, state St
 	accumulatedGas := new(big.Int)
 	totalGasAccount := new(big.Int)
 	blockTimestamp := new(big.Int).SetUint64(timestamp)
-	for addr, rawAmount := range gtm.allocations {
+
+	addrs := make([]common.Address, 0, len(gtm.allocations))
+	for addr := range gtm.allocations {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	updates := make([]*GasParameters, 0, len(addrs))
+	for _, addr := range addrs {
 		// find scaled gas units
+		rawAmount := gtm.allocations[addr]
 		parsedRawAmount := new(big.Int).SetUint64(rawAmount)
 		scaledGasUnits := new(big.Int).Div(new(big.Int).Mul(remainingGas, parsedRawAmount), netGas)
 		totalGasAccount.Add(totalGasAccount, scaledGasUnits)
 
+		contract := ContractGasAllocation{
+			Address:   addr,
+			GasUsed:   rawAmount,
+			ScaledGas: scaledGasUnits,
+			Fee:       new(big.Int),
+		}
+
 		// skip allocation of gas to contracts that dont accumulate
-		gasParameters := readGasParameters(state, addr)
+		gasParameters, err := readGasParameters(state, addr)
+		if err != nil {
+			return nil, err
+		}
 		if !gasParameters.mode {
+			allocation.Contracts = append(allocation.Contracts, contract)
+			updates = append(updates, nil)
 			continue
 		}
+		contract.Accumulating = true
 
 		accumulatedGas.Add(accumulatedGas, scaledGasUnits)
 
 		// calculate gas in wei terms
-		fee := new(big.Int).Mul(scaledGasUnits, gasPrice)
-
-		// update gas predeploy
-		if fee.Cmp(common.Big0) > 0 {
-			updateGasPredeploy(state, addr, fee, blockTimestamp, gasParameters)
-		}
+		contract.Fee = new(big.Int).Mul(scaledGasUnits, gasPrice)
+		allocation.Contracts = append(allocation.Contracts, contract)
+		updates = append(updates, gasParameters)
 	}
 
 	// sanity check
 	if totalGasAccount.Cmp(remainingGas) > 0 {
-		panic(fmt.Sprintf("gas accounting inflation: totalGasAccount=%v, remainingGas=%v", totalGasAccount.String(), remainingGas.String()))
+		gasAccountingInflateCounter.Inc(1)
+		return nil, fmt.Errorf("%w: totalGasAccount=%v, remainingGas=%v", ErrGasAccountingInflation, totalGasAccount, remainingGas)
+	}
+
+	// update gas predeploy
+	for i, contract := range allocation.Contracts {
+		if updates[i] != nil && contract.Fee.Sign() > 0 {
+			updateGasPredeploy(state, contract.Address, contract.Fee, blockTimestamp, updates[i])
+		}
 	}
 
 	// give rest of gas to base fee recipient (for patex admin to claim)
@@ -92,6 +245,31 @@ func (gtm *GasTracker) AllocateDevGas(gasPrice *big.Int, refund uint64, state St
 	// pay out non-void gas to patex predeploy
 	claimableGasToAdd := new(big.Int).Mul(accumulatedGas, gasPrice)
 	state.AddBalance(params.PatexGasAddress, claimableGasToAdd)
+
+	allocation.UnallocatedGas = patexGasUnits
+	allocation.UnallocatedFee = patexGas
+	allocation.ClaimableFee = claimableGasToAdd
+	return allocation, nil
+}
+
+// FallbackAllocation reports the canary fallback taken when the gas attributed
+// by the tracker does not match the gasUsed reported by the EVM: no fees are
+// credited to contracts and the base fee of all gas used goes to the
+// PatexBaseFeeRecipient, while the priority fee was split between the coinbase
+// and the fee split recipients as given.
+func (gtm *GasTracker) FallbackAllocation(gasUsed uint64, refund uint64, baseFee *big.Int, coinbaseFee *big.Int, feeSplit []FeeShare) *GasAllocation {
+	return &GasAllocation{
+		AccountingCorrect: false,
+		EVMGasUsed:        gasUsed,
+		TrackerGasUsed:    gtm.gasUsed,
+		Refund:            refund,
+		GasPrice:          new(big.Int).Set(baseFee),
+		UnallocatedGas:    new(big.Int).SetUint64(gasUsed),
+		UnallocatedFee:    new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), baseFee),
+		ClaimableFee:      new(big.Int),
+		CoinbaseFee:       new(big.Int).Set(coinbaseFee),
+		FeeSplit:          feeSplit,
+	}
 }
 
 func NewGasTracker() *GasTracker {
@@ -109,15 +287,15 @@ func updateGasPredeploy(state StateDB, contractAddress common.Address, fee *big.
 	updateGasParameters(state, contractAddress, gasParameters)
 }
 
-func readGasParameters(state StateDB, contractAddress common.Address) *GasParameters {
+func readGasParameters(state StateDB, contractAddress common.Address) (*GasParameters, error) {
 	slot := getContractStorageSlot(contractAddress)
 	gasStorageSlotBytes := state.GetState(params.PatexGasAddress, slot).Bytes()
 	gasParameters, err := unpack(gasStorageSlotBytes)
 	if err != nil {
-		// TODO(patex): remove this panic, should never happen
-		panic(err)
+		gasParamsInvalidCounter.Inc(1)
+		return nil, fmt.Errorf("invalid gas parameters of %v: %w", contractAddress, err)
 	}
-	return gasParameters
+	return gasParameters, nil
 }
 
 func updateGasParameters(state StateDB, contractAddress common.Address, gasParameters *GasParameters) {
@@ -170,14 +348,27 @@ func pack(params *GasParameters) []byte {
 		output[0] = 1
 	}
 
-	// This will panic if any of the values exceeds the buffer size
-	// See: https: //pkg.go.dev/math/big#Int.FillBytes
-	params.etherBalance.FillBytes(output[1:13])
-	params.etherSeconds.FillBytes(output[13:28])
-	params.lastUpdated.FillBytes(output[28:32])
+	// FillBytes panics if a value exceeds the buffer size, so values that do
+	// not fit into their field saturate at the largest value it can hold.
+	saturate(params.etherBalance, maxPackedEtherBalance).FillBytes(output[1:13])
+	saturate(params.etherSeconds, maxPackedEtherSeconds).FillBytes(output[13:28])
+	saturate(params.lastUpdated, maxPackedLastUpdated).FillBytes(output[28:32])
 	return output
 }
 
+// saturate clamps value into the range [0, max].
+func saturate(value *big.Int, max *big.Int) *big.Int {
+	if value.Sign() < 0 {
+		gasParamsSaturatedCounter.Inc(1)
+		return new(big.Int)
+	}
+	if value.Cmp(max) > 0 {
+		gasParamsSaturatedCounter.Inc(1)
+		return max
+	}
+	return value
+}
+
 func getContractStorageSlot(contractAddress common.Address) common.Hash {
 	slot := getHash(contractAddress, "parameters")
 	return slot
@@ -829,7 +829,7 @@ func opSelfdestruct(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext
 	}
 	beneficiary := scope.Stack.pop()
 
-	interpreter.evm.StateDB.SetFlags(scope.Contract.Address(), types.YieldAutomatic)
+	interpreter.evm.setFlags(scope.Contract.Address(), types.YieldAutomatic)
 	balance := interpreter.evm.StateDB.GetBalance(scope.Contract.Address())
 	interpreter.evm.StateDB.AddBalance(beneficiary.Bytes20(), balance)
 	interpreter.evm.StateDB.Suicide(scope.Contract.Address())
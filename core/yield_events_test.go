@@ -0,0 +1,116 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that yield mode changes made by the patex precompile, by contract creation
+// and by self-destruction are logged once the yield events fork is active, and
+// that claims of claimable yield are logged.
+func TestYieldEvents(t *testing.T) {
+	var (
+		sender  = common.HexToAddress("0x1000")
+		account = common.HexToAddress("0x2000")
+		// Init code deploying a contract that self-destructs when called.
+		initCode = common.FromHex("0x6133ff6000526002601ef3")
+	)
+	configure := func(account common.Address, flags uint8) []byte {
+		input := append(common.FromHex("0x3bdbe9a5"), common.BytesToHash(account.Bytes()).Bytes()...)
+		return append(input, common.BigToHash(big.NewInt(int64(flags))).Bytes()...)
+	}
+	claim := func(account, recipient common.Address, amount int64) []byte {
+		input := append(common.FromHex("0x996cba68"), common.BytesToHash(account.Bytes()).Bytes()...)
+		input = append(input, common.BytesToHash(recipient.Bytes()).Bytes()...)
+		return append(input, common.BigToHash(big.NewInt(amount)).Bytes()...)
+	}
+	run := func(yieldEventsTime *uint64) ([]*types.Log, common.Address) {
+		config := *params.TestChainConfig
+		config.BedrockBlock = big.NewInt(0)
+		config.YieldEventsTime = yieldEventsTime
+		config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.AddBalance(sender, big.NewInt(params.Ether))
+		statedb.SetState(params.PatexSharesAddress, state.SharePriceSlot, common.BigToHash(common.Big1))
+
+		header := &types.Header{Number: big.NewInt(5), Time: 10, Difficulty: common.Big0, BaseFee: common.Big0, GasLimit: 10_000_000}
+		evm := vm.NewEVM(NewEVMBlockContext(header, nil, &common.Address{}, &config, statedb), vm.TxContext{}, statedb, &config, vm.Config{})
+
+		// Create the contract, and destroy it once its yield mode was changed.
+		_, contract, _, err := evm.Create(vm.AccountRef(sender), initCode, 1_000_000, new(big.Int), vm.NewGasTracker())
+		if err != nil {
+			t.Fatalf("failed to create contract: %v", err)
+		}
+		if _, _, err := evm.Call(vm.AccountRef(sender), contract, nil, 1_000_000, new(big.Int), vm.NewGasTracker()); err != nil {
+			t.Fatalf("failed to destroy contract: %v", err)
+		}
+		// Make the account claimable, and claim its yield once it accrued some.
+		statedb.AddBalance(account, big.NewInt(100))
+		if _, _, err := evm.Call(vm.AccountRef(params.PatexAccountConfigurationAddress), params.PatexPrecompileAddress, configure(account, types.YieldClaimable), 1_000_000, new(big.Int), vm.NewGasTracker()); err != nil {
+			t.Fatalf("failed to configure account: %v", err)
+		}
+		statedb.SetState(params.PatexSharesAddress, state.SharePriceSlot, common.BigToHash(common.Big2))
+		if _, _, err := evm.Call(vm.AccountRef(params.PatexAccountConfigurationAddress), params.PatexPrecompileAddress, claim(account, sender, 50), 1_000_000, new(big.Int), vm.NewGasTracker()); err != nil {
+			t.Fatalf("failed to claim yield: %v", err)
+		}
+		return statedb.Logs(), contract
+	}
+	// Before the fork, no yield events are emitted.
+	if logs, _ := run(nil); len(logs) != 0 {
+		t.Fatalf("yield events emitted before the fork: %v", logs)
+	}
+	logs, contract := run(new(uint64))
+	if len(logs) != 4 {
+		t.Fatalf("yield event count mismatch: have %d, want 4", len(logs))
+	}
+	configured := []types.YieldConfigured{
+		{Account: contract, PreviousFlags: types.YieldAutomatic, Flags: types.YieldDisabled}, // creation
+		{Account: contract, PreviousFlags: types.YieldDisabled, Flags: types.YieldAutomatic}, // self-destruction
+		{Account: account, PreviousFlags: types.YieldAutomatic, Flags: types.YieldClaimable}, // precompile
+	}
+	for i, want := range configured {
+		have, err := types.ParseYieldConfiguredLog(logs[i])
+		if err != nil {
+			t.Fatalf("log %d: not a yield configuration event: %v", i, err)
+		}
+		if *have != want {
+			t.Errorf("log %d: yield configuration mismatch: have %+v, want %+v", i, have, want)
+		}
+	}
+	claimed, err := types.ParseYieldClaimedLog(logs[3])
+	if err != nil {
+		t.Fatalf("log 3: not a yield claim event: %v", err)
+	}
+	if claimed.Account != account || claimed.Recipient != sender || claimed.Amount.Int64() != 50 {
+		t.Errorf("yield claim mismatch: have %+v", claimed)
+	}
+	for i, log := range logs {
+		if log.BlockNumber != 5 {
+			t.Errorf("log %d: block number mismatch: have %d, want 5", i, log.BlockNumber)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxPatexBlockRange is the maximum number of blocks a single range query of
+// the patex namespace may span, as every block in the range needs its state.
+const maxPatexBlockRange = 1024
+
+// PatexAPI provides an API to access the yield-bearing account state of a Patex
+// chain, such as share prices and accrued yield.
+type PatexAPI struct {
+	eth *Ethereum
+}
+
+// NewPatexAPI creates a new PatexAPI instance.
+func NewPatexAPI(eth *Ethereum) *PatexAPI {
+	return &PatexAPI{eth: eth}
+}
+
+// SharePriceResult is the state of the yield shares at a block.
+type SharePriceResult struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	SharePrice  *hexutil.Big   `json:"sharePrice"`
+	ShareCount  *hexutil.Big   `json:"shareCount"`
+}
+
+// GetSharePriceHistory returns the share price and total share count at the end
+// of every block in the inclusive range [fromBlock, toBlock].
+func (api *PatexAPI) GetSharePriceHistory(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) ([]*SharePriceResult, error) {
+	from, to, err := api.resolveRange(ctx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*SharePriceResult, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		statedb, err := api.stateAt(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &SharePriceResult{
+			BlockNumber: hexutil.Uint64(number),
+			SharePrice:  (*hexutil.Big)(statedb.GetSharePrice()),
+			ShareCount:  (*hexutil.Big)(statedb.GetShareCount()),
+		})
+	}
+	return results, nil
+}
+
+// AccruedYieldResult is the yield an account accrued over a range of blocks.
+type AccruedYieldResult struct {
+	Address   common.Address `json:"address"`
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+	Flags     hexutil.Uint64 `json:"flags"`
+	Yield     *hexutil.Big   `json:"yield"`
+}
+
+// GetAccruedYield returns the yield the given account accrued after fromBlock up
+// to and including toBlock. Yield only accrues when the share price changes, so
+// it is the sum of the share price changes over the range, each weighted by the
+// shares the account held before the change. Transfers in and out of the account
+// do not count as yield.
+func (api *PatexAPI) GetAccruedYield(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber) (*AccruedYieldResult, error) {
+	from, to, err := api.resolveRange(ctx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := api.stateAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		yield     = new(big.Int)
+		prevPrice = prev.GetSharePrice()
+	)
+	for number := from + 1; number <= to; number++ {
+		statedb, err := api.stateAt(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if price := statedb.GetSharePrice(); price.Cmp(prevPrice) != 0 {
+			delta := new(big.Int).Sub(price, prevPrice)
+			yield.Add(yield, delta.Mul(delta, prev.GetBalanceValues(address).Shares))
+			prevPrice = price
+		}
+		prev = statedb
+	}
+	return &AccruedYieldResult{
+		Address:   address,
+		FromBlock: hexutil.Uint64(from),
+		ToBlock:   hexutil.Uint64(to),
+		Flags:     hexutil.Uint64(prev.GetFlags(address)),
+		Yield:     (*hexutil.Big)(yield),
+	}, nil
+}
+
+// YieldModeChange is an account whose yield mode was changed by a block.
+type YieldModeChange struct {
+	Address       common.Address `json:"address"`
+	PreviousFlags hexutil.Uint64 `json:"previousFlags"`
+	Flags         hexutil.Uint64 `json:"flags"`
+}
+
+// GetYieldModeChanges returns the accounts whose yield mode differs between the
+// state of the given block and the state of its parent, in the order they were
+// first changed. The changes are gathered from the yield events of the block, so
+// they are only available from the yield events fork on.
+func (api *PatexAPI) GetYieldModeChanges(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*YieldModeChange, error) {
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("block not found")
+	}
+	if rules := api.eth.blockchain.Config().Rules(header.Number, false, header.Time); !rules.IsPatexYieldEvents {
+		return nil, fmt.Errorf("yield events are not emitted by block %d", header.Number)
+	}
+	receipts, err := api.eth.APIBackend.GetReceipts(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		changes = []*YieldModeChange{} // Empty rather than null if there are none
+		changed = make(map[common.Address]*YieldModeChange)
+	)
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			event, err := types.ParseYieldConfiguredLog(log)
+			if err != nil {
+				continue
+			}
+			if change, ok := changed[event.Account]; ok {
+				change.Flags = hexutil.Uint64(event.Flags)
+				continue
+			}
+			change := &YieldModeChange{
+				Address:       event.Account,
+				PreviousFlags: hexutil.Uint64(event.PreviousFlags),
+				Flags:         hexutil.Uint64(event.Flags),
+			}
+			changed[event.Account] = change
+			changes = append(changes, change)
+		}
+	}
+	// Drop the accounts whose yield mode was changed back within the block.
+	result := changes[:0]
+	for _, change := range changes {
+		if change.PreviousFlags != change.Flags {
+			result = append(result, change)
+		}
+	}
+	return result, nil
+}
+
+// GasParametersResult is the decoded gas fee sharing state of a contract.
+type GasParametersResult struct {
+	Address      common.Address `json:"address"`
+	BlockNumber  hexutil.Uint64 `json:"blockNumber"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	Mode         bool           `json:"mode"`
+	EtherBalance *hexutil.Big   `json:"etherBalance"`
+	EtherSeconds *hexutil.Big   `json:"etherSeconds"`
+	LastUpdated  hexutil.Uint64 `json:"lastUpdated"`
+
+	// EtherSecondsAtBlock is the ether seconds accrued up to the block timestamp,
+	// from which the gas predeploy derives the claimable share of EtherBalance.
+	EtherSecondsAtBlock *hexutil.Big `json:"etherSecondsAtBlock"`
+}
+
+// GetGasParameters returns the gas parameters of the given contract, as packed
+// into its slot of the gas predeploy, together with the ether seconds accrued
+// up to the timestamp of the requested block.
+func (api *PatexAPI) GetGasParameters(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*GasParametersResult, error) {
+	statedb, header, err := api.eth.APIBackend.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	gasParams, err := vm.ReadGasParameters(statedb, address)
+	if err != nil {
+		return nil, err
+	}
+	return &GasParametersResult{
+		Address:             address,
+		BlockNumber:         hexutil.Uint64(header.Number.Uint64()),
+		Timestamp:           hexutil.Uint64(header.Time),
+		Mode:                gasParams.Mode(),
+		EtherBalance:        (*hexutil.Big)(gasParams.EtherBalance()),
+		EtherSeconds:        (*hexutil.Big)(gasParams.EtherSeconds()),
+		LastUpdated:         hexutil.Uint64(gasParams.LastUpdated()),
+		EtherSecondsAtBlock: (*hexutil.Big)(gasParams.EtherSecondsAt(header.Time)),
+	}, statedb.Error()
+}
+
+// GetDepositBySourceHash returns the deposit transaction with the given source
+// hash, or nil if no canonical block includes it.
+func (api *PatexAPI) GetDepositBySourceHash(ctx context.Context, sourceHash common.Hash) (*ethapi.RPCDeposit, error) {
+	tx, blockHash, blockNumber, index := rawdb.ReadDeposit(api.eth.ChainDb(), sourceHash)
+	if tx == nil {
+		return nil, nil
+	}
+	receipts, err := api.eth.APIBackend.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	var receipt *types.Receipt
+	if index < uint64(len(receipts)) {
+		receipt = receipts[index]
+	}
+	return ethapi.NewRPCDeposit(tx, receipt, blockHash, blockNumber, index, api.eth.blockchain.Config()), nil
+}
+
+// GetBlockDeposits returns the deposit transactions of the given block and the
+// total value they minted.
+func (api *PatexAPI) GetBlockDeposits(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*ethapi.RPCBlockDeposits, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	receipts, err := api.eth.APIBackend.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return ethapi.NewRPCBlockDeposits(block, receipts, api.eth.blockchain.Config()), nil
+}
+
+// FeeStatsResult summarizes the fees collected by the blocks in the inclusive
+// range [FromBlock, ToBlock].
+type FeeStatsResult struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+
+	DepositCount   hexutil.Uint64 `json:"depositCount"`
+	DepositGasUsed hexutil.Uint64 `json:"depositGasUsed"`
+	UserTxCount    hexutil.Uint64 `json:"userTxCount"`
+	UserGasUsed    hexutil.Uint64 `json:"userGasUsed"`
+
+	RollupDataGas hexutil.Uint64 `json:"rollupDataGas"`
+	L1GasUsed     hexutil.Uint64 `json:"l1GasUsed"`
+	L1Fee         *hexutil.Big   `json:"l1Fee"`
+	L1DataCost    *hexutil.Big   `json:"l1DataCost"`
+
+	BaseFeeRecipientFee *hexutil.Big `json:"baseFeeRecipientFee"`
+	ClaimableFee        *hexutil.Big `json:"claimableFee"`
+	FeeSplitFee         *hexutil.Big `json:"feeSplitFee"`
+	CoinbaseFee         *hexutil.Big `json:"coinbaseFee"`
+}
+
+func newFeeStatsResult(from, to uint64, stats *types.BlockFeeStats) *FeeStatsResult {
+	return &FeeStatsResult{
+		FromBlock:           hexutil.Uint64(from),
+		ToBlock:             hexutil.Uint64(to),
+		DepositCount:        hexutil.Uint64(stats.Deposits),
+		DepositGasUsed:      hexutil.Uint64(stats.DepositGasUsed),
+		UserTxCount:         hexutil.Uint64(stats.UserTxs),
+		UserGasUsed:         hexutil.Uint64(stats.UserGasUsed),
+		RollupDataGas:       hexutil.Uint64(stats.RollupDataGas),
+		L1GasUsed:           hexutil.Uint64(stats.L1GasUsed),
+		L1Fee:               (*hexutil.Big)(stats.Fees.L1Fee),
+		L1DataCost:          (*hexutil.Big)(stats.L1DataCost),
+		BaseFeeRecipientFee: (*hexutil.Big)(stats.Fees.BaseFeeRecipient),
+		ClaimableFee:        (*hexutil.Big)(stats.Fees.Claimable),
+		FeeSplitFee:         (*hexutil.Big)(stats.Fees.FeeSplit),
+		CoinbaseFee:         (*hexutil.Big)(stats.Fees.Coinbase),
+	}
+}
+
+// GetBlockFeeStats returns the fees collected by the given block, split by the
+// recipient they were credited to. The stats are recorded when a block is
+// processed if enabled, so they are not available for snap synced blocks nor
+// for the blocks processed before enabling them.
+func (api *PatexAPI) GetBlockFeeStats(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*FeeStatsResult, error) {
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("block not found")
+	}
+	stats, err := api.feeStatsAt(header.Hash(), header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	return newFeeStatsResult(header.Number.Uint64(), header.Number.Uint64(), stats), nil
+}
+
+// GetFeeStatsRange returns the fees collected by the blocks in the inclusive
+// range [fromBlock, toBlock], summed up over all blocks.
+func (api *PatexAPI) GetFeeStatsRange(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) (*FeeStatsResult, error) {
+	from, to, err := api.resolveRange(ctx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	total := types.NewBlockFeeStats()
+	for number := from; number <= to; number++ {
+		hash := rawdb.ReadCanonicalHash(api.eth.ChainDb(), number)
+		if hash == (common.Hash{}) {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		stats, err := api.feeStatsAt(hash, number)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(stats)
+	}
+	return newFeeStatsResult(from, to, total), nil
+}
+
+// PayloadTransaction is a transaction appended to a payload under construction,
+// along with the receipt of its execution on top of the payload so far.
+type PayloadTransaction struct {
+	PayloadID   engine.PayloadID   `json:"payloadId"`
+	Build       hexutil.Uint64     `json:"build"`
+	Index       hexutil.Uint64     `json:"transactionIndex"`
+	Transaction *types.Transaction `json:"transaction"`
+	Receipt     *types.Receipt     `json:"receipt"`
+}
+
+// PayloadTransactions streams the transactions appended to payloads while the
+// sequencer builds them, before the payloads are sealed. Payloads are rebuilt
+// from their parent block periodically, a notification with a higher build
+// number supersedes all transactions of earlier builds of the same payload.
+//
+// The transactions are exposed before they are sealed, so the subscription has
+// to be enabled explicitly. Notifications are dropped if the subscriber falls
+// behind, leaving gaps in the transaction indexes of a build.
+func (api *PatexAPI) PayloadTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	if !api.eth.config.RollupPayloadTxs {
+		return &rpc.Subscription{}, errors.New("payload transaction streaming is disabled")
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan miner.PayloadTxEvent, 128)
+		eventsSub := api.eth.Miner().SubscribePayloadTxs(events)
+		defer eventsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, &PayloadTransaction{
+					PayloadID:   ev.PayloadID,
+					Build:       hexutil.Uint64(ev.Build),
+					Index:       hexutil.Uint64(ev.Index),
+					Transaction: ev.Tx,
+					Receipt:     ev.Receipt,
+				})
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// feeStatsAt returns the recorded fee stats of the given block.
+func (api *PatexAPI) feeStatsAt(hash common.Hash, number uint64) (*types.BlockFeeStats, error) {
+	if !api.eth.config.FeeStats {
+		return nil, errors.New("fee stats are not recorded")
+	}
+	stats := rawdb.ReadBlockFeeStats(api.eth.ChainDb(), hash, number)
+	if stats == nil {
+		return nil, fmt.Errorf("fee stats for block %d not available", number)
+	}
+	return stats, nil
+}
+
+// resolveRange resolves the given block range to absolute block numbers and
+// checks that it is ordered and within the allowed span.
+func (api *PatexAPI) resolveRange(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) (uint64, uint64, error) {
+	fromHeader, err := api.eth.APIBackend.HeaderByNumber(ctx, fromBlock)
+	if err != nil {
+		return 0, 0, err
+	}
+	toHeader, err := api.eth.APIBackend.HeaderByNumber(ctx, toBlock)
+	if err != nil {
+		return 0, 0, err
+	}
+	if fromHeader == nil || toHeader == nil {
+		return 0, 0, errors.New("block not found")
+	}
+	from, to := fromHeader.Number.Uint64(), toHeader.Number.Uint64()
+	if from > to {
+		return 0, 0, fmt.Errorf("start block (%d) must not be after end block (%d)", from, to)
+	}
+	if to-from >= maxPatexBlockRange {
+		return 0, 0, fmt.Errorf("block range (%d) exceeds the maximum of %d blocks", to-from+1, maxPatexBlockRange)
+	}
+	return from, to, nil
+}
+
+// stateAt returns the state at the end of the given block.
+func (api *PatexAPI) stateAt(ctx context.Context, number uint64) (*state.StateDB, error) {
+	statedb, _, err := api.eth.APIBackend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil {
+		return nil, fmt.Errorf("state for block %d not available", number)
+	}
+	return statedb, nil
+}
@@ -0,0 +1,369 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	patexTestKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	patexTestAddr    = crypto.PubkeyToAddress(patexTestKey.PublicKey)
+	patexTestHolder  = common.HexToAddress("0x1000") // Yield earning account
+	patexTestAccount = common.HexToAddress("0x2000") // Account whose yield mode is changed
+
+	// patexTestStorageCode stores the second word of the call data in the slot
+	// given by the first word.
+	patexTestStorageCode = common.FromHex("0x6020356000355500")
+
+	// patexTestForwarderCode forwards the call data to the patex precompile.
+	patexTestForwarderCode = common.FromHex("0x3660006000376000600036600060006101005af100")
+
+	// configure(address,uint8) selector of the patex precompile
+	patexTestConfigure = common.FromHex("0x3bdbe9a5")
+
+	// patexTestGasContract accumulates gas fees: mode 1, etherBalance 100,
+	// etherSeconds 50 and lastUpdated 0, as packed into the gas predeploy.
+	patexTestGasContract = common.HexToAddress("0xc0de")
+	patexTestGasValue    = common.HexToHash("0x0100000000000000000000006400000000000000000000000000003200000000")
+)
+
+// newPatexTestAPI creates a Patex chain of the given number of blocks and a
+// PatexAPI serving it. Every block starts with an L1 info deposit.
+func newPatexTestAPI(t *testing.T, n int, gen func(int, *core.BlockGen)) (*PatexAPI, []*types.Block) {
+	config := *params.AllEthashProtocolChanges
+	config.TerminalTotalDifficulty = common.Big0
+	config.TerminalTotalDifficultyPassed = true
+	config.BedrockBlock = common.Big0
+	config.RegolithTime = new(uint64)
+	config.YieldEventsTime = new(uint64)
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	genesis := &core.Genesis{
+		Config:   &config,
+		GasLimit: 11_500_000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			patexTestAddr:   {Balance: big.NewInt(params.Ether)},
+			patexTestHolder: {Balance: big.NewInt(1000)},
+			types.L1BlockAddr: {
+				Nonce:   1, // Not empty, otherwise the storage is wiped
+				Balance: new(big.Int),
+			},
+			params.PatexSharesAddress: {
+				Code:    patexTestStorageCode,
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{state.SharePriceSlot: common.BigToHash(common.Big1)},
+			},
+			params.PatexAccountConfigurationAddress: {
+				Code:    patexTestForwarderCode,
+				Balance: new(big.Int),
+			},
+			params.PatexGasAddress: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{vm.GasParametersSlot(patexTestGasContract): patexTestGasValue},
+			},
+		},
+	}
+	engine := beacon.New(ethash.NewFaker())
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, n, func(i int, b *core.BlockGen) {
+		b.AddTx(types.NewTx(&types.DepositTx{To: &types.L1BlockAddr, Value: new(big.Int), Gas: 1_000_000, Data: make([]byte, 4+32*8)}))
+		if gen != nil {
+			gen(i, b)
+		}
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, &core.CacheConfig{TrieDirtyDisabled: true, FeeStats: true}, genesis, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	eth := &Ethereum{blockchain: chain, chainDb: db, config: &ethconfig.Config{FeeStats: true}}
+	eth.APIBackend = &EthAPIBackend{eth: eth}
+	return NewPatexAPI(eth), blocks
+}
+
+// patexTestCall adds a transaction calling the given address to the block.
+func patexTestCall(b *core.BlockGen, to *common.Address, data []byte) {
+	chainID := params.AllEthashProtocolChanges.ChainID
+	tx, _ := types.SignNewTx(patexTestKey, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     b.TxNonce(patexTestAddr),
+		To:        to,
+		Gas:       500_000,
+		GasFeeCap: new(big.Int).Mul(b.BaseFee(), common.Big2),
+		Data:      data,
+	})
+	b.AddTx(tx)
+}
+
+// patexTestConfigureCall returns the call data of the patex precompile changing
+// the yield mode of the given account.
+func patexTestConfigureCall(account common.Address, flags uint8) []byte {
+	return append(append(common.CopyBytes(patexTestConfigure), common.BytesToHash(account.Bytes()).Bytes()...), common.BigToHash(big.NewInt(int64(flags))).Bytes()...)
+}
+
+func TestPatexSharePriceHistory(t *testing.T) {
+	// Raise the share price to 3 in the second block.
+	api, _ := newPatexTestAPI(t, 3, func(i int, b *core.BlockGen) {
+		if i == 1 {
+			patexTestCall(b, &params.PatexSharesAddress, append(state.SharePriceSlot.Bytes(), common.BigToHash(big.NewInt(3)).Bytes()...))
+		}
+	})
+	ctx := context.Background()
+
+	history, err := api.GetSharePriceHistory(ctx, 0, 3)
+	if err != nil {
+		t.Fatalf("failed to retrieve share price history: %v", err)
+	}
+	var prices []uint64
+	for i, result := range history {
+		if uint64(result.BlockNumber) != uint64(i) {
+			t.Errorf("result %d: block number mismatch: have %d", i, result.BlockNumber)
+		}
+		prices = append(prices, result.SharePrice.ToInt().Uint64())
+	}
+	if want := []uint64{1, 1, 3, 3}; !reflect.DeepEqual(prices, want) {
+		t.Errorf("share prices mismatch: have %v, want %v", prices, want)
+	}
+	// The holder earned 2 wei for each of its 1000 shares.
+	yield, err := api.GetAccruedYield(ctx, patexTestHolder, 0, 3)
+	if err != nil {
+		t.Fatalf("failed to retrieve accrued yield: %v", err)
+	}
+	if yield.Yield.ToInt().Int64() != 2000 || yield.Flags != types.YieldAutomatic {
+		t.Errorf("accrued yield mismatch: have %v (flags %d), want 2000", yield.Yield, yield.Flags)
+	}
+	if yield, err = api.GetAccruedYield(ctx, patexTestHolder, 2, 3); err != nil {
+		t.Fatalf("failed to retrieve accrued yield: %v", err)
+	}
+	if yield.Yield.ToInt().Sign() != 0 {
+		t.Errorf("accrued yield after price change mismatch: have %v, want 0", yield.Yield)
+	}
+	// Reversed ranges and ranges beyond the chain head are rejected.
+	if _, err := api.GetSharePriceHistory(ctx, 2, 1); err == nil {
+		t.Error("reversed range accepted")
+	}
+	if _, err := api.GetSharePriceHistory(ctx, 0, rpc.BlockNumber(maxPatexBlockRange)); err == nil {
+		t.Error("range beyond the chain head accepted")
+	}
+}
+
+func TestPatexYieldModeChanges(t *testing.T) {
+	api, blocks := newPatexTestAPI(t, 3, func(i int, b *core.BlockGen) {
+		switch i {
+		case 0:
+			// Contracts are created with yield disabled.
+			patexTestCall(b, nil, []byte{0x00})
+		case 1:
+			// Make the account claimable, and the holder disabled and automatic
+			// again, which is not a change of the block.
+			patexTestCall(b, &params.PatexAccountConfigurationAddress, patexTestConfigureCall(patexTestAccount, types.YieldClaimable))
+			patexTestCall(b, &params.PatexAccountConfigurationAddress, patexTestConfigureCall(patexTestHolder, types.YieldDisabled))
+			patexTestCall(b, &params.PatexAccountConfigurationAddress, patexTestConfigureCall(patexTestHolder, types.YieldAutomatic))
+		}
+	})
+	ctx := context.Background()
+	contract := crypto.CreateAddress(patexTestAddr, 0)
+
+	tests := []struct {
+		block uint64
+		want  []*YieldModeChange
+	}{
+		{1, []*YieldModeChange{{Address: contract, PreviousFlags: types.YieldAutomatic, Flags: types.YieldDisabled}}},
+		{2, []*YieldModeChange{{Address: patexTestAccount, PreviousFlags: types.YieldAutomatic, Flags: types.YieldClaimable}}},
+		{3, []*YieldModeChange{}},
+	}
+	for _, tt := range tests {
+		have, err := api.GetYieldModeChanges(ctx, rpc.BlockNumberOrHashWithHash(blocks[tt.block-1].Hash(), true))
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve yield mode changes: %v", tt.block, err)
+		}
+		if have == nil || len(have) != len(tt.want) {
+			t.Fatalf("block %d: change count mismatch: have %v, want %d changes", tt.block, have, len(tt.want))
+		}
+		for i := range have {
+			if *have[i] != *tt.want[i] {
+				t.Errorf("block %d: change %d mismatch: have %+v, want %+v", tt.block, i, have[i], tt.want[i])
+			}
+		}
+	}
+	// The yield modes are reflected in the state.
+	statedb, err := api.stateAt(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags := hexutil.Uint64(statedb.GetFlags(patexTestAccount)); flags != types.YieldClaimable {
+		t.Errorf("yield mode mismatch: have %d, want %d", flags, types.YieldClaimable)
+	}
+}
+
+func TestPatexGasParameters(t *testing.T) {
+	api, blocks := newPatexTestAPI(t, 2, nil)
+	ctx := context.Background()
+
+	result, err := api.GetGasParameters(ctx, patexTestGasContract, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		t.Fatalf("failed to retrieve gas parameters: %v", err)
+	}
+	head := blocks[len(blocks)-1]
+	if result.Address != patexTestGasContract || uint64(result.BlockNumber) != head.NumberU64() || uint64(result.Timestamp) != head.Time() {
+		t.Fatalf("gas parameters of wrong contract or block: %+v", result)
+	}
+	if !result.Mode || result.EtherBalance.ToInt().Int64() != 100 || result.EtherSeconds.ToInt().Int64() != 50 || result.LastUpdated != 0 {
+		t.Errorf("gas parameters mismatch: have %+v", result)
+	}
+	// The ether balance was held since the last update.
+	if want := int64(50 + 100*head.Time()); result.EtherSecondsAtBlock.ToInt().Int64() != want {
+		t.Errorf("ether seconds at block mismatch: have %v, want %d", result.EtherSecondsAtBlock, want)
+	}
+	// Contracts without gas parameters decode to all zeroes.
+	if result, err = api.GetGasParameters(ctx, patexTestAddr, rpc.BlockNumberOrHashWithNumber(0)); err != nil {
+		t.Fatalf("failed to retrieve gas parameters: %v", err)
+	}
+	if result.Mode || result.EtherBalance.ToInt().Sign() != 0 || result.EtherSecondsAtBlock.ToInt().Sign() != 0 || result.BlockNumber != 0 {
+		t.Errorf("gas parameters of plain account mismatch: have %+v", result)
+	}
+}
+
+func TestPatexDeposits(t *testing.T) {
+	source := func(i int) common.Hash { return common.BigToHash(big.NewInt(int64(0x5ec + i))) }
+	api, blocks := newPatexTestAPI(t, 2, func(i int, b *core.BlockGen) {
+		b.AddTx(types.NewTx(&types.DepositTx{SourceHash: source(i), From: patexTestAccount, To: &patexTestAccount, Mint: big.NewInt(int64(100 * (i + 1))), Value: new(big.Int), Gas: params.TxGas}))
+	})
+	ctx := context.Background()
+
+	for i, block := range blocks {
+		deposit, err := api.GetDepositBySourceHash(ctx, source(i))
+		if err != nil {
+			t.Fatalf("deposit %d: lookup failed: %v", i, err)
+		}
+		if deposit == nil || deposit.TransactionHash != block.Transactions()[1].Hash() {
+			t.Fatalf("deposit %d: lookup mismatch: have %+v", i, deposit)
+		}
+		if deposit.BlockHash != block.Hash() || uint64(deposit.BlockNumber) != block.NumberU64() || deposit.TransactionIndex != 1 {
+			t.Errorf("deposit %d: position mismatch: have %+v", i, deposit)
+		}
+		if deposit.From != patexTestAccount || deposit.Mint.ToInt().Int64() != int64(100*(i+1)) || deposit.Status == nil || uint64(*deposit.Status) != types.ReceiptStatusSuccessful {
+			t.Errorf("deposit %d: content mismatch: have %+v", i, deposit)
+		}
+	}
+	if deposit, err := api.GetDepositBySourceHash(ctx, source(len(blocks))); deposit != nil || err != nil {
+		t.Errorf("unknown deposit found: %+v, %v", deposit, err)
+	}
+	// Every block holds the L1 info deposit and the test deposit.
+	result, err := api.GetBlockDeposits(ctx, rpc.BlockNumberOrHashWithNumber(2))
+	if err != nil {
+		t.Fatalf("failed to retrieve block deposits: %v", err)
+	}
+	if result.BlockHash != blocks[1].Hash() || len(result.Deposits) != 2 || result.TotalMint.ToInt().Int64() != 200 {
+		t.Fatalf("block deposits mismatch: have %+v", result)
+	}
+	if result.Deposits[0].To == nil || *result.Deposits[0].To != types.L1BlockAddr || result.Deposits[1].SourceHash != source(1) {
+		t.Errorf("block deposits order mismatch: have %+v, %+v", result.Deposits[0], result.Deposits[1])
+	}
+	if _, err := api.GetBlockDeposits(ctx, rpc.BlockNumberOrHashWithNumber(3)); err == nil {
+		t.Errorf("deposits of missing block returned")
+	}
+}
+
+func TestPatexFeeStats(t *testing.T) {
+	// Every block holds the L1 info deposit and one more user transaction than
+	// the previous one.
+	api, blocks := newPatexTestAPI(t, 3, func(i int, b *core.BlockGen) {
+		for j := 0; j <= i; j++ {
+			patexTestCall(b, &patexTestHolder, nil)
+		}
+	})
+	ctx := context.Background()
+
+	var gasUsed uint64
+	for i, block := range blocks {
+		result, err := api.GetBlockFeeStats(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve fee stats: %v", i, err)
+		}
+		if uint64(result.FromBlock) != block.NumberU64() || uint64(result.ToBlock) != block.NumberU64() {
+			t.Errorf("block %d: range mismatch: have %d-%d", i, result.FromBlock, result.ToBlock)
+		}
+		if result.DepositCount != 1 || uint64(result.UserTxCount) != uint64(i+1) {
+			t.Errorf("block %d: transaction count mismatch: have %d deposits and %d user txs, want 1 and %d", i, result.DepositCount, result.UserTxCount, i+1)
+		}
+		if uint64(result.DepositGasUsed+result.UserGasUsed) != block.GasUsed() {
+			t.Errorf("block %d: gas used mismatch: have %d, want %d", i, result.DepositGasUsed+result.UserGasUsed, block.GasUsed())
+		}
+		// The fees of plain transfers are not claimable by any contract.
+		if result.ClaimableFee.ToInt().Sign() != 0 || result.BaseFeeRecipientFee.ToInt().Sign() <= 0 {
+			t.Errorf("block %d: fee mismatch: have %v claimable and %v unallocated", i, result.ClaimableFee, result.BaseFeeRecipientFee)
+		}
+		gasUsed += block.GasUsed()
+	}
+	result, err := api.GetFeeStatsRange(ctx, 1, rpc.BlockNumber(len(blocks)))
+	if err != nil {
+		t.Fatalf("failed to retrieve fee stats range: %v", err)
+	}
+	if result.FromBlock != 1 || uint64(result.ToBlock) != uint64(len(blocks)) {
+		t.Errorf("range mismatch: have %d-%d, want 1-%d", result.FromBlock, result.ToBlock, len(blocks))
+	}
+	if uint64(result.DepositCount) != uint64(len(blocks)) || result.UserTxCount != 6 || uint64(result.DepositGasUsed+result.UserGasUsed) != gasUsed {
+		t.Errorf("summed fee stats mismatch: have %+v", result)
+	}
+	// Missing blocks and reversed ranges are rejected.
+	if _, err := api.GetBlockFeeStats(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(len(blocks)+1))); err == nil {
+		t.Errorf("fee stats of missing block returned")
+	}
+	if _, err := api.GetFeeStatsRange(ctx, 2, 1); err == nil {
+		t.Errorf("fee stats of reversed range returned")
+	}
+	if _, err := api.GetFeeStatsRange(ctx, 1, rpc.BlockNumber(len(blocks)+1)); err == nil {
+		t.Errorf("fee stats of range beyond the head returned")
+	}
+	// Fee stats are not served unless recorded.
+	api.eth.config.FeeStats = false
+	if _, err := api.GetBlockFeeStats(ctx, rpc.BlockNumberOrHashWithNumber(1)); err == nil {
+		t.Errorf("fee stats returned without recording them")
+	}
+}
+
+// Tests that the transactions of payloads under construction are not streamed
+// unless explicitly enabled.
+func TestPatexPayloadTransactionsDisabled(t *testing.T) {
+	api, _ := newPatexTestAPI(t, 1, nil)
+	if _, err := api.PayloadTransactions(context.Background()); err == nil {
+		t.Fatal("payload transactions streamed without being enabled")
+	}
+}
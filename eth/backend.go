@@ -18,14 +18,12 @@
 package eth
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"runtime"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -35,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -45,9 +44,11 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/eth/sequencer"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/internal/shutdowncheck"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
@@ -76,8 +77,9 @@ type Ethereum struct {
 	snapDialCandidates enode.Iterator
 	merger             *consensus.Merger
 
-	seqRPCService        *rpc.Client
-	historicalRPCService *rpc.Client
+	seqForwarder     *sequencer.Forwarder
+	seqMirror        *sequencer.Mirror
+	historicalRouter *historical.Router
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
@@ -88,6 +90,7 @@ type Ethereum struct {
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
+	logIndex          *logindex.Indexer              // Log index serving log searches, nil if disabled
 	closeBloomHandler chan struct{}
 
 	APIBackend *EthAPIBackend
@@ -116,6 +119,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	if config.RollupMempoolMirror != "" && !config.RollupDisableTxPoolGossip {
+		return nil, errors.New("mirroring the sequencer mempool requires transaction pool gossip to be disabled")
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Cmp(common.Big0) <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -195,6 +201,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			TrieTimeLimit:       config.TrieTimeout,
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
+			FeeStats:            config.FeeStats,
 		}
 	)
 	// Override the chain config with provided settings.
@@ -226,6 +233,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.LogIndex {
+		eth.logIndex = logindex.New(chainDb, eth.blockchain, config.LogIndexLimit)
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
@@ -279,23 +289,28 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 
 	if config.RollupSequencerHTTP != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		client, err := rpc.DialContext(ctx, config.RollupSequencerHTTP)
-		cancel()
+		forwarder, err := sequencer.NewForwarder(sequencer.Config{
+			Endpoints:           sequencer.SplitEndpoints(config.RollupSequencerHTTP),
+			Retries:             config.RollupSequencerRetries,
+			HealthCheckInterval: config.RollupSequencerHealthCheckInterval,
+			Resend:              eth.retainedTransactions,
+		})
 		if err != nil {
 			return nil, err
 		}
-		eth.seqRPCService = client
+		eth.seqForwarder = forwarder
 	}
 
 	if config.RollupHistoricalRPC != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), config.RollupHistoricalRPCTimeout)
-		client, err := rpc.DialContext(ctx, config.RollupHistoricalRPC)
-		cancel()
+		endpoints, err := historical.ParseEndpoints(config.RollupHistoricalRPC)
+		if err != nil {
+			return nil, err
+		}
+		router, err := historical.NewRouter(endpoints, config.RollupHistoricalRPCTimeout, config.RollupHistoricalRPCCacheSize)
 		if err != nil {
 			return nil, err
 		}
-		eth.historicalRPCService = client
+		eth.historicalRouter = router
 	}
 
 	// Start the RPC service
@@ -357,6 +372,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
+		}, {
+			Namespace: "patex",
+			Service:   NewPatexAPI(s),
 		},
 	}...)
 }
@@ -443,6 +461,10 @@ func (s *Ethereum) SetEtherbase(etherbase common.Address) {
 // is already running, this method adjust the number of threads allowed to use
 // and updates the minimum price required by the transaction pool.
 func (s *Ethereum) StartMining(threads int) error {
+	// The mirrored sequencer transactions are not validated, don't mine them
+	if s.config.RollupMempoolMirror != "" {
+		return errors.New("mining is not supported while mirroring the sequencer mempool")
+	}
 	// Update the thread count within the consensus engine
 	type threaded interface {
 		SetThreads(threads int)
@@ -507,6 +529,17 @@ func (s *Ethereum) StopMining() {
 	s.miner.Stop()
 }
 
+// retainedTransactions returns the executable local transactions of the pool,
+// which were retained after being forwarded to the sequencer.
+func (s *Ethereum) retainedTransactions() types.Transactions {
+	var txs types.Transactions
+	for _, addr := range s.txPool.Locals() {
+		pending, _ := s.txPool.ContentFrom(addr)
+		txs = append(txs, pending...)
+	}
+	return txs
+}
+
 func (s *Ethereum) IsMining() bool      { return s.miner.Mining() }
 func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 
@@ -559,6 +592,11 @@ func (s *Ethereum) Start() error {
 	}
 	// Start the networking layer and the light server if requested
 	s.handler.Start(maxPeers)
+
+	// Start mirroring the sequencer mempool if requested
+	if s.config.RollupMempoolMirror != "" {
+		s.seqMirror = sequencer.NewMirror(s.config.RollupMempoolMirror, s.txPool)
+	}
 	return nil
 }
 
@@ -571,18 +609,22 @@ func (s *Ethereum) Stop() error {
 	s.handler.Stop()
 
 	// Then stop everything else.
+	if s.seqMirror != nil {
+		s.seqMirror.Close()
+	}
 	s.bloomIndexer.Close()
+	if s.logIndex != nil {
+		s.logIndex.Close()
+	}
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
 	s.miner.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
-	if s.seqRPCService != nil {
-		s.seqRPCService.Close()
-	}
-	if s.historicalRPCService != nil {
-		s.historicalRPCService.Close()
+	if s.seqForwarder != nil {
+		s.seqForwarder.Close()
 	}
+	s.historicalRouter.Close()
 
 	// Clean shutdown marker as the last thing before closing db
 	s.shutdownTracker.Stop()
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
@@ -34,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 // Register adds the engine API to the full node.
@@ -82,6 +84,7 @@ var caps = []string{
 	"engine_getPayloadV2",
 	"engine_newPayloadV1",
 	"engine_newPayloadV2",
+	"engine_newPayloadDryRunV2",
 	"engine_getPayloadBodiesByHashV1",
 	"engine_getPayloadBodiesByRangeV1",
 }
@@ -552,6 +555,82 @@ func (api *ConsensusAPI) newPayload(params engine.ExecutableData) (engine.Payloa
 	return engine.PayloadStatusV1{Status: engine.VALID, LatestValidHash: &hash}, nil
 }
 
+// NewPayloadDryRunV2 executes a payload on top of its parent and reports the
+// outcome, without importing the block or changing the forkchoice. The parent
+// block and its state must be available.
+func (api *ConsensusAPI) NewPayloadDryRunV2(params engine.ExecutableData) (*engine.PayloadValidationReport, error) {
+	if api.eth.BlockChain().Config().IsShanghai(params.Timestamp) {
+		if params.Withdrawals == nil {
+			return nil, engine.InvalidParams.With(fmt.Errorf("nil withdrawals post-shanghai"))
+		}
+	} else if params.Withdrawals != nil {
+		return nil, engine.InvalidParams.With(fmt.Errorf("non-nil withdrawals pre-shanghai"))
+	}
+	log.Trace("Engine API request received", "method", "NewPayloadDryRun", "number", params.Number, "hash", params.BlockHash)
+
+	report := &engine.PayloadValidationReport{
+		Status:       engine.INVALID,
+		BlockHash:    params.BlockHash,
+		Transactions: []*engine.TxValidationResult{},
+	}
+	invalid := func(err error) (*engine.PayloadValidationReport, error) {
+		errorMsg := err.Error()
+		report.ValidationError = &errorMsg
+		return report, nil
+	}
+	block, err := engine.ExecutableDataToBlock(params)
+	if err != nil {
+		return invalid(err)
+	}
+	bc := api.eth.BlockChain()
+	parent := bc.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("unknown parent %x", block.ParentHash())
+	}
+	statedb, err := bc.StateAt(parent.Root())
+	if err != nil {
+		return nil, fmt.Errorf("parent state %x not available: %w", parent.Root(), err)
+	}
+	if err := bc.Engine().VerifyHeader(bc, block.Header(), true); err != nil {
+		return invalid(err)
+	}
+	if err := bc.Validator().ValidateBody(block); err != nil && !errors.Is(err, core.ErrKnownBlock) {
+		return invalid(err)
+	}
+	receipts, _, _, usedGas, err := bc.Processor().Process(block, statedb, *bc.GetVMConfig())
+	if err != nil {
+		var txErr *core.TxApplyError
+		if errors.As(err, &txErr) {
+			index := hexutil.Uint64(txErr.Index)
+			report.FailedTxIndex = &index
+		}
+		return invalid(err)
+	}
+	if err := receipts.DeriveFields(bc.Config(), block.Hash(), block.NumberU64(), block.Time(), block.BaseFee(), block.Transactions()); err != nil {
+		return invalid(err)
+	}
+	for _, receipt := range receipts {
+		report.Transactions = append(report.Transactions, &engine.TxValidationResult{
+			Hash:    receipt.TxHash,
+			Status:  hexutil.Uint64(receipt.Status),
+			GasUsed: hexutil.Uint64(receipt.GasUsed),
+			L1Fee:   (*hexutil.Big)(receipt.L1Fee),
+		})
+	}
+	report.GasUsed = hexutil.Uint64(usedGas)
+
+	// Validate the result last, so the roots are reported for mismatching payloads.
+	stateErr := bc.Validator().ValidateState(block, statedb, receipts, usedGas)
+	stateRoot := statedb.IntermediateRoot(bc.Config().IsEIP158(block.Number()))
+	receiptsRoot := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+	report.StateRoot, report.ReceiptsRoot = &stateRoot, &receiptsRoot
+	if stateErr != nil {
+		return invalid(stateErr)
+	}
+	report.Status = engine.VALID
+	return report, nil
+}
+
 // delayPayloadImport stashes the given block away for import at a later time,
 // either via a forkchoice update or a sync extension. This method is meant to
 // be called by the newpayload command when the block seems to be ok, but some
@@ -875,6 +875,77 @@ func TestInvalidBloom(t *testing.T) {
 	}
 }
 
+func TestNewPayloadDryRun(t *testing.T) {
+	genesis, preMergeBlocks := generateMergeChain(10, false)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+
+	var (
+		api    = NewConsensusAPI(ethservice)
+		parent = preMergeBlocks[len(preMergeBlocks)-1]
+		signer = types.LatestSigner(ethservice.BlockChain().Config())
+	)
+	statedb, _ := ethservice.BlockChain().StateAt(parent.Root())
+	nonce := statedb.GetNonce(testAddr)
+	tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x1}, big.NewInt(1), params.TxGas, big.NewInt(2*params.InitialBaseFee), nil), signer, testKey)
+	ethservice.TxPool().AddLocal(tx)
+
+	execData, err := assembleWithTransactions(api, parent.Hash(), &engine.PayloadAttributes{
+		Timestamp: parent.Time() + 5,
+	}, 1)
+	if err != nil {
+		t.Fatalf("Failed to create the executable data %v", err)
+	}
+	head := ethservice.BlockChain().CurrentBlock().Hash()
+
+	// A valid payload reports the roots of the payload.
+	report, err := api.NewPayloadDryRunV2(*execData)
+	switch {
+	case err != nil:
+		t.Fatalf("Failed to dry-run payload: %v", err)
+	case report.Status != engine.VALID:
+		t.Fatalf("Invalid status: expected VALID got %v: %v", report.Status, *report.ValidationError)
+	case *report.StateRoot != execData.StateRoot || *report.ReceiptsRoot != execData.ReceiptsRoot:
+		t.Fatalf("Root mismatch: have %x/%x, want %x/%x", *report.StateRoot, *report.ReceiptsRoot, execData.StateRoot, execData.ReceiptsRoot)
+	case len(report.Transactions) != 1 || report.Transactions[0].Hash != tx.Hash() || uint64(report.Transactions[0].GasUsed) != params.TxGas:
+		t.Fatalf("Unexpected transaction results: %+v", report.Transactions)
+	}
+	if ethservice.BlockChain().GetBlockByHash(execData.BlockHash) != nil {
+		t.Fatalf("Dry-run payload was imported")
+	}
+	if ethservice.BlockChain().CurrentBlock().Hash() != head {
+		t.Fatalf("Dry-run payload changed the chain head")
+	}
+
+	// A state root mismatch reports the computed root.
+	badRoot := *execData
+	badRoot.StateRoot = common.Hash{0x1}
+	report, err = api.NewPayloadDryRunV2(*setBlockhash(&badRoot))
+	switch {
+	case err != nil:
+		t.Fatalf("Failed to dry-run payload: %v", err)
+	case report.Status != engine.INVALID || report.ValidationError == nil:
+		t.Fatalf("Invalid status: expected INVALID got %v", report.Status)
+	case *report.StateRoot != execData.StateRoot:
+		t.Fatalf("Root mismatch: have %x, want %x", *report.StateRoot, execData.StateRoot)
+	}
+
+	// A transaction that cannot be applied is identified.
+	badNonce, _ := types.SignTx(types.NewTransaction(nonce+1, common.Address{0x1}, big.NewInt(1), params.TxGas, big.NewInt(2*params.InitialBaseFee), nil), signer, testKey)
+	enc, _ := badNonce.MarshalBinary()
+	badTx := *execData
+	badTx.Transactions = [][]byte{enc}
+	report, err = api.NewPayloadDryRunV2(*setBlockhash(&badTx))
+	switch {
+	case err != nil:
+		t.Fatalf("Failed to dry-run payload: %v", err)
+	case report.Status != engine.INVALID:
+		t.Fatalf("Invalid status: expected INVALID got %v", report.Status)
+	case report.FailedTxIndex == nil || *report.FailedTxIndex != 0:
+		t.Fatalf("Failed transaction not identified: %v", report.FailedTxIndex)
+	}
+}
+
 func TestNewPayloadOnInvalidTerminalBlock(t *testing.T) {
 	genesis, preMergeBlocks := generateMergeChain(100, false)
 	n, ethservice := startEthService(t, genesis, preMergeBlocks)
@@ -136,6 +136,11 @@ type Config struct {
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
+	LogIndex      bool   `toml:",omitempty"` // Whether to maintain the log index for fast log searches
+	LogIndexLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose logs are indexed (0 = entire chain)
+
+	FeeStats bool `toml:",omitempty"` // Whether to record the fee stats of processed blocks
+
 	// RequiredBlocks is a set of block number -> hash mappings which must be in the
 	// canonical chain of all remote peers. Setting the option makes geth verify the
 	// presence of these blocks for every new peer connection.
@@ -213,10 +218,16 @@ type Config struct {
 	OverridePatexRegolith *uint64 `toml:",omitempty"`
 	OverridePatex         *bool
 
-	RollupSequencerHTTP        string
-	RollupHistoricalRPC        string
-	RollupHistoricalRPCTimeout time.Duration
-	RollupDisableTxPoolGossip  bool
+	RollupSequencerHTTP                string // Comma separated sequencer endpoints, in order of preference
+	RollupSequencerRetries             int
+	RollupSequencerHealthCheckInterval time.Duration
+	RollupHistoricalRPC                string
+	RollupHistoricalRPCTimeout         time.Duration
+	RollupHistoricalRPCCacheSize       int // Megabytes of memory allocated to caching historical RPC responses
+	RollupDisableTxPoolGossip          bool
+	RollupConditionalTxs               bool   // Whether eth_sendRawTransactionConditional is served
+	RollupPayloadTxs                   bool   // Whether the transactions of payloads under construction are streamed
+	RollupMempoolMirror                string // Sequencer websocket or IPC endpoint whose mempool is mirrored into the pool, requires RollupDisableTxPoolGossip
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain configuration.
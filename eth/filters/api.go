@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -241,6 +242,62 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	return rpcSub, nil
 }
 
+// Deposits creates a subscription that fires for every deposit transaction
+// included in a new chain head.
+func (api *FilterAPI) Deposits(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeNewHeads(headers)
+
+		for {
+			select {
+			case h := <-headers:
+				deposits, err := api.blockDeposits(h)
+				if err != nil {
+					log.Warn("Failed to retrieve deposits of new head", "number", h.Number, "hash", h.Hash(), "err", err)
+					continue
+				}
+				for _, deposit := range deposits {
+					notifier.Notify(rpcSub.ID, deposit)
+				}
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// blockDeposits returns the deposits included in the block of the given header.
+func (api *FilterAPI) blockDeposits(header *types.Header) ([]*ethapi.RPCDeposit, error) {
+	ctx := context.Background()
+	body, err := api.sys.backend.GetBody(ctx, header.Hash(), rpc.BlockNumber(header.Number.Int64()))
+	if err != nil {
+		return nil, err
+	}
+	if len(body.Transactions) == 0 || !body.Transactions[0].IsDepositTx() {
+		return nil, nil // Deposits always lead the block
+	}
+	receipts, err := api.sys.backend.GetReceipts(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	block := types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
+	return ethapi.NewRPCBlockDeposits(block, receipts, api.sys.backend.ChainConfig()).Deposits, nil
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
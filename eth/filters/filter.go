@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -101,6 +102,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		if err != nil {
 			return nil, err
 		}
+		if logs, routed, err := f.historicalBlockLogs(ctx, header); routed {
+			return logs, err
+		}
 		if header == nil {
 			return nil, errors.New("unknown block")
 		}
@@ -153,32 +157,114 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end, err = resolveSpecial(f.end); err != nil {
 		return nil, err
 	}
-	// Gather all indexed logs, and finish with non indexed ones
+	// Pre-Bedrock logs are retrieved from the historical backends, if any, and
+	// the remainder of the range is searched locally.
+	var logs []*types.Log
+	if router := f.sys.backend.HistoricalRouter(); router != nil && f.preBedrock(f.begin) {
+		bedrock := f.sys.backend.ChainConfig().BedrockBlock.Int64()
+		last := f.end
+		if last >= bedrock {
+			last = bedrock - 1
+		}
+		if logs, err = router.Logs(ctx, uint64(f.begin), uint64(last), f.addresses, f.topics); err != nil {
+			return nil, err
+		}
+		if f.end < bedrock {
+			return logs, nil
+		}
+		f.begin = bedrock
+	}
+	// Search the blocks covered by the log index using it, and the blocks
+	// before and after them by their bloom filters.
+	end := uint64(f.end)
+	if index := f.sys.backend.LogIndex(); index != nil && logindex.Selective(f.addresses, f.topics) {
+		if tail, head, ok := index.Range(); ok && uint64(f.begin) <= end && uint64(f.begin) <= head && end >= tail {
+			if uint64(f.begin) < tail {
+				found, err := f.bloomLogs(ctx, tail-1)
+				logs = append(logs, found...)
+				if err != nil {
+					return logs, err
+				}
+			}
+			last := end
+			if last > head {
+				last = head
+			}
+			found, err := f.logIndexLogs(ctx, index, last)
+			logs = append(logs, found...)
+			if err != nil {
+				return logs, err
+			}
+		}
+	}
+	rest, err := f.bloomLogs(ctx, end)
+	logs = append(logs, rest...)
+	if pending {
+		pendingLogs, err := f.pendingLogs()
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, pendingLogs...)
+	}
+	return logs, err
+}
+
+// bloomLogs returns the logs matching the filter criteria up to the given block,
+// using the bloom bits index where available and finishing with non indexed
+// blocks.
+func (f *Filter) bloomLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
+	if uint64(f.begin) > end {
+		return nil, nil
+	}
 	var (
 		logs           []*types.Log
-		end            = uint64(f.end)
 		size, sections = f.sys.backend.BloomStatus()
 	)
 	if indexed := sections * size; indexed > uint64(f.begin) {
+		var (
+			found []*types.Log
+			err   error
+		)
 		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
+			found, err = f.indexedLogs(ctx, end)
 		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
+			found, err = f.indexedLogs(ctx, indexed-1)
 		}
+		logs = append(logs, found...)
 		if err != nil {
 			return logs, err
 		}
 	}
 	rest, err := f.unindexedLogs(ctx, end)
-	logs = append(logs, rest...)
-	if pending {
-		pendingLogs, err := f.pendingLogs()
+	return append(logs, rest...), err
+}
+
+// logIndexLogs returns the logs matching the filter criteria up to the given
+// block based on the log index. The blocks must be covered by the index.
+func (f *Filter) logIndexLogs(ctx context.Context, index *logindex.Indexer, end uint64) ([]*types.Log, error) {
+	positions, err := index.Lookup(ctx, uint64(f.begin), end, f.addresses, f.topics)
+	if err != nil {
+		return nil, err
+	}
+	// The index lists the exact matches, but it may lag behind reorgs, so
+	// the logs are checked against the canonical blocks.
+	var logs []*types.Log
+	for i, pos := range positions {
+		if i > 0 && positions[i-1].Block == pos.Block {
+			continue
+		}
+		header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(pos.Block))
+		if header == nil || err != nil {
+			return logs, err
+		}
+		found, err := f.checkMatches(ctx, header)
 		if err != nil {
-			return nil, err
+			return logs, err
 		}
-		logs = append(logs, pendingLogs...)
+		logs = append(logs, found...)
 	}
-	return logs, err
+	f.begin = int64(end) + 1
+	return logs, nil
 }
 
 // indexedLogs returns the logs matching the filter criteria based on the bloom
@@ -250,6 +336,39 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 	return logs, nil
 }
 
+// historicalBlockLogs retrieves the logs of a pre-Bedrock block, or of a block
+// unknown locally, from the historical backends. It reports whether the request
+// was answered by them.
+func (f *Filter) historicalBlockLogs(ctx context.Context, header *types.Header) ([]*types.Log, bool, error) {
+	router := f.sys.backend.HistoricalRouter()
+	if router == nil {
+		return nil, false, nil
+	}
+	var (
+		logs  []*types.Log
+		query = map[string]interface{}{
+			"blockHash": *f.block,
+			"address":   f.addresses,
+			"topics":    f.topics,
+		}
+	)
+	if header == nil {
+		found, err := router.CallByHash(ctx, &logs, "eth_getLogs", query)
+		return logs, found || err != nil, err
+	}
+	if !f.preBedrock(header.Number.Int64()) {
+		return nil, false, nil
+	}
+	err := router.Call(ctx, &logs, header.Number.Uint64(), "eth_getLogs", query)
+	return logs, true, err
+}
+
+// preBedrock reports whether the given block predates the Bedrock upgrade.
+func (f *Filter) preBedrock(number int64) bool {
+	config := f.sys.backend.ChainConfig()
+	return config.BedrockBlock != nil && config.IsPatexPreBedrock(big.NewInt(number))
+}
+
 // blockLogs returns the logs matching the filter criteria within a single block.
 func (f *Filter) blockLogs(ctx context.Context, header *types.Header) ([]*types.Log, error) {
 	if bloomFilter(header.Bloom, f.addresses, f.topics) {
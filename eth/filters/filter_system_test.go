@@ -32,14 +32,17 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 type testBackend struct {
@@ -50,6 +53,7 @@ type testBackend struct {
 	rmLogsFeed      event.Feed
 	pendingLogsFeed event.Feed
 	chainFeed       event.Feed
+	logIndex        *logindex.Indexer
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -149,6 +153,14 @@ func (b *testBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, b.sections
 }
 
+func (b *testBackend) HistoricalRouter() *historical.Router {
+	return nil
+}
+
+func (b *testBackend) LogIndex() *logindex.Indexer {
+	return b.logIndex
+}
+
 func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	requests := make(chan chan *bloombits.Retrieval)
 
@@ -241,6 +253,66 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestDepositSubscription tests that a deposit subscription reports the deposits
+// of new chain heads along with the outcome of their execution.
+func TestDepositSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		api          = NewFilterAPI(sys, false)
+		to           = common.HexToAddress("0xd0")
+		nonce        = uint64(7)
+	)
+	deposit := types.NewTx(&types.DepositTx{SourceHash: common.HexToHash("0x5ec"), From: to, To: &to, Mint: big.NewInt(100), Value: big.NewInt(100), Gas: params.TxGas})
+	tx := types.NewTransaction(0, to, big.NewInt(1), params.TxGas, big.NewInt(1), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{deposit, tx}, nil, nil, trie.NewStackTrie(nil))
+	receipts := types.Receipts{
+		{Type: types.DepositTxType, Status: types.ReceiptStatusSuccessful, DepositNonce: &nonce, Logs: []*types.Log{}},
+		{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}},
+	}
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatalf("failed to register filter API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	deposits := make(chan *ethapi.RPCDeposit)
+	sub, err := client.EthSubscribe(context.Background(), deposits, "deposits")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	time.Sleep(1 * time.Second)
+	backend.chainFeed.Send(core.ChainEvent{Hash: block.Hash(), Block: block})
+
+	select {
+	case have := <-deposits:
+		if have.SourceHash != deposit.SourceHash() || have.TransactionHash != deposit.Hash() || have.BlockHash != block.Hash() {
+			t.Errorf("deposit mismatch: have %+v", have)
+		}
+		if have.Mint.ToInt().Int64() != 100 || have.Status == nil || *have.Status != 1 || have.DepositNonce == nil || *have.DepositNonce != 7 {
+			t.Errorf("deposit execution mismatch: have %+v", have)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("deposit not reported")
+	}
+	select {
+	case have := <-deposits:
+		t.Fatalf("unexpected deposit reported: %+v", have)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
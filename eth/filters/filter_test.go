@@ -21,13 +21,16 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -100,12 +103,28 @@ func BenchmarkFilters(b *testing.B) {
 	}
 }
 
+// testIndexChain is the chain of a test backend as seen by the log index.
+type testIndexChain struct {
+	backend *testBackend
+}
+
+func (c *testIndexChain) Config() *params.ChainConfig { return c.backend.ChainConfig() }
+
+func (c *testIndexChain) CurrentBlock() *types.Header { return c.backend.CurrentHeader() }
+
+func (c *testIndexChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
 func TestFilters(t *testing.T) {
 	var (
-		db, _   = rawdb.NewLevelDBDatabase(t.TempDir(), 0, 0, "", false)
-		_, sys  = newTestFilterSystem(t, db, Config{})
-		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
-		addr    = crypto.PubkeyToAddress(key1.PublicKey)
+		db, _        = rawdb.NewLevelDBDatabase(t.TempDir(), 0, 0, "", false)
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		key1, _      = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr         = crypto.PubkeyToAddress(key1.PublicKey)
 
 		hash1 = common.BytesToHash([]byte("topic1"))
 		hash2 = common.BytesToHash([]byte("topic2"))
@@ -179,6 +198,30 @@ func TestFilters(t *testing.T) {
 	// Set block 998 as Finalized (-3)
 	rawdb.WriteFinalizedBlockHash(db, chain[998].Hash())
 
+	testFilters(t, sys, addr, []common.Hash{hash1, hash2, hash3, hash4})
+
+	// Repeat the queries with the logs of the last 500 blocks indexed, the
+	// older blocks are searched by their bloom filters.
+	backend.logIndex = logindex.New(db, &testIndexChain{backend}, 500)
+	defer backend.logIndex.Close()
+
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if tail, head, _ := backend.logIndex.Range(); tail == 501 && head == 1000 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("logs not indexed")
+		}
+	}
+	testFilters(t, sys, addr, []common.Hash{hash1, hash2, hash3, hash4})
+}
+
+// testFilters checks the logs found by range filters on the test chain of
+// TestFilters, emitting a log with each of the given topics.
+func testFilters(t *testing.T, sys *FilterSystem, addr common.Address, hashes []common.Hash) {
+	t.Helper()
+
+	hash1, hash2, hash3, hash4 := hashes[0], hashes[1], hashes[2], hashes[3]
 	filter := sys.NewRangeFilter(0, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
 	logs, _ := filter.Logs(context.Background())
 	if len(logs) != 4 {
@@ -66,6 +66,9 @@ type processedFees struct {
 	reward               []*big.Int
 	baseFee, nextBaseFee *big.Int
 	gasUsedRatio         float64
+
+	// L1 data fee parameters, only set on rollup chains if the block is available
+	l1BaseFee, l1Overhead, l1Scalar *big.Int
 }
 
 // txGasAndReward is sorted in ascending order based on reward
@@ -99,6 +102,9 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 		bf.results.nextBaseFee = new(big.Int)
 	}
 	bf.results.gasUsedRatio = float64(bf.header.GasUsed) / float64(bf.header.GasLimit)
+	if chainconfig.IsPatex() && bf.block != nil {
+		bf.results.l1BaseFee, bf.results.l1Overhead, bf.results.l1Scalar = l1FeeParams(bf.block)
+	}
 	if len(percentiles) == 0 {
 		// rewards were not requested, return null
 		return
@@ -137,6 +143,18 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 	}
 }
 
+// l1FeeParams returns the L1 data fee parameters the L1Block predeploy held while
+// the given block was executed, read from the L1 info deposit that sets them at
+// the start of the block. Blocks without an L1 info deposit report zero values.
+func l1FeeParams(block *types.Block) (l1BaseFee, overhead, scalar *big.Int) {
+	if txs := block.Transactions(); len(txs) > 0 && txs[0].IsDepositTx() {
+		if l1BaseFee, overhead, scalar, err := types.L1InfoCostParams(txs[0].Data()); err == nil {
+			return l1BaseFee, overhead, scalar
+		}
+	}
+	return new(big.Int), new(big.Int), new(big.Int)
+}
+
 // resolveBlockRange resolves the specified block range to absolute block numbers while also
 // enforcing backend specific limitations. The pending block and corresponding receipts are
 // also returned if requested and available.
@@ -218,11 +236,16 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, reqEnd rpc.BlockNum
 //   - baseFee: base fee per gas in the given block
 //   - gasUsedRatio: gasUsed/gasLimit in the given block
 //
+// On rollup chains the L1 data fee parameters (L1 base fee, overhead and scalar) of each block
+// are returned as well, otherwise they are nil. They are read from the L1 info deposit of the
+// block, so the blocks are retrieved in full even if no reward percentiles are requested, but
+// their receipts are not.
+//
 // Note: baseFee includes the next block after the newest of the returned range, because this
 // value can be derived from the newest block.
-func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, *types.L1FeeHistory, error) {
 	if blocks < 1 {
-		return common.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+		return common.Big0, nil, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
 	}
 	maxFeeHistory := oracle.maxHeaderHistory
 	if len(rewardPercentiles) != 0 {
@@ -234,10 +257,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 	}
 	for i, p := range rewardPercentiles {
 		if p < 0 || p > 100 {
-			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+			return common.Big0, nil, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
 		}
 		if i > 0 && p < rewardPercentiles[i-1] {
-			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+			return common.Big0, nil, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
 		}
 	}
 	var (
@@ -247,11 +270,12 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 	)
 	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks)
 	if err != nil || blocks == 0 {
-		return common.Big0, nil, nil, nil, err
+		return common.Big0, nil, nil, nil, nil, err
 	}
 	oldestBlock := lastBlock + 1 - blocks
 
 	var (
+		patex   = oracle.backend.ChainConfig().IsPatex()
 		next    = oldestBlock
 		results = make(chan *blockFees, blocks)
 	)
@@ -281,10 +305,12 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 						fees.results = p
 						results <- fees
 					} else {
-						if len(rewardPercentiles) != 0 {
+						if len(rewardPercentiles) != 0 || patex {
 							fees.block, fees.err = oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
 							if fees.block != nil && fees.err == nil {
-								fees.receipts, fees.err = oracle.backend.GetReceipts(ctx, fees.block.Hash())
+								if len(rewardPercentiles) != 0 {
+									fees.receipts, fees.err = oracle.backend.GetReceipts(ctx, fees.block.Hash())
+								}
 								fees.header = fees.block.Header()
 							}
 						} else {
@@ -307,16 +333,27 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 		reward       = make([][]*big.Int, blocks)
 		baseFee      = make([]*big.Int, blocks+1)
 		gasUsedRatio = make([]float64, blocks)
+		l1History    *types.L1FeeHistory
 		firstMissing = blocks
 	)
+	if patex {
+		l1History = &types.L1FeeHistory{
+			L1BaseFee: make([]*big.Int, blocks),
+			Overhead:  make([]*big.Int, blocks),
+			Scalar:    make([]*big.Int, blocks),
+		}
+	}
 	for ; blocks > 0; blocks-- {
 		fees := <-results
 		if fees.err != nil {
-			return common.Big0, nil, nil, nil, fees.err
+			return common.Big0, nil, nil, nil, nil, fees.err
 		}
 		i := fees.blockNumber - oldestBlock
 		if fees.results.baseFee != nil {
 			reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = fees.results.reward, fees.results.baseFee, fees.results.nextBaseFee, fees.results.gasUsedRatio
+			if l1History != nil {
+				l1History.L1BaseFee[i], l1History.Overhead[i], l1History.Scalar[i] = fees.results.l1BaseFee, fees.results.l1Overhead, fees.results.l1Scalar
+			}
 		} else {
 			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
 			if i < firstMissing {
@@ -325,7 +362,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 		}
 	}
 	if firstMissing == 0 {
-		return common.Big0, nil, nil, nil, nil
+		return common.Big0, nil, nil, nil, nil, nil
 	}
 	if len(rewardPercentiles) != 0 {
 		reward = reward[:firstMissing]
@@ -333,5 +370,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks uint64, unresolvedL
 		reward = nil
 	}
 	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
-	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+	if l1History != nil {
+		l1History.L1BaseFee = l1History.L1BaseFee[:firstMissing]
+		l1History.Overhead = l1History.Overhead[:firstMissing]
+		l1History.Scalar = l1History.Scalar[:firstMissing]
+	}
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, l1History, nil
 }
@@ -20,8 +20,12 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -61,7 +65,7 @@ func TestFeeHistory(t *testing.T) {
 		backend := newTestBackend(t, big.NewInt(16), c.pending)
 		oracle := NewOracle(backend, config)
 
-		first, reward, baseFee, ratio, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
+		first, reward, baseFee, ratio, _, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
 		backend.teardown()
 		expReward := c.expCount
 		if len(c.percent) == 0 {
@@ -89,3 +93,89 @@ func TestFeeHistory(t *testing.T) {
 		}
 	}
 }
+
+func TestL1FeeParams(t *testing.T) {
+	data := make([]byte, 4+32*8)
+	big.NewInt(7).FillBytes(data[4+32*2 : 4+32*3])
+	big.NewInt(2100).FillBytes(data[4+32*6 : 4+32*7])
+	big.NewInt(1_000_000).FillBytes(data[4+32*7 : 4+32*8])
+
+	infoTx := types.NewTx(&types.DepositTx{Data: data})
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody([]*types.Transaction{infoTx}, nil)
+	l1BaseFee, overhead, scalar := l1FeeParams(block)
+	if l1BaseFee.Int64() != 7 || overhead.Int64() != 2100 || scalar.Int64() != 1_000_000 {
+		t.Errorf("unexpected L1 fee parameters: l1BaseFee %v, overhead %v, scalar %v", l1BaseFee, overhead, scalar)
+	}
+
+	// Blocks without an L1 info deposit report zero values
+	empty := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	l1BaseFee, overhead, scalar = l1FeeParams(empty)
+	if l1BaseFee.Sign() != 0 || overhead.Sign() != 0 || scalar.Sign() != 0 {
+		t.Errorf("unexpected L1 fee parameters for empty block: l1BaseFee %v, overhead %v, scalar %v", l1BaseFee, overhead, scalar)
+	}
+}
+
+// patexTestBackend is a test backend reporting a rollup chain config, counting
+// the blocks retrieved in full and the receipts retrieved.
+type patexTestBackend struct {
+	*testBackend
+	config   *params.ChainConfig
+	blocks   int32
+	receipts int32
+}
+
+func (b *patexTestBackend) ChainConfig() *params.ChainConfig { return b.config }
+
+func (b *patexTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	atomic.AddInt32(&b.blocks, 1)
+	return b.testBackend.BlockByNumber(ctx, number)
+}
+
+func (b *patexTestBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	atomic.AddInt32(&b.receipts, 1)
+	return b.testBackend.GetReceipts(ctx, hash)
+}
+
+// Tests that the fee history of a rollup chain reports the L1 fee parameters of
+// the blocks, retrieving their receipts only if reward percentiles are requested.
+func TestFeeHistoryL1Params(t *testing.T) {
+	backend := &patexTestBackend{testBackend: newTestBackend(t, big.NewInt(16), false)}
+	defer backend.teardown()
+
+	config := *backend.testBackend.ChainConfig()
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+	backend.config = &config
+
+	oracle := NewOracle(backend, Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+	_, _, baseFee, _, l1History, err := oracle.FeeHistory(context.Background(), 10, rpc.LatestBlockNumber, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve fee history: %v", err)
+	}
+	if len(baseFee) != 11 {
+		t.Errorf("fee history without percentiles mismatch: %d base fees", len(baseFee))
+	}
+	check := func(l1History *types.L1FeeHistory) {
+		t.Helper()
+
+		if l1History == nil || len(l1History.L1BaseFee) != 10 || len(l1History.Overhead) != 10 || len(l1History.Scalar) != 10 {
+			t.Fatalf("L1 history mismatch: %+v", l1History)
+		}
+		for i, fee := range l1History.L1BaseFee {
+			if fee == nil || fee.Sign() != 0 {
+				t.Errorf("block %d: L1 base fee mismatch: have %v, want 0", i, fee)
+			}
+		}
+	}
+	check(l1History)
+	if receipts := atomic.LoadInt32(&backend.receipts); receipts != 0 {
+		t.Errorf("retrieved %d receipts without percentiles", receipts)
+	}
+	_, _, _, _, l1History, err = oracle.FeeHistory(context.Background(), 10, rpc.LatestBlockNumber, []float64{50})
+	if err != nil {
+		t.Fatalf("failed to retrieve fee history: %v", err)
+	}
+	check(l1History)
+	if receipts := atomic.LoadInt32(&backend.receipts); receipts == 0 {
+		t.Error("retrieved no receipts with percentiles")
+	}
+}
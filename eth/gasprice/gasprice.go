@@ -47,6 +47,7 @@ type Config struct {
 	Default          *big.Int `toml:",omitempty"`
 	MaxPrice         *big.Int `toml:",omitempty"`
 	IgnorePrice      *big.Int `toml:",omitempty"`
+	L1Aware          bool     `toml:",omitempty"` // Suggest L1 base fees from recent history on rollup chains
 }
 
 // OracleBackend includes all necessary background APIs for oracle.
@@ -70,6 +71,10 @@ type Oracle struct {
 	cacheLock   sync.RWMutex
 	fetchLock   sync.Mutex
 
+	l1Aware       bool
+	lastL1Head    common.Hash
+	lastL1BaseFee *big.Int
+
 	checkBlocks, percentile           int
 	maxHeaderHistory, maxBlockHistory uint64
 
@@ -138,6 +143,7 @@ func NewOracle(backend OracleBackend, params Config) *Oracle {
 		maxHeaderHistory: maxHeaderHistory,
 		maxBlockHistory:  maxBlockHistory,
 		historyCache:     cache,
+		l1Aware:          params.L1Aware,
 	}
 }
 
@@ -222,6 +228,68 @@ func (oracle *Oracle) SuggestTipCap(ctx context.Context) (*big.Int, error) {
 	return new(big.Int).Set(price), nil
 }
 
+// SuggestL1BaseFee returns an L1 base fee that the L1 data fee of newly created
+// transactions should be priced with, so that they remain affordable if the L1
+// base fee tracked by the rollup rises before inclusion. It is the configured
+// percentile of the L1 base fees of recent blocks, but never lower than the L1
+// base fee of the latest block.
+//
+// If the oracle is not L1-aware or the chain is not a rollup, nil is returned.
+func (oracle *Oracle) SuggestL1BaseFee(ctx context.Context) (*big.Int, error) {
+	if !oracle.l1Aware || !oracle.backend.ChainConfig().IsPatex() {
+		return nil, nil
+	}
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	headHash := head.Hash()
+
+	oracle.cacheLock.RLock()
+	lastHead, lastL1BaseFee := oracle.lastL1Head, oracle.lastL1BaseFee
+	oracle.cacheLock.RUnlock()
+	if headHash == lastHead && lastL1BaseFee != nil {
+		return new(big.Int).Set(lastL1BaseFee), nil
+	}
+	var (
+		number = head.Number.Uint64()
+		fees   []*big.Int
+		latest *big.Int
+	)
+	for i := 0; i < oracle.checkBlocks; i++ {
+		block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		l1BaseFee, _, _ := l1FeeParams(block)
+		if latest == nil {
+			latest = l1BaseFee
+		}
+		fees = append(fees, l1BaseFee)
+		if number == 0 {
+			break
+		}
+		number--
+	}
+	if len(fees) == 0 {
+		return nil, nil
+	}
+	sort.Sort(bigIntArray(fees))
+	l1BaseFee := fees[(len(fees)-1)*oracle.percentile/100]
+	if l1BaseFee.Cmp(latest) < 0 {
+		l1BaseFee = latest
+	}
+	oracle.cacheLock.Lock()
+	oracle.lastL1Head = headHash
+	oracle.lastL1BaseFee = l1BaseFee
+	oracle.cacheLock.Unlock()
+
+	return new(big.Int).Set(l1BaseFee), nil
+}
+
 type results struct {
 	values []*big.Int
 	err    error
@@ -492,9 +492,10 @@ func answerGetPooledTransactions(backend Backend, query GetPooledTransactionsPac
 		if bytes >= softResponseLimit {
 			break
 		}
-		// Retrieve the requested transaction, skipping if unknown to us
+		// Retrieve the requested transaction, skipping if unknown to us or if
+		// it carries local inclusion requirements peers would not honour
 		tx := backend.TxPool().Get(hash)
-		if tx == nil {
+		if tx == nil || tx.Conditional() != nil {
 			continue
 		}
 		// If known, encode and queue for response packet
@@ -0,0 +1,491 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package sequencer implements the forwarding of transactions from replica nodes
+// to the rollup sequencer and the mirroring of the sequencer mempool into them.
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	defaultRetries             = 3
+	defaultRetryBackoff        = 100 * time.Millisecond
+	defaultFailureThreshold    = 3
+	defaultHealthCheckInterval = 5 * time.Second
+
+	maxRetryBackoff   = 2 * time.Second // Upper bound of the exponential retry backoff
+	dialTimeout       = 5 * time.Second // Timeout of dialing a sequencer endpoint
+	probeTimeout      = 2 * time.Second // Timeout of a single health probe
+	resendTxTimeout   = 5 * time.Second // Timeout of re-forwarding a single retained transaction
+	sendRawTxMethod   = "eth_sendRawTransaction"
+	sendCondTxMethod  = "eth_sendRawTransactionConditional"
+	healthProbeMethod = "eth_chainId"
+)
+
+var (
+	// ErrNoEndpoints is returned if a forwarder is created without endpoints.
+	ErrNoEndpoints = errors.New("no sequencer endpoints configured")
+
+	// ErrUnavailable is returned if no sequencer endpoint could be reached.
+	ErrUnavailable = errors.New("no sequencer endpoint available")
+
+	errNotConnected = errors.New("sequencer endpoint not connected")
+
+	resendMeter     = metrics.NewRegisteredMeter("rollup/sequencer/resend", nil)
+	resendFailMeter = metrics.NewRegisteredMeter("rollup/sequencer/resend/fail", nil)
+)
+
+// Config contains the settings of a Forwarder.
+type Config struct {
+	Endpoints           []string      // Sequencer RPC endpoints, in order of preference
+	Retries             int           // Number of rounds over the endpoints before giving up on a transaction
+	RetryBackoff        time.Duration // Delay before the second round, doubled for every further round
+	FailureThreshold    int           // Consecutive failures after which an endpoint is taken out of rotation
+	HealthCheckInterval time.Duration // Interval of probing the endpoints
+
+	// Resend returns the locally retained transactions, in nonce order per
+	// account. They are re-forwarded whenever an endpoint comes back after
+	// being out of rotation. Optional.
+	Resend func() types.Transactions
+}
+
+// sanitize returns a copy of the config with defaults filled in.
+func (c Config) sanitize() Config {
+	if c.Retries <= 0 {
+		c.Retries = defaultRetries
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = defaultRetryBackoff
+	}
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = defaultFailureThreshold
+	}
+	if c.HealthCheckInterval <= 0 {
+		c.HealthCheckInterval = defaultHealthCheckInterval
+	}
+	return c
+}
+
+// SplitEndpoints splits a comma separated list of endpoints, dropping empty
+// entries.
+func SplitEndpoints(input string) []string {
+	var endpoints []string
+	for _, endpoint := range strings.Split(input, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// Forwarder sends transactions to the first healthy of a list of sequencer
+// endpoints. Transport failures are retried on the next endpoint and, after all
+// endpoints were tried, in further rounds with exponential backoff. Errors
+// returned by a sequencer, such as a nonce being too low, are final.
+//
+// Every endpoint has a circuit breaker: after a number of consecutive transport
+// failures it is taken out of rotation until a periodic health probe succeeds.
+// Once an endpoint is back, the locally retained transactions are queued to be
+// forwarded again, as a restarted sequencer will have lost its mempool.
+type Forwarder struct {
+	config    Config
+	endpoints []*endpoint
+
+	resendCh chan struct{} // Notification channel of the resend queue, buffered to coalesce recoveries
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewForwarder creates a forwarder for the configured endpoints. Endpoints that
+// can't be dialed or don't answer a health probe are not fatal, they start out
+// of rotation and are retried by the health probes.
+func NewForwarder(config Config) (*Forwarder, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	f := &Forwarder{
+		config:   config.sanitize(),
+		resendCh: make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
+	}
+	for i, url := range config.Endpoints {
+		e := newEndpoint(i, url)
+		if err := e.connect(); err != nil {
+			log.Warn("Failed to connect to sequencer endpoint", "index", i, "err", err)
+		}
+		f.endpoints = append(f.endpoints, e)
+	}
+	f.wg.Add(2)
+	go f.healthLoop()
+	go f.resendLoop()
+	return f, nil
+}
+
+// Close stops the background probing and resending and closes all connections.
+func (f *Forwarder) Close() {
+	close(f.closeCh)
+	f.wg.Wait()
+	for _, e := range f.endpoints {
+		e.close()
+	}
+}
+
+// Send forwards a transaction to the sequencer, along with its conditional if
+// it has one.
+func (f *Forwarder) Send(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if cond := tx.Conditional(); cond != nil {
+		return f.send(ctx, sendCondTxMethod, hexutil.Encode(data), cond)
+	}
+	return f.send(ctx, sendRawTxMethod, hexutil.Encode(data))
+}
+
+// send forwards a transaction with the given method, retrying transport failures
+// as long as the round limit and the context allow. If no endpoint is in
+// rotation, it fails right away instead of waiting for the next round.
+func (f *Forwarder) send(ctx context.Context, method string, args ...interface{}) error {
+	var (
+		lastErr = ErrUnavailable
+		backoff = f.config.RetryBackoff
+	)
+	for round := 0; round < f.config.Retries; round++ {
+		if round > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("failed to forward transaction to sequencer: %w", lastErr)
+			case <-f.closeCh:
+				timer.Stop()
+				return fmt.Errorf("failed to forward transaction to sequencer: %w", lastErr)
+			}
+			if backoff *= 2; backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
+		}
+		tried := false
+		for _, e := range f.endpoints {
+			if !e.isHealthy() {
+				continue
+			}
+			tried = true
+			err := e.sendTransaction(ctx, f.config.FailureThreshold, method, args...)
+			if err == nil || isAlreadyKnown(err) {
+				// A retry of a request that failed ambiguously may find the
+				// transaction already in the sequencer mempool.
+				return nil
+			}
+			if isRejection(err) {
+				return err
+			}
+			lastErr = err
+			if ctx.Err() != nil {
+				return fmt.Errorf("failed to forward transaction to sequencer: %w", lastErr)
+			}
+		}
+		if !tried {
+			break
+		}
+	}
+	return fmt.Errorf("failed to forward transaction to sequencer: %w", lastErr)
+}
+
+// healthLoop periodically probes all endpoints, taking failing ones out of
+// rotation and putting recovered ones back.
+func (f *Forwarder) healthLoop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, e := range f.endpoints {
+				if e.probe(f.config.FailureThreshold) {
+					f.scheduleResend()
+				}
+			}
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// scheduleResend queues a resend of the retained transactions, unless one is
+// already queued.
+func (f *Forwarder) scheduleResend() {
+	if f.config.Resend == nil {
+		return
+	}
+	select {
+	case f.resendCh <- struct{}{}:
+	default:
+	}
+}
+
+// resendLoop re-forwards the retained transactions whenever a resend is queued.
+func (f *Forwarder) resendLoop() {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case <-f.resendCh:
+			f.resend()
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// resend forwards all retained transactions once. It stops early if the
+// sequencer becomes unreachable again, the next recovery queues a new resend.
+func (f *Forwarder) resend() {
+	txs := f.config.Resend()
+	if len(txs) == 0 {
+		return
+	}
+	log.Info("Re-forwarding retained transactions to sequencer", "count", len(txs))
+	for _, tx := range txs {
+		select {
+		case <-f.closeCh:
+			return
+		default:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), resendTxTimeout)
+		err := f.Send(ctx, tx)
+		cancel()
+
+		switch {
+		case err == nil:
+			resendMeter.Mark(1)
+		case isRejection(err):
+			// The sequencer may have already included or replaced the transaction.
+			resendFailMeter.Mark(1)
+			log.Debug("Sequencer rejected retained transaction", "hash", tx.Hash(), "err", err)
+		default:
+			resendFailMeter.Mark(1)
+			log.Warn("Failed to re-forward retained transactions", "err", err)
+			return
+		}
+	}
+}
+
+// isRejection reports whether the error was returned by the sequencer itself,
+// as opposed to a failure of reaching it.
+func isRejection(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr)
+}
+
+// isAlreadyKnown reports whether the sequencer rejected a transaction because it
+// already has it.
+func isAlreadyKnown(err error) bool {
+	return isRejection(err) && err.Error() == txpool.ErrAlreadyKnown.Error()
+}
+
+// endpoint is a single sequencer endpoint with its circuit breaker state.
+type endpoint struct {
+	index int
+	url   string
+
+	client   *rpc.Client
+	failures int  // Consecutive transport failures
+	healthy  bool // Whether the endpoint is in rotation
+	lock     sync.Mutex
+
+	requestMeter metrics.Meter
+	failureMeter metrics.Meter
+	latencyTimer metrics.Timer
+	healthGauge  metrics.Gauge
+}
+
+func newEndpoint(index int, url string) *endpoint {
+	// Metrics are keyed by the index, as endpoint URLs may contain credentials.
+	prefix := fmt.Sprintf("rollup/sequencer/%d/", index)
+	return &endpoint{
+		index:        index,
+		url:          url,
+		requestMeter: metrics.GetOrRegisterMeter(prefix+"requests", nil),
+		failureMeter: metrics.GetOrRegisterMeter(prefix+"failures", nil),
+		latencyTimer: metrics.GetOrRegisterTimer(prefix+"latency", nil),
+		healthGauge:  metrics.GetOrRegisterGauge(prefix+"healthy", nil),
+	}
+}
+
+// dial connects to the endpoint. The endpoint is not put into rotation until a
+// health probe succeeds.
+func (e *endpoint) dial() (*rpc.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.client = client
+	return client, nil
+}
+
+// connect dials the endpoint if needed and puts it into rotation if it answers
+// a health probe.
+func (e *endpoint) connect() error {
+	client := e.connection()
+	if client == nil {
+		var err error
+		if client, err = e.dial(); err != nil {
+			return err
+		}
+	}
+	if err := e.ping(client); err != nil {
+		return err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.failures = 0
+	e.setHealthy(true)
+	return nil
+}
+
+func (e *endpoint) close() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.client != nil {
+		e.client.Close()
+		e.client = nil
+	}
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.healthy
+}
+
+func (e *endpoint) connection() *rpc.Client {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.client
+}
+
+// setHealthy updates the rotation state. The caller must hold the lock.
+func (e *endpoint) setHealthy(healthy bool) {
+	e.healthy = healthy
+	if healthy {
+		e.healthGauge.Update(1)
+	} else {
+		e.healthGauge.Update(0)
+	}
+}
+
+// sendTransaction forwards a transaction to the endpoint with the given method
+// and updates the circuit breaker with the outcome.
+func (e *endpoint) sendTransaction(ctx context.Context, threshold int, method string, args ...interface{}) error {
+	client := e.connection()
+	if client == nil {
+		return errNotConnected
+	}
+	e.requestMeter.Mark(1)
+	start := time.Now()
+	err := client.CallContext(ctx, nil, method, args...)
+	e.latencyTimer.UpdateSince(start)
+
+	switch {
+	case err == nil || isRejection(err):
+		e.recordSuccess()
+	case ctx.Err() != nil:
+		// The caller gave up, which says nothing about the endpoint.
+	default:
+		e.failureMeter.Mark(1)
+		e.recordFailure(err, threshold)
+	}
+	return err
+}
+
+// probe checks the endpoint, redialing it if needed. It returns whether the
+// endpoint came back into rotation.
+func (e *endpoint) probe(threshold int) bool {
+	if e.isHealthy() {
+		client := e.connection()
+		if client == nil {
+			return false
+		}
+		if err := e.ping(client); err != nil {
+			e.recordFailure(err, threshold)
+		} else {
+			e.recordSuccess()
+		}
+		return false
+	}
+	if err := e.connect(); err != nil {
+		log.Debug("Sequencer endpoint still unavailable", "index", e.index, "err", err)
+		return false
+	}
+	log.Info("Sequencer endpoint back in rotation", "index", e.index)
+	return true
+}
+
+// ping performs a health probe call.
+func (e *endpoint) ping(client *rpc.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	var chainID hexutil.Big
+	return client.CallContext(ctx, &chainID, healthProbeMethod)
+}
+
+func (e *endpoint) recordSuccess() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.failures = 0
+}
+
+func (e *endpoint) recordFailure(err error, threshold int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.failures++
+	if e.healthy && e.failures >= threshold {
+		e.setHealthy(false)
+		log.Warn("Sequencer endpoint taken out of rotation", "index", e.index, "failures", e.failures, "err", err)
+	}
+}
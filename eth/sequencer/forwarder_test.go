@@ -0,0 +1,282 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testSequencer is a mock sequencer mempool that can be taken down.
+type testSequencer struct {
+	down   atomic.Bool
+	reject error
+
+	lock  sync.Mutex
+	txs   []common.Hash
+	conds []*types.TransactionConditional
+}
+
+func (s *testSequencer) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (s *testSequencer) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if s.reject != nil {
+		return common.Hash{}, s.reject
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.txs = append(s.txs, tx.Hash())
+	return tx.Hash(), nil
+}
+
+func (s *testSequencer) SendRawTransactionConditional(input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	hash, err := s.SendRawTransaction(input)
+	if err != nil {
+		return hash, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.conds = append(s.conds, &cond)
+	return hash, nil
+}
+
+func (s *testSequencer) received() []common.Hash {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]common.Hash{}, s.txs...)
+}
+
+func newTestSequencer(t *testing.T) (*testSequencer, string) {
+	seq := new(testSequencer)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", seq); err != nil {
+		t.Fatalf("failed to register sequencer: %v", err)
+	}
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if seq.down.Load() {
+			http.Error(w, "sequencer down", http.StatusServiceUnavailable)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return seq, httpServer.URL
+}
+
+func newTestTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{}})
+}
+
+func TestSplitEndpoints(t *testing.T) {
+	have := SplitEndpoints(" http://a , ,http://b,")
+	if len(have) != 2 || have[0] != "http://a" || have[1] != "http://b" {
+		t.Fatalf("unexpected endpoints: %v", have)
+	}
+	if _, err := NewForwarder(Config{Endpoints: SplitEndpoints(" , ")}); err != ErrNoEndpoints {
+		t.Fatalf("expected %v, got %v", ErrNoEndpoints, err)
+	}
+}
+
+// Tests that transactions fail over to the next endpoint and that an endpoint is
+// taken out of rotation after consecutive failures.
+func TestForwarderFailover(t *testing.T) {
+	primary, primaryURL := newTestSequencer(t)
+	backup, backupURL := newTestSequencer(t)
+
+	f, err := NewForwarder(Config{
+		Endpoints:           []string{primaryURL, backupURL},
+		FailureThreshold:    2,
+		HealthCheckInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create forwarder: %v", err)
+	}
+	defer f.Close()
+
+	primary.down.Store(true)
+	for i := uint64(0); i < 3; i++ {
+		if err := f.Send(context.Background(), newTestTx(i)); err != nil {
+			t.Fatalf("tx %d: failed to forward: %v", i, err)
+		}
+	}
+	if n := len(backup.received()); n != 3 {
+		t.Fatalf("backup received %d txs, want 3", n)
+	}
+	if f.endpoints[0].isHealthy() {
+		t.Fatalf("failing endpoint still in rotation")
+	}
+	if !f.endpoints[1].isHealthy() {
+		t.Fatalf("working endpoint out of rotation")
+	}
+	// With all endpoints down, forwarding fails after the retries.
+	backup.down.Store(true)
+	f.config.RetryBackoff = time.Millisecond
+	if err := f.Send(context.Background(), newTestTx(3)); err == nil {
+		t.Fatalf("forwarding succeeded without a sequencer")
+	}
+}
+
+// Tests that endpoints only enter rotation once they answer a health probe, and
+// that forwarding fails right away while no endpoint is in rotation.
+func TestForwarderUnavailable(t *testing.T) {
+	seq, url := newTestSequencer(t)
+	seq.down.Store(true)
+
+	f, err := NewForwarder(Config{
+		Endpoints:           []string{url},
+		RetryBackoff:        time.Hour,
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create forwarder: %v", err)
+	}
+	defer f.Close()
+
+	if f.endpoints[0].isHealthy() {
+		t.Fatalf("unreachable endpoint in rotation")
+	}
+	start := time.Now()
+	if err := f.Send(context.Background(), newTestTx(0)); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("unexpected error: have %v, want %v", err, ErrUnavailable)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("forwarding waited for retries without endpoints: %v", elapsed)
+	}
+	seq.down.Store(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !f.endpoints[0].isHealthy() {
+		if time.Now().After(deadline) {
+			t.Fatalf("recovered endpoint not back in rotation")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := f.Send(context.Background(), newTestTx(0)); err != nil {
+		t.Fatalf("failed to forward: %v", err)
+	}
+}
+
+// Tests that errors returned by the sequencer are final and not retried.
+func TestForwarderRejection(t *testing.T) {
+	primary, primaryURL := newTestSequencer(t)
+	backup, backupURL := newTestSequencer(t)
+	primary.reject = errors.New("nonce too low")
+
+	f, err := NewForwarder(Config{Endpoints: []string{primaryURL, backupURL}, HealthCheckInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create forwarder: %v", err)
+	}
+	defer f.Close()
+
+	if err := f.Send(context.Background(), newTestTx(0)); err == nil || err.Error() != "nonce too low" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(backup.received()); n != 0 {
+		t.Fatalf("rejected tx forwarded to backup")
+	}
+	if !f.endpoints[0].isHealthy() {
+		t.Fatalf("rejecting endpoint taken out of rotation")
+	}
+}
+
+// Tests that retained transactions are forwarded again once the sequencer comes
+// back into rotation.
+func TestForwarderResend(t *testing.T) {
+	seq, url := newTestSequencer(t)
+	retained := types.Transactions{newTestTx(0), newTestTx(1)}
+
+	f, err := NewForwarder(Config{
+		Endpoints:           []string{url},
+		Retries:             1,
+		FailureThreshold:    1,
+		HealthCheckInterval: 10 * time.Millisecond,
+		Resend:              func() types.Transactions { return retained },
+	})
+	if err != nil {
+		t.Fatalf("failed to create forwarder: %v", err)
+	}
+	defer f.Close()
+
+	seq.down.Store(true)
+	if err := f.Send(context.Background(), retained[0]); err == nil {
+		t.Fatalf("forwarding succeeded without a sequencer")
+	}
+	if f.endpoints[0].isHealthy() {
+		t.Fatalf("failing endpoint still in rotation")
+	}
+	seq.down.Store(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(seq.received()) < len(retained) {
+		if time.Now().After(deadline) {
+			t.Fatalf("retained txs not resent: have %d, want %d", len(seq.received()), len(retained))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i, hash := range seq.received()[:len(retained)] {
+		if hash != retained[i].Hash() {
+			t.Fatalf("resent tx %d mismatch: have %x, want %x", i, hash, retained[i].Hash())
+		}
+	}
+}
+
+// Tests that the conditional of a transaction is forwarded along with it.
+func TestForwarderConditional(t *testing.T) {
+	seq, url := newTestSequencer(t)
+
+	f, err := NewForwarder(Config{Endpoints: []string{url}, HealthCheckInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create forwarder: %v", err)
+	}
+	defer f.Close()
+
+	max := uint64(100)
+	tx := newTestTx(0)
+	tx.SetConditional(&types.TransactionConditional{TimestampMax: &max})
+	if err := f.Send(context.Background(), tx); err != nil {
+		t.Fatalf("failed to forward: %v", err)
+	}
+	seq.lock.Lock()
+	defer seq.lock.Unlock()
+	if len(seq.conds) != 1 || seq.conds[0].TimestampMax == nil || *seq.conds[0].TimestampMax != max {
+		t.Fatalf("conditional not forwarded: %+v", seq.conds)
+	}
+}
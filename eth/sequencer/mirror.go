@@ -0,0 +1,208 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	mirrorBatchSize      = 256              // Maximum number of transactions added to the pool at once
+	mirrorSyncTimeout    = 30 * time.Second // Timeout of fetching the sequencer mempool content
+	minMirrorRedialDelay = time.Second      // Delay before the first redial of a lost sequencer connection
+	maxMirrorRedialDelay = 30 * time.Second // Upper bound of the exponential redial backoff
+
+	pendingTxsSubscription = "newPendingTransactions"
+	mempoolContentMethod   = "txpool_content"
+)
+
+// mirrorResyncInterval is the interval of refetching the full content of the
+// sequencer mempool, dropping the transactions the sequencer dropped meanwhile.
+var mirrorResyncInterval = time.Minute
+
+var mirrorTxMeter = metrics.NewRegisteredMeter("rollup/sequencer/mirror/txs", nil)
+
+// MirrorPool is the transaction pool a Mirror maintains a read-only view of the
+// sequencer mempool in.
+type MirrorPool interface {
+	// AddMirrored adds pending transactions of the sequencer to the view.
+	AddMirrored(txs []*types.Transaction)
+
+	// SetMirrored replaces the view with the pending transactions of the sequencer.
+	SetMirrored(txs []*types.Transaction)
+}
+
+// Mirror maintains a read-only view of the mempool of the sequencer in the local
+// transaction pool of a replica that doesn't take part in transaction gossip,
+// so that the pool content, the pending transaction subscriptions and the
+// pending state reflect the transactions the sequencer is about to include.
+//
+// The mirrored transactions are kept apart from the own ones of the pool: they
+// are not subject to its limits and admission policies, and they are neither
+// gossiped nor forwarded back to the sequencer. They leave the view once
+// included, or once the sequencer dropped them.
+//
+// The sequencer is followed through its pending transaction subscription, which
+// requires a websocket or IPC endpoint. On every (re)connect, and periodically
+// after that, the view is replaced by the full content of the sequencer mempool
+// to fill the gap since the last notification and to drop the transactions the
+// sequencer evicted.
+type Mirror struct {
+	url  string
+	pool MirrorPool
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewMirror creates a mirror of the sequencer mempool at the given endpoint and
+// starts following it. Connection failures are not fatal, the endpoint is
+// redialed with exponential backoff until the mirror is closed.
+func NewMirror(url string, pool MirrorPool) *Mirror {
+	m := &Mirror{
+		url:     url,
+		pool:    pool,
+		closeCh: make(chan struct{}),
+	}
+	m.wg.Add(1)
+	go m.loop()
+	return m
+}
+
+// Close stops following the sequencer and closes the connection.
+func (m *Mirror) Close() {
+	close(m.closeCh)
+	m.wg.Wait()
+}
+
+// loop follows the sequencer mempool, reconnecting whenever the connection is lost.
+func (m *Mirror) loop() {
+	defer m.wg.Done()
+
+	delay := minMirrorRedialDelay
+	for {
+		connected, err := m.follow()
+		if err == nil {
+			return
+		}
+		if connected {
+			delay = minMirrorRedialDelay
+		}
+		log.Warn("Lost sequencer mempool mirror connection", "retry", delay, "err", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-m.closeCh:
+			timer.Stop()
+			return
+		}
+		if delay *= 2; delay > maxMirrorRedialDelay {
+			delay = maxMirrorRedialDelay
+		}
+	}
+}
+
+// follow connects to the sequencer, copies its mempool content and then adds
+// every announced transaction, resyncing the content periodically, until the
+// connection fails or the mirror is closed, returning nil in the latter case.
+// The connected flag reports whether the subscription was established.
+func (m *Mirror) follow() (connected bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, m.url)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	// Subscribe before fetching the content, no transaction may fall in between.
+	txs := make(chan *types.Transaction, mirrorBatchSize)
+	sub, err := client.EthSubscribe(ctx, txs, pendingTxsSubscription, true)
+	if err != nil {
+		return false, err
+	}
+	defer sub.Unsubscribe()
+
+	if err := m.sync(client); err != nil {
+		return true, err
+	}
+	log.Info("Mirroring sequencer mempool")
+
+	resync := time.NewTicker(mirrorResyncInterval)
+	defer resync.Stop()
+
+	batch := make([]*types.Transaction, 0, mirrorBatchSize)
+	for {
+		select {
+		case tx := <-txs:
+			batch = append(batch[:0], tx)
+		drain:
+			for len(batch) < mirrorBatchSize {
+				select {
+				case tx := <-txs:
+					batch = append(batch, tx)
+				default:
+					break drain
+				}
+			}
+			mirrorTxMeter.Mark(int64(len(batch)))
+			m.pool.AddMirrored(batch)
+		case <-resync.C:
+			if err := m.sync(client); err != nil {
+				return true, err
+			}
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return true, err
+		case <-m.closeCh:
+			return true, nil
+		}
+	}
+}
+
+// sync replaces the view with the pending transactions of the sequencer mempool.
+// Queued transactions are announced once the sequencer promotes them.
+func (m *Mirror) sync(client *rpc.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorSyncTimeout)
+	defer cancel()
+
+	var content map[string]map[string]map[string]*types.Transaction
+	if err := client.CallContext(ctx, &content, mempoolContentMethod); err != nil {
+		return err
+	}
+	var txs []*types.Transaction
+	for _, nonces := range content["pending"] {
+		for _, tx := range nonces {
+			txs = append(txs, tx)
+		}
+	}
+	mirrorTxMeter.Mark(int64(len(txs)))
+	m.pool.SetMirrored(txs)
+	return nil
+}
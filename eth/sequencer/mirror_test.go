@@ -0,0 +1,156 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package sequencer
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testMempool is a mock sequencer mempool announcing its transactions.
+type testMempool struct {
+	lock    sync.Mutex
+	content []*types.Transaction
+	feed    event.Feed
+}
+
+func (m *testMempool) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	rpcSub := notifier.CreateSubscription()
+
+	txs := make(chan *types.Transaction)
+	sub := m.feed.Subscribe(txs)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case tx := <-txs:
+				notifier.Notify(rpcSub.ID, tx)
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+func (m *testMempool) Content() map[string]map[string]map[string]*types.Transaction {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	pending := make(map[string]map[string]*types.Transaction)
+	for i, tx := range m.content {
+		pending[fmt.Sprintf("%#x", i)] = map[string]*types.Transaction{fmt.Sprint(tx.Nonce()): tx}
+	}
+	return map[string]map[string]map[string]*types.Transaction{"pending": pending, "queued": {}}
+}
+
+// testMirrorPool records the transactions mirrored into it.
+type testMirrorPool struct {
+	lock sync.Mutex
+	txs  map[common.Hash]bool
+}
+
+func (p *testMirrorPool) AddMirrored(txs []*types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, tx := range txs {
+		p.txs[tx.Hash()] = true
+	}
+}
+
+func (p *testMirrorPool) SetMirrored(txs []*types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.txs = make(map[common.Hash]bool)
+	for _, tx := range txs {
+		p.txs[tx.Hash()] = true
+	}
+}
+
+func (p *testMirrorPool) has(hash common.Hash) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.txs[hash]
+}
+
+// waitFor waits until the pool has the given transaction mirrored or not.
+func (p *testMirrorPool) waitFor(t *testing.T, tx *types.Transaction, mirrored bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); p.has(tx.Hash()) != mirrored; {
+		if time.Now().After(deadline) {
+			t.Fatalf("transaction %x mirrored state mismatch: want %t", tx.Hash(), mirrored)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMirror(t *testing.T) {
+	defer func(interval time.Duration) { mirrorResyncInterval = interval }(mirrorResyncInterval)
+	mirrorResyncInterval = 100 * time.Millisecond
+
+	mempool := &testMempool{content: []*types.Transaction{newTestTx(0), newTestTx(1)}}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", mempool); err != nil {
+		t.Fatalf("failed to register mempool: %v", err)
+	}
+	if err := server.RegisterName("txpool", mempool); err != nil {
+		t.Fatalf("failed to register mempool: %v", err)
+	}
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer func() {
+		httpServer.Close()
+		server.Stop()
+	}()
+	pool := &testMirrorPool{txs: make(map[common.Hash]bool)}
+	mirror := NewMirror("ws://"+strings.TrimPrefix(httpServer.URL, "http://"), pool)
+	defer mirror.Close()
+
+	// The mempool content is copied on connect.
+	for _, tx := range mempool.content {
+		pool.waitFor(t, tx, true)
+	}
+	// Announced transactions are copied as they arrive.
+	tx := newTestTx(2)
+	if n := mempool.feed.Send(tx); n != 1 {
+		t.Fatalf("subscriber count mismatch: have %d, want %d", n, 1)
+	}
+	pool.waitFor(t, tx, true)
+
+	// Transactions dropped by the sequencer are dropped on the next resync.
+	mempool.lock.Lock()
+	dropped := mempool.content[0]
+	mempool.content = append(mempool.content[1:], tx)
+	mempool.lock.Unlock()
+
+	pool.waitFor(t, dropped, false)
+	pool.waitFor(t, tx, true)
+}
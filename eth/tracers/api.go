@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -87,7 +88,7 @@ type Backend interface {
 	ChainDb() ethdb.Database
 	StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, StateReleaseFunc, error)
 	StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (*core.Message, vm.BlockContext, *state.StateDB, StateReleaseFunc, error)
-	HistoricalRPCService() *rpc.Client
+	HistoricalRouter() *historical.Router
 }
 
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
@@ -462,16 +463,11 @@ func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber,
 	}
 
 	if api.backend.ChainConfig().IsPatexPreBedrock(block.Number()) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult []*txTraceResult
-			err = api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceBlockByNumber", number, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var histResult []*txTraceResult
+		if err := api.backend.HistoricalRouter().Call(ctx, &histResult, block.NumberU64(), "debug_traceBlockByNumber", number, config); err != nil {
+			return nil, err
 		}
+		return histResult, nil
 	}
 
 	return api.traceBlock(ctx, block, config)
@@ -486,16 +482,11 @@ func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *
 	}
 
 	if api.backend.ChainConfig().IsPatexPreBedrock(block.Number()) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult []*txTraceResult
-			err = api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceBlockByHash", hash, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var histResult []*txTraceResult
+		if err := api.backend.HistoricalRouter().Call(ctx, &histResult, block.NumberU64(), "debug_traceBlockByHash", hash, config); err != nil {
+			return nil, err
 		}
+		return histResult, nil
 	}
 
 	return api.traceBlock(ctx, block, config)
@@ -881,22 +872,26 @@ func containsTx(block *types.Block, hash common.Hash) bool {
 // and returns them as a JSON object.
 func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
 	// GetTransaction returns 0 for the blocknumber if the transaction is not found
-	_, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
+	tx, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
-
 	if api.backend.ChainConfig().IsPatexPreBedrock(new(big.Int).SetUint64(blockNumber)) {
-		if api.backend.HistoricalRPCService() != nil {
-			var histResult json.RawMessage
-			err := api.backend.HistoricalRPCService().CallContext(ctx, &histResult, "debug_traceTransaction", hash, config)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return histResult, nil
+		var (
+			histResult json.RawMessage
+			router     = api.backend.HistoricalRouter()
+		)
+		if tx != nil {
+			err = router.Call(ctx, &histResult, blockNumber, "debug_traceTransaction", hash, config)
 		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+			// Transactions unknown locally may be legacy transactions only known
+			// to the historical backends.
+			_, err = router.CallByHash(ctx, &histResult, "debug_traceTransaction", hash, config)
+		}
+		if err != nil {
+			return nil, err
 		}
+		return histResult, nil
 	}
 
 	// It shouldn't happen in practice.
@@ -46,6 +46,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -127,7 +128,7 @@ type testBackend struct {
 	refHook func() // Hook is invoked when the requested state is referenced
 	relHook func() // Hook is invoked when the requested state is released
 
-	historical     *rpc.Client
+	historical     *historical.Router
 	mockHistorical *mockHistoricalBackend
 }
 
@@ -137,16 +138,16 @@ func newTestBackend(t *testing.T, n int, gspec *core.Genesis, generator func(i i
 	mock := new(mockHistoricalBackend)
 	historicalAddr := newMockHistoricalBackend(t, mock)
 
-	historicalClient, err := rpc.Dial(historicalAddr)
+	historicalRouter, err := historical.NewRouter([]historical.Endpoint{{URL: historicalAddr}}, time.Second, 0)
 	if err != nil {
-		t.Fatalf("error making historical client: %v", err)
+		t.Fatalf("error making historical router: %v", err)
 	}
 
 	backend := &testBackend{
 		chainConfig:    gspec.Config,
 		engine:         ethash.NewFaker(),
 		chaindb:        rawdb.NewMemoryDatabase(),
-		historical:     historicalClient,
+		historical:     historicalRouter,
 		mockHistorical: mock,
 	}
 	// Generate blocks for testing
@@ -268,7 +269,7 @@ func (b *testBackend) StateAtTransaction(ctx context.Context, block *types.Block
 	return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
 
-func (b *testBackend) HistoricalRPCService() *rpc.Client {
+func (b *testBackend) HistoricalRouter() *historical.Router {
 	return b.historical
 }
 
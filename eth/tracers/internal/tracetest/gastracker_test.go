@@ -0,0 +1,327 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+type gasTrackerContract struct {
+	Address      common.Address `json:"address"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	ScaledGas    *hexutil.Big   `json:"scaledGas"`
+	Accumulating bool           `json:"accumulating"`
+	Fee          *hexutil.Big   `json:"fee"`
+}
+
+type gasTrackerFeeShare struct {
+	Address common.Address `json:"address"`
+	Fee     *hexutil.Big   `json:"fee"`
+}
+
+type gasTrackerResult struct {
+	AccountingCorrect bool                 `json:"accountingCorrect"`
+	EVMGasUsed        hexutil.Uint64       `json:"evmGasUsed"`
+	TrackerGasUsed    hexutil.Uint64       `json:"trackerGasUsed"`
+	GasPrice          *hexutil.Big         `json:"gasPrice"`
+	Allocations       []gasTrackerContract `json:"allocations"`
+	UnallocatedGas    *hexutil.Big         `json:"unallocatedGas"`
+	UnallocatedFee    *hexutil.Big         `json:"unallocatedFee"`
+	ClaimableFee      *hexutil.Big         `json:"claimableFee"`
+	CoinbaseFee       *hexutil.Big         `json:"coinbaseFee"`
+	FeeSplit          []gasTrackerFeeShare `json:"feeSplit"`
+}
+
+// gasBurner wraps a tracer and burns gas behind the back of the gas tracker at
+// the first step of the execution, making the gas accounting diverge.
+type gasBurner struct {
+	tracers.Tracer
+	burnt bool
+}
+
+func (b *gasBurner) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if !b.burnt {
+		scope.Contract.Gas -= 1000
+		b.burnt = true
+	}
+	b.Tracer.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+}
+
+func (b *gasBurner) CaptureGasAllocation(allocation *vm.GasAllocation) {
+	b.Tracer.(vm.GasAllocationLogger).CaptureGasAllocation(allocation)
+}
+
+// Tests that the gas tracker tracer reports the fees credited to a contract
+// accumulating gas fees and the remainder going to the base fee recipient.
+func TestGasTrackerTracer(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0x000000000000000000000000000000000000c0de")
+		baseFee  = big.NewInt(params.GWei)
+		tip      = big.NewInt(2 * params.GWei)
+		config   = *params.TestChainConfig
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	// Opt the contract into gas fee accumulation by setting the mode byte of
+	// its packed gas parameters.
+	slot := crypto.Keccak256Hash(append(contract.Bytes(), []byte("parameters")...))
+	mode := common.Hash{}
+	mode[0] = 1
+
+	alloc := core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(params.Ether)},
+		contract: {Code: common.FromHex("0x600160005500")}, // sstore(0, 1)
+		params.PatexGasAddress: {
+			Balance: new(big.Int),
+			Storage: map[common.Hash]common.Hash{slot: mode},
+		},
+	}
+	signer := types.LatestSigner(&config)
+	tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   config.ChainID,
+		Nonce:     0,
+		To:        &contract,
+		Gas:       100_000,
+		GasTipCap: tip,
+		GasFeeCap: new(big.Int).Add(baseFee, tip),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	var (
+		txContext = vm.TxContext{Origin: sender, GasPrice: new(big.Int).Add(baseFee, tip)}
+		context   = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: big.NewInt(1),
+			Time:        1,
+			Difficulty:  new(big.Int),
+			GasLimit:    30_000_000,
+			BaseFee:     baseFee,
+			L1CostFunc: func(uint64, uint64, types.RollupGasData, bool) *big.Int {
+				return nil
+			},
+		}
+		_, statedb = tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+	)
+	tracer, err := tracers.DefaultDirectory.New("gasTrackerTracer", new(tracers.Context), nil)
+	if err != nil {
+		t.Fatalf("failed to create gas tracker tracer: %v", err)
+	}
+	evm := vm.NewEVM(context, txContext, statedb, &config, vm.Config{Tracer: tracer})
+	msg, err := core.TransactionToMessage(tx, signer, baseFee)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+	if err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	blob, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var result gasTrackerResult
+	if err := json.Unmarshal(blob, &result); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if !result.AccountingCorrect {
+		t.Fatalf("gas accounting reported as incorrect: %s", blob)
+	}
+	if uint64(result.EVMGasUsed) != res.UsedGas || result.TrackerGasUsed != result.EVMGasUsed {
+		t.Errorf("gas used mismatch: have evm %d tracker %d, want %d", result.EVMGasUsed, result.TrackerGasUsed, res.UsedGas)
+	}
+	price := new(big.Int).Add(baseFee, tip)
+	if result.GasPrice.ToInt().Cmp(price) != 0 {
+		t.Errorf("gas price mismatch: have %v, want %v", result.GasPrice, price)
+	}
+	var (
+		found    bool
+		credited = new(big.Int)
+	)
+	for _, a := range result.Allocations {
+		credited.Add(credited, a.Fee.ToInt())
+		if a.Address != contract {
+			if a.Accumulating || a.Fee.ToInt().Sign() != 0 {
+				t.Errorf("fee credited to non-accumulating %x: %v", a.Address, a.Fee)
+			}
+			continue
+		}
+		found = true
+		if !a.Accumulating {
+			t.Errorf("contract not reported as accumulating")
+		}
+		if want := new(big.Int).Mul(a.ScaledGas.ToInt(), price); a.Fee.ToInt().Cmp(want) != 0 {
+			t.Errorf("contract fee mismatch: have %v, want %v", a.Fee, want)
+		}
+	}
+	if !found {
+		t.Fatalf("contract missing from allocations: %s", blob)
+	}
+	if credited.Cmp(result.ClaimableFee.ToInt()) != 0 {
+		t.Errorf("claimable fee mismatch: have %v, want %v", result.ClaimableFee, credited)
+	}
+	// Every scaled gas unit is paid either to a contract or to the base fee recipient
+	total := new(big.Int).Add(result.ClaimableFee.ToInt(), result.UnallocatedFee.ToInt())
+	if want := new(big.Int).Mul(new(big.Int).SetUint64(res.UsedGas), price); total.Cmp(want) != 0 {
+		t.Errorf("total fee mismatch: have %v, want %v", total, want)
+	}
+	if have := statedb.GetBalance(params.PatexGasAddress); have.Cmp(result.ClaimableFee.ToInt()) != 0 {
+		t.Errorf("gas predeploy balance mismatch: have %v, want %v", have, result.ClaimableFee)
+	}
+}
+
+// Tests that the gas tracker tracer reports the canary fallback, taken when the
+// gas tracker disagrees with the EVM: the base fee goes to the base fee recipient
+// and the priority fee is split between the coinbase and the fee split recipients.
+func TestGasTrackerTracerFallback(t *testing.T) {
+	var (
+		key, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender     = crypto.PubkeyToAddress(key.PublicKey)
+		contract   = common.HexToAddress("0x000000000000000000000000000000000000c0de")
+		coinbase   = common.HexToAddress("0x000000000000000000000000000000000000c014")
+		recipient1 = common.HexToAddress("0x000000000000000000000000000000000000f001")
+		recipient2 = common.HexToAddress("0x000000000000000000000000000000000000f002")
+		baseFee    = big.NewInt(params.GWei)
+		tip        = big.NewInt(2 * params.GWei)
+		config     = *params.TestChainConfig
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.Patex = &params.PatexConfig{
+		EIP1559Elasticity:  6,
+		EIP1559Denominator: 50,
+		FeeSplit: &params.FeeSplitConfig{
+			CoinbaseWeight: 1,
+			Recipients:     []params.FeeSplitRecipient{{Address: recipient1, Weight: 1}, {Address: recipient2, Weight: 2}},
+		},
+	}
+	alloc := core.GenesisAlloc{
+		sender:   {Balance: big.NewInt(params.Ether)},
+		contract: {Code: common.FromHex("0x600160005500")}, // sstore(0, 1)
+	}
+	signer := types.LatestSigner(&config)
+	tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   config.ChainID,
+		Nonce:     0,
+		To:        &contract,
+		Gas:       100_000,
+		GasTipCap: tip,
+		GasFeeCap: new(big.Int).Add(baseFee, tip),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	var (
+		txContext = vm.TxContext{Origin: sender, GasPrice: new(big.Int).Add(baseFee, tip)}
+		context   = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			Coinbase:    coinbase,
+			BlockNumber: big.NewInt(1),
+			Time:        1,
+			Difficulty:  new(big.Int),
+			GasLimit:    30_000_000,
+			BaseFee:     baseFee,
+			L1CostFunc: func(uint64, uint64, types.RollupGasData, bool) *big.Int {
+				return nil
+			},
+		}
+		_, statedb = tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+	)
+	inner, err := tracers.DefaultDirectory.New("gasTrackerTracer", new(tracers.Context), nil)
+	if err != nil {
+		t.Fatalf("failed to create gas tracker tracer: %v", err)
+	}
+	tracer := &gasBurner{Tracer: inner}
+	evm := vm.NewEVM(context, txContext, statedb, &config, vm.Config{Tracer: tracer})
+	msg, err := core.TransactionToMessage(tx, signer, baseFee)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+	if err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	blob, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var result gasTrackerResult
+	if err := json.Unmarshal(blob, &result); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if result.AccountingCorrect {
+		t.Fatalf("gas accounting reported as correct: %s", blob)
+	}
+	if uint64(result.EVMGasUsed) != res.UsedGas || uint64(result.TrackerGasUsed) != res.UsedGas-1000 {
+		t.Errorf("gas used mismatch: have evm %d tracker %d, want %d and %d", result.EVMGasUsed, result.TrackerGasUsed, res.UsedGas, res.UsedGas-1000)
+	}
+	if len(result.Allocations) != 0 || result.ClaimableFee.ToInt().Sign() != 0 {
+		t.Errorf("fees credited to contracts on fallback: %s", blob)
+	}
+	gasUsed := new(big.Int).SetUint64(res.UsedGas)
+	if want := new(big.Int).Mul(gasUsed, baseFee); result.UnallocatedFee.ToInt().Cmp(want) != 0 {
+		t.Errorf("unallocated fee mismatch: have %v, want %v", result.UnallocatedFee, want)
+	}
+	// The priority fee is split 1:1:2 between the coinbase and the recipients.
+	quarter := new(big.Int).Div(new(big.Int).Mul(gasUsed, tip), big.NewInt(4))
+	if result.CoinbaseFee.ToInt().Cmp(quarter) != 0 {
+		t.Errorf("coinbase fee mismatch: have %v, want %v", result.CoinbaseFee, quarter)
+	}
+	want := []gasTrackerFeeShare{
+		{Address: recipient1, Fee: (*hexutil.Big)(quarter)},
+		{Address: recipient2, Fee: (*hexutil.Big)(new(big.Int).Mul(quarter, big.NewInt(2)))},
+	}
+	if len(result.FeeSplit) != len(want) {
+		t.Fatalf("fee split length mismatch: have %d, want %d", len(result.FeeSplit), len(want))
+	}
+	for i, share := range result.FeeSplit {
+		if share.Address != want[i].Address || share.Fee.ToInt().Cmp(want[i].Fee.ToInt()) != 0 {
+			t.Errorf("fee share %d mismatch: have %x %v, want %x %v", i, share.Address, share.Fee, want[i].Address, want[i].Fee)
+		}
+	}
+	// The reported fees are the ones credited.
+	for _, credit := range []struct {
+		addr common.Address
+		fee  *hexutil.Big
+	}{
+		{coinbase, result.CoinbaseFee},
+		{recipient1, result.FeeSplit[0].Fee},
+		{recipient2, result.FeeSplit[1].Fee},
+		{params.PatexBaseFeeRecipient, result.UnallocatedFee},
+	} {
+		if have := statedb.GetBalance(credit.addr); have.Cmp(credit.fee.ToInt()) != 0 {
+			t.Errorf("balance of %x mismatch: have %v, want %v", credit.addr, have, credit.fee)
+		}
+	}
+}
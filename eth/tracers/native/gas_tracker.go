@@ -0,0 +1,166 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("gasTrackerTracer", newGasTrackerTracer, false)
+}
+
+// gasTrackerResult is the json representation of a vm.GasAllocation.
+type gasTrackerResult struct {
+	AccountingCorrect bool                    `json:"accountingCorrect"`
+	EVMGasUsed        hexutil.Uint64          `json:"evmGasUsed"`
+	TrackerGasUsed    hexutil.Uint64          `json:"trackerGasUsed"`
+	Refund            hexutil.Uint64          `json:"refund"`
+	GasPrice          *hexutil.Big            `json:"gasPrice"`
+	Allocations       []gasTrackerContract    `json:"allocations"`
+	UnallocatedGas    *hexutil.Big            `json:"unallocatedGas"`
+	UnallocatedFee    *hexutil.Big            `json:"unallocatedFee"`
+	ClaimableFee      *hexutil.Big            `json:"claimableFee"`
+	CoinbaseFee       *hexutil.Big            `json:"coinbaseFee"`
+	FeeSplit          []gasTrackerFeeShare    `json:"feeSplit"`
+	Recipients        gasTrackerFeeRecipients `json:"recipients"`
+}
+
+// gasTrackerContract is the json representation of a vm.ContractGasAllocation.
+type gasTrackerContract struct {
+	Address      common.Address `json:"address"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	ScaledGas    *hexutil.Big   `json:"scaledGas"`
+	Accumulating bool           `json:"accumulating"`
+	Fee          *hexutil.Big   `json:"fee"`
+}
+
+// gasTrackerFeeShare is the json representation of a vm.FeeShare.
+type gasTrackerFeeShare struct {
+	Address common.Address `json:"address"`
+	Fee     *hexutil.Big   `json:"fee"`
+}
+
+// gasTrackerFeeRecipients names the accounts the unallocated and claimable
+// fees are sent to.
+type gasTrackerFeeRecipients struct {
+	Unallocated common.Address `json:"unallocated"`
+	Claimable   common.Address `json:"claimable"`
+}
+
+// gasTrackerTracer reports how the fees of a transaction are shared out by
+// the Patex gas tracker: the gas attributed to every contract, the gas units
+// left after scaling out the refund, the wei credited to contracts that
+// accumulate gas fees and the remainder sent to the PatexBaseFeeRecipient.
+// If gas accounting diverged from the EVM, accountingCorrect is false and
+// the canary fallback is reported instead of a per-contract allocation,
+// along with the split of the priority fee between the coinbase and the fee
+// split recipients.
+//
+// The result is null for transactions that are not subject to gas sharing,
+// such as deposits or transactions on pre-bedrock blocks.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x214e...", {tracer: "gasTrackerTracer"})
+//	{
+//	  accountingCorrect: true,
+//	  allocations: [{
+//	      accumulating: true,
+//	      address: "0x...",
+//	      fee: "0x...",
+//	      gasUsed: "0x5208",
+//	      scaledGas: "0x5208"
+//	  }],
+//	  ...
+//	}
+type gasTrackerTracer struct {
+	noopTracer
+	result    *gasTrackerResult
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newGasTrackerTracer returns a native go tracer which reports the gas fee
+// allocation of a transaction, and implements vm.EVMLogger.
+func newGasTrackerTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &gasTrackerTracer{}, nil
+}
+
+// CaptureGasAllocation implements the vm.GasAllocationLogger interface to
+// record the fee allocation of the transaction.
+func (t *gasTrackerTracer) CaptureGasAllocation(allocation *vm.GasAllocation) {
+	if t.interrupt.Load() {
+		return
+	}
+	result := &gasTrackerResult{
+		AccountingCorrect: allocation.AccountingCorrect,
+		EVMGasUsed:        hexutil.Uint64(allocation.EVMGasUsed),
+		TrackerGasUsed:    hexutil.Uint64(allocation.TrackerGasUsed),
+		Refund:            hexutil.Uint64(allocation.Refund),
+		GasPrice:          (*hexutil.Big)(allocation.GasPrice),
+		Allocations:       make([]gasTrackerContract, 0, len(allocation.Contracts)),
+		UnallocatedGas:    (*hexutil.Big)(allocation.UnallocatedGas),
+		UnallocatedFee:    (*hexutil.Big)(allocation.UnallocatedFee),
+		ClaimableFee:      (*hexutil.Big)(allocation.ClaimableFee),
+		CoinbaseFee:       (*hexutil.Big)(allocation.CoinbaseFee),
+		FeeSplit:          make([]gasTrackerFeeShare, 0, len(allocation.FeeSplit)),
+		Recipients: gasTrackerFeeRecipients{
+			Unallocated: params.PatexBaseFeeRecipient,
+			Claimable:   params.PatexGasAddress,
+		},
+	}
+	for _, contract := range allocation.Contracts {
+		result.Allocations = append(result.Allocations, gasTrackerContract{
+			Address:      contract.Address,
+			GasUsed:      hexutil.Uint64(contract.GasUsed),
+			ScaledGas:    (*hexutil.Big)(contract.ScaledGas),
+			Accumulating: contract.Accumulating,
+			Fee:          (*hexutil.Big)(contract.Fee),
+		})
+	}
+	for _, share := range allocation.FeeSplit {
+		result.FeeSplit = append(result.FeeSplit, gasTrackerFeeShare{
+			Address: share.Address,
+			Fee:     (*hexutil.Big)(share.Fee),
+		})
+	}
+	t.result = result
+}
+
+// GetResult returns the json-encoded gas fee allocation, and any error arising
+// from the encoding or forceful termination (via `Stop`).
+func (t *gasTrackerTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *gasTrackerTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
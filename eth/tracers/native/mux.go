@@ -113,6 +113,16 @@ func (t *muxTracer) CaptureTxEnd(restGas uint64) {
 	}
 }
 
+// CaptureGasAllocation forwards the fee allocation of the gas tracker to the
+// tracers interested in it.
+func (t *muxTracer) CaptureGasAllocation(allocation *vm.GasAllocation) {
+	for _, t := range t.tracers {
+		if t, ok := t.(vm.GasAllocationLogger); ok {
+			t.CaptureGasAllocation(allocation)
+		}
+	}
+}
+
 // GetResult returns an empty json object.
 func (t *muxTracer) GetResult() (json.RawMessage, error) {
 	resObject := make(map[string]json.RawMessage)
@@ -134,6 +134,49 @@ func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []s
 	return &result, err
 }
 
+// GasParametersResult is the decoded gas fee sharing state of a contract.
+type GasParametersResult struct {
+	Address             common.Address `json:"address"`
+	BlockNumber         uint64         `json:"blockNumber"`
+	Timestamp           uint64         `json:"timestamp"`
+	Mode                bool           `json:"mode"`
+	EtherBalance        *big.Int       `json:"etherBalance"`
+	EtherSeconds        *big.Int       `json:"etherSeconds"`
+	LastUpdated         uint64         `json:"lastUpdated"`
+	EtherSecondsAtBlock *big.Int       `json:"etherSecondsAtBlock"`
+}
+
+// GetGasParameters returns the decoded gas fee sharing parameters of the given
+// contract, along with the ether seconds accrued up to the timestamp of the block.
+// The block number can be nil, in which case the value is taken from the latest known block.
+func (ec *Client) GetGasParameters(ctx context.Context, account common.Address, blockNumber *big.Int) (*GasParametersResult, error) {
+	type gasParametersResult struct {
+		Address             common.Address `json:"address"`
+		BlockNumber         hexutil.Uint64 `json:"blockNumber"`
+		Timestamp           hexutil.Uint64 `json:"timestamp"`
+		Mode                bool           `json:"mode"`
+		EtherBalance        *hexutil.Big   `json:"etherBalance"`
+		EtherSeconds        *hexutil.Big   `json:"etherSeconds"`
+		LastUpdated         hexutil.Uint64 `json:"lastUpdated"`
+		EtherSecondsAtBlock *hexutil.Big   `json:"etherSecondsAtBlock"`
+	}
+
+	var res gasParametersResult
+	if err := ec.c.CallContext(ctx, &res, "patex_getGasParameters", account, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return &GasParametersResult{
+		Address:             res.Address,
+		BlockNumber:         uint64(res.BlockNumber),
+		Timestamp:           uint64(res.Timestamp),
+		Mode:                res.Mode,
+		EtherBalance:        res.EtherBalance.ToInt(),
+		EtherSeconds:        res.EtherSeconds.ToInt(),
+		LastUpdated:         uint64(res.LastUpdated),
+		EtherSecondsAtBlock: res.EtherSecondsAtBlock.ToInt(),
+	}, nil
+}
+
 // CallContract executes a message call transaction, which is directly executed in the VM
 // of the node, but never mined into the blockchain.
 //
@@ -0,0 +1,625 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package patexclient provides an RPC client for the Patex specific APIs.
+package patexclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client is a wrapper around rpc.Client that implements the Patex extensions:
+// yield-bearing balances, gas fee sharing, deposits, L1 fees and fee stats.
+//
+// If you want to use the standardized Ethereum RPC functionality, use ethclient.Client instead.
+type Client struct {
+	c *rpc.Client
+}
+
+// New creates a client that uses the given RPC client.
+func New(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+// IsNoHistoricalFallback reports whether a request failed because it concerns
+// pre-Bedrock history and the node has no historical RPC endpoint to serve it.
+func IsNoHistoricalFallback(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpc.ErrNoHistoricalFallback.ErrorCode()
+}
+
+// BalanceValues is the yield-bearing representation of an account balance.
+type BalanceValues struct {
+	Flags     uint8    // Yield mode, one of types.YieldAutomatic, YieldDisabled and YieldClaimable
+	Fixed     *big.Int // Part of the balance held as a plain amount
+	Shares    *big.Int // Number of yield shares
+	Remainder *big.Int // Part of the balance held besides the shares
+}
+
+// BalanceValuesAt returns the yield-bearing balance values of the given account.
+// The block number can be nil, in which case the values are taken from the latest known block.
+func (ec *Client) BalanceValuesAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*BalanceValues, error) {
+	var res struct {
+		Flags     hexutil.Uint64 `json:"flags"`
+		Fixed     *hexutil.Big   `json:"fixed"`
+		Shares    *hexutil.Big   `json:"shares"`
+		Remainder *hexutil.Big   `json:"remainder"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "eth_getBalanceValues", account, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return &BalanceValues{
+		Flags:     uint8(res.Flags),
+		Fixed:     res.Fixed.ToInt(),
+		Shares:    res.Shares.ToInt(),
+		Remainder: res.Remainder.ToInt(),
+	}, nil
+}
+
+// SharePrice is the state of the yield shares at a block.
+type SharePrice struct {
+	BlockNumber uint64
+	SharePrice  *big.Int
+	ShareCount  *big.Int
+}
+
+// SharePriceHistory returns the share price and total share count at the end of
+// every block in the inclusive range [from, to]. Nil block numbers select the
+// latest known block.
+func (ec *Client) SharePriceHistory(ctx context.Context, from, to *big.Int) ([]*SharePrice, error) {
+	var res []struct {
+		BlockNumber hexutil.Uint64 `json:"blockNumber"`
+		SharePrice  *hexutil.Big   `json:"sharePrice"`
+		ShareCount  *hexutil.Big   `json:"shareCount"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "patex_getSharePriceHistory", toBlockNumArg(from), toBlockNumArg(to)); err != nil {
+		return nil, err
+	}
+	prices := make([]*SharePrice, len(res))
+	for i, r := range res {
+		prices[i] = &SharePrice{
+			BlockNumber: uint64(r.BlockNumber),
+			SharePrice:  r.SharePrice.ToInt(),
+			ShareCount:  r.ShareCount.ToInt(),
+		}
+	}
+	return prices, nil
+}
+
+// AccruedYield is the yield an account accrued over a range of blocks.
+type AccruedYield struct {
+	Address   common.Address
+	FromBlock uint64
+	ToBlock   uint64
+	Flags     uint8 // Yield mode at the end of the range
+	Yield     *big.Int
+}
+
+// AccruedYield returns the yield the given account accrued after the from block
+// up to and including the to block. Nil block numbers select the latest known block.
+func (ec *Client) AccruedYield(ctx context.Context, account common.Address, from, to *big.Int) (*AccruedYield, error) {
+	var res struct {
+		Address   common.Address `json:"address"`
+		FromBlock hexutil.Uint64 `json:"fromBlock"`
+		ToBlock   hexutil.Uint64 `json:"toBlock"`
+		Flags     hexutil.Uint64 `json:"flags"`
+		Yield     *hexutil.Big   `json:"yield"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "patex_getAccruedYield", account, toBlockNumArg(from), toBlockNumArg(to)); err != nil {
+		return nil, err
+	}
+	return &AccruedYield{
+		Address:   res.Address,
+		FromBlock: uint64(res.FromBlock),
+		ToBlock:   uint64(res.ToBlock),
+		Flags:     uint8(res.Flags),
+		Yield:     res.Yield.ToInt(),
+	}, nil
+}
+
+// YieldModeChange is an account whose yield mode was changed by a block.
+type YieldModeChange struct {
+	Address       common.Address
+	PreviousFlags uint8
+	Flags         uint8
+}
+
+// YieldModeChanges returns the accounts whose yield mode was changed by the given
+// block. The block number can be nil, in which case the latest known block is used.
+func (ec *Client) YieldModeChanges(ctx context.Context, blockNumber *big.Int) ([]*YieldModeChange, error) {
+	var res []struct {
+		Address       common.Address `json:"address"`
+		PreviousFlags hexutil.Uint64 `json:"previousFlags"`
+		Flags         hexutil.Uint64 `json:"flags"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "patex_getYieldModeChanges", toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	changes := make([]*YieldModeChange, len(res))
+	for i, r := range res {
+		changes[i] = &YieldModeChange{
+			Address:       r.Address,
+			PreviousFlags: uint8(r.PreviousFlags),
+			Flags:         uint8(r.Flags),
+		}
+	}
+	return changes, nil
+}
+
+// GasParameters is the decoded gas fee sharing state of a contract.
+type GasParameters = gethclient.GasParametersResult
+
+// GasParametersAt returns the decoded gas fee sharing parameters of the given
+// contract, along with the gas it can claim at the timestamp of the block.
+// The block number can be nil, in which case the value is taken from the latest known block.
+func (ec *Client) GasParametersAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (*GasParameters, error) {
+	return gethclient.New(ec.c).GetGasParameters(ctx, contract, blockNumber)
+}
+
+// Deposit is an included deposit transaction together with the outcome of its
+// execution, as far as the receipt is available.
+type Deposit struct {
+	SourceHash       common.Hash
+	TransactionHash  common.Hash
+	BlockHash        common.Hash
+	BlockNumber      uint64
+	TransactionIndex uint64
+	From             common.Address
+	To               *common.Address // Nil for contract creations
+	Mint             *big.Int
+	Value            *big.Int
+	Gas              uint64
+	IsSystemTx       bool
+	Status           *uint64 // Nil if the receipt is not available
+	DepositNonce     *uint64 // Nil before Regolith or if the receipt is not available
+}
+
+type rpcDeposit struct {
+	SourceHash       common.Hash     `json:"sourceHash"`
+	TransactionHash  common.Hash     `json:"transactionHash"`
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	From             common.Address  `json:"from"`
+	To               *common.Address `json:"to"`
+	Mint             *hexutil.Big    `json:"mint"`
+	Value            *hexutil.Big    `json:"value"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	IsSystemTx       bool            `json:"isSystemTx"`
+	Status           *hexutil.Uint64 `json:"status"`
+	DepositNonce     *hexutil.Uint64 `json:"depositNonce"`
+}
+
+func (d *rpcDeposit) toDeposit() *Deposit {
+	return &Deposit{
+		SourceHash:       d.SourceHash,
+		TransactionHash:  d.TransactionHash,
+		BlockHash:        d.BlockHash,
+		BlockNumber:      uint64(d.BlockNumber),
+		TransactionIndex: uint64(d.TransactionIndex),
+		From:             d.From,
+		To:               d.To,
+		Mint:             d.Mint.ToInt(),
+		Value:            d.Value.ToInt(),
+		Gas:              uint64(d.Gas),
+		IsSystemTx:       d.IsSystemTx,
+		Status:           (*uint64)(d.Status),
+		DepositNonce:     (*uint64)(d.DepositNonce),
+	}
+}
+
+// DepositBySourceHash returns the deposit with the given source hash, if it is
+// included in the canonical chain.
+func (ec *Client) DepositBySourceHash(ctx context.Context, sourceHash common.Hash) (*Deposit, error) {
+	var res *rpcDeposit
+	if err := ec.c.CallContext(ctx, &res, "patex_getDepositBySourceHash", sourceHash); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ethereum.NotFound
+	}
+	return res.toDeposit(), nil
+}
+
+// BlockDeposits are the deposits of a block and the value they minted.
+type BlockDeposits struct {
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Deposits    []*Deposit
+	TotalMint   *big.Int
+}
+
+// BlockDeposits returns the deposits of the given block. The block number can be
+// nil, in which case the latest known block is used.
+func (ec *Client) BlockDeposits(ctx context.Context, blockNumber *big.Int) (*BlockDeposits, error) {
+	var res struct {
+		BlockHash   common.Hash    `json:"blockHash"`
+		BlockNumber hexutil.Uint64 `json:"blockNumber"`
+		Deposits    []*rpcDeposit  `json:"deposits"`
+		TotalMint   *hexutil.Big   `json:"totalMint"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "patex_getBlockDeposits", toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	deposits := make([]*Deposit, len(res.Deposits))
+	for i, d := range res.Deposits {
+		deposits[i] = d.toDeposit()
+	}
+	return &BlockDeposits{
+		BlockHash:   res.BlockHash,
+		BlockNumber: uint64(res.BlockNumber),
+		Deposits:    deposits,
+		TotalMint:   res.TotalMint.ToInt(),
+	}, nil
+}
+
+// FeeStats summarizes the fees collected by the blocks in the inclusive range
+// [FromBlock, ToBlock], split by the recipient they were credited to.
+type FeeStats struct {
+	FromBlock uint64
+	ToBlock   uint64
+
+	DepositCount   uint64
+	DepositGasUsed uint64
+	UserTxCount    uint64
+	UserGasUsed    uint64
+
+	RollupDataGas uint64
+	L1GasUsed     uint64
+	L1Fee         *big.Int
+	L1DataCost    *big.Int // Data gas priced at the L1 base fee, the estimated cost of posting to L1
+
+	BaseFeeRecipientFee *big.Int
+	ClaimableFee        *big.Int
+	FeeSplitFee         *big.Int
+	CoinbaseFee         *big.Int
+}
+
+type rpcFeeStats struct {
+	FromBlock           hexutil.Uint64 `json:"fromBlock"`
+	ToBlock             hexutil.Uint64 `json:"toBlock"`
+	DepositCount        hexutil.Uint64 `json:"depositCount"`
+	DepositGasUsed      hexutil.Uint64 `json:"depositGasUsed"`
+	UserTxCount         hexutil.Uint64 `json:"userTxCount"`
+	UserGasUsed         hexutil.Uint64 `json:"userGasUsed"`
+	RollupDataGas       hexutil.Uint64 `json:"rollupDataGas"`
+	L1GasUsed           hexutil.Uint64 `json:"l1GasUsed"`
+	L1Fee               *hexutil.Big   `json:"l1Fee"`
+	L1DataCost          *hexutil.Big   `json:"l1DataCost"`
+	BaseFeeRecipientFee *hexutil.Big   `json:"baseFeeRecipientFee"`
+	ClaimableFee        *hexutil.Big   `json:"claimableFee"`
+	FeeSplitFee         *hexutil.Big   `json:"feeSplitFee"`
+	CoinbaseFee         *hexutil.Big   `json:"coinbaseFee"`
+}
+
+func (ec *Client) feeStats(ctx context.Context, method string, args ...interface{}) (*FeeStats, error) {
+	var res rpcFeeStats
+	if err := ec.c.CallContext(ctx, &res, method, args...); err != nil {
+		return nil, err
+	}
+	return &FeeStats{
+		FromBlock:           uint64(res.FromBlock),
+		ToBlock:             uint64(res.ToBlock),
+		DepositCount:        uint64(res.DepositCount),
+		DepositGasUsed:      uint64(res.DepositGasUsed),
+		UserTxCount:         uint64(res.UserTxCount),
+		UserGasUsed:         uint64(res.UserGasUsed),
+		RollupDataGas:       uint64(res.RollupDataGas),
+		L1GasUsed:           uint64(res.L1GasUsed),
+		L1Fee:               res.L1Fee.ToInt(),
+		L1DataCost:          res.L1DataCost.ToInt(),
+		BaseFeeRecipientFee: res.BaseFeeRecipientFee.ToInt(),
+		ClaimableFee:        res.ClaimableFee.ToInt(),
+		FeeSplitFee:         res.FeeSplitFee.ToInt(),
+		CoinbaseFee:         res.CoinbaseFee.ToInt(),
+	}, nil
+}
+
+// BlockFeeStats returns the fees collected by the given block. The block number
+// can be nil, in which case the latest known block is used.
+func (ec *Client) BlockFeeStats(ctx context.Context, blockNumber *big.Int) (*FeeStats, error) {
+	return ec.feeStats(ctx, "patex_getBlockFeeStats", toBlockNumArg(blockNumber))
+}
+
+// FeeStatsRange returns the fees collected by the blocks in the inclusive range
+// [from, to], summed up over all blocks. Nil block numbers select the latest known block.
+func (ec *Client) FeeStatsRange(ctx context.Context, from, to *big.Int) (*FeeStats, error) {
+	return ec.feeStats(ctx, "patex_getFeeStatsRange", toBlockNumArg(from), toBlockNumArg(to))
+}
+
+// L1FeeEstimate is the breakdown of the L1 data fee a transaction is charged.
+type L1FeeEstimate struct {
+	DataGas   uint64
+	L1BaseFee *big.Int
+	Overhead  *big.Int
+	Scalar    *big.Int
+	L1Fee     *big.Int
+}
+
+// EstimateL1Fee returns the L1 data fee the given message would be charged if it
+// were included on top of the given block. The block number can be nil, in which
+// case the pending block is used.
+func (ec *Client) EstimateL1Fee(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (*L1FeeEstimate, error) {
+	var res struct {
+		DataGas   hexutil.Uint64 `json:"dataGas"`
+		L1BaseFee *hexutil.Big   `json:"l1BaseFee"`
+		Overhead  *hexutil.Big   `json:"overhead"`
+		Scalar    *hexutil.Big   `json:"scalar"`
+		L1Fee     *hexutil.Big   `json:"l1Fee"`
+	}
+	block := "pending"
+	if blockNumber != nil {
+		block = toBlockNumArg(blockNumber)
+	}
+	if err := ec.c.CallContext(ctx, &res, "eth_estimateL1Fee", toCallArg(msg), block); err != nil {
+		return nil, err
+	}
+	return &L1FeeEstimate{
+		DataGas:   uint64(res.DataGas),
+		L1BaseFee: res.L1BaseFee.ToInt(),
+		Overhead:  res.Overhead.ToInt(),
+		Scalar:    res.Scalar.ToInt(),
+		L1Fee:     res.L1Fee.ToInt(),
+	}, nil
+}
+
+// SendTransactionConditional injects a signed transaction into the pending pool
+// for execution, to be included only as long as the given conditions hold.
+func (ec *Client) SendTransactionConditional(ctx context.Context, tx *types.Transaction, cond types.TransactionConditional) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return ec.c.CallContext(ctx, nil, "eth_sendRawTransactionConditional", hexutil.Encode(data), cond)
+}
+
+// Transaction is a transaction as returned by the node, with its position in
+// the chain if it is included.
+type Transaction struct {
+	Tx          *types.Transaction
+	Deposit     *types.DepositTx // Deposit fields, nil for other transactions
+	Nonce       *uint64          // Nonce a deposit was executed with, nil before Regolith
+	From        common.Address
+	BlockHash   *common.Hash
+	BlockNumber *big.Int
+	Index       *uint64
+}
+
+// rpcDepositTx holds the JSON-RPC fields of a deposit transaction. The generic
+// transaction decoding in core/types loses them, as it keeps the execution
+// nonce of the deposit in a wrapper of the DepositTx.
+type rpcDepositTx struct {
+	SourceHash common.Hash     `json:"sourceHash"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	Mint       *hexutil.Big    `json:"mint"`
+	Value      *hexutil.Big    `json:"value"`
+	Gas        hexutil.Uint64  `json:"gas"`
+	IsSystemTx bool            `json:"isSystemTx"`
+	Input      hexutil.Bytes   `json:"input"`
+	Nonce      *hexutil.Uint64 `json:"nonce"`
+}
+
+// DecodeDeposit decodes the JSON-RPC representation of a deposit transaction, as
+// returned by eth_getTransactionByHash and the block methods. It also returns the
+// nonce the deposit was executed with, if present.
+func DecodeDeposit(input []byte) (*types.DepositTx, *uint64, error) {
+	var dec struct {
+		Type hexutil.Uint64 `json:"type"`
+		rpcDepositTx
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return nil, nil, err
+	}
+	if dec.Type != types.DepositTxType {
+		return nil, nil, fmt.Errorf("not a deposit transaction: type %d", dec.Type)
+	}
+	if dec.Value == nil {
+		return nil, nil, errors.New("missing required field 'value' in deposit transaction")
+	}
+	return &types.DepositTx{
+		SourceHash:          dec.SourceHash,
+		From:                dec.From,
+		To:                  dec.To,
+		Mint:                (*big.Int)(dec.Mint),
+		Value:               (*big.Int)(dec.Value),
+		Gas:                 uint64(dec.Gas),
+		IsSystemTransaction: dec.IsSystemTx,
+		Data:                dec.Input,
+	}, (*uint64)(dec.Nonce), nil
+}
+
+// TransactionByHash returns the transaction with the given hash, decoding the
+// fields of deposits.
+func (ec *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*Transaction, error) {
+	var raw json.RawMessage
+	if err := ec.c.CallContext(ctx, &raw, "eth_getTransactionByHash", hash); err != nil {
+		return nil, err
+	}
+	var info *struct {
+		Type        hexutil.Uint64  `json:"type"`
+		From        common.Address  `json:"from"`
+		BlockHash   *common.Hash    `json:"blockHash"`
+		BlockNumber *hexutil.Big    `json:"blockNumber"`
+		Index       *hexutil.Uint64 `json:"transactionIndex"`
+	}
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, ethereum.NotFound
+	}
+	result := &Transaction{
+		From:        info.From,
+		BlockHash:   info.BlockHash,
+		BlockNumber: info.BlockNumber.ToInt(),
+		Index:       (*uint64)(info.Index),
+	}
+	if info.Type == types.DepositTxType {
+		deposit, nonce, err := DecodeDeposit(raw)
+		if err != nil {
+			return nil, err
+		}
+		result.Tx, result.Deposit, result.Nonce = types.NewTx(deposit), deposit, nonce
+		return result, nil
+	}
+	if err := json.Unmarshal(raw, &result.Tx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Receipt is a transaction receipt as returned by the node. The embedded receipt
+// carries the L1 fee fields of user transactions, L1GasPrice, L1GasUsed, L1Fee and
+// FeeScalar, and the DepositNonce of deposits.
+type Receipt struct {
+	*types.Receipt
+	From common.Address
+	To   *common.Address // Nil for contract creations
+}
+
+// UnmarshalJSON decodes the JSON-RPC representation of a receipt, which encodes
+// the deposit nonce as a quantity rather than the number the receipt expects.
+func (r *Receipt) UnmarshalJSON(input []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return err
+	}
+	var dec struct {
+		From         common.Address  `json:"from"`
+		To           *common.Address `json:"to"`
+		DepositNonce *hexutil.Uint64 `json:"depositNonce"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	delete(fields, "depositNonce")
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	receipt := new(types.Receipt)
+	if err := receipt.UnmarshalJSON(stripped); err != nil {
+		return err
+	}
+	receipt.DepositNonce = (*uint64)(dec.DepositNonce)
+	r.Receipt, r.From, r.To = receipt, dec.From, dec.To
+	return nil
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+// Note that the receipt is not available for pending transactions.
+func (ec *Client) TransactionReceipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	var r *Receipt
+	if err := ec.c.CallContext(ctx, &r, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, ethereum.NotFound
+	}
+	return r, nil
+}
+
+// PayloadTransaction is a transaction appended to a payload under construction,
+// along with the receipt of its execution on top of the payload so far.
+type PayloadTransaction struct {
+	PayloadID   engine.PayloadID
+	Build       uint64 // Build of the payload, a higher build supersedes the earlier ones
+	Index       uint64
+	Transaction *types.Transaction
+	Receipt     *types.Receipt
+}
+
+// UnmarshalJSON decodes a payload transaction notification.
+func (p *PayloadTransaction) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		PayloadID   engine.PayloadID   `json:"payloadId"`
+		Build       hexutil.Uint64     `json:"build"`
+		Index       hexutil.Uint64     `json:"transactionIndex"`
+		Transaction *types.Transaction `json:"transaction"`
+		Receipt     *types.Receipt     `json:"receipt"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*p = PayloadTransaction{
+		PayloadID:   dec.PayloadID,
+		Build:       uint64(dec.Build),
+		Index:       uint64(dec.Index),
+		Transaction: dec.Transaction,
+		Receipt:     dec.Receipt,
+	}
+	return nil
+}
+
+// SubscribePayloadTransactions subscribes to the transactions the sequencer
+// appends to payloads while building them.
+func (ec *Client) SubscribePayloadTransactions(ctx context.Context, ch chan<- *PayloadTransaction) (ethereum.Subscription, error) {
+	return ec.c.Subscribe(ctx, "patex", ch, "payloadTransactions")
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Sign() >= 0 {
+		return hexutil.EncodeBig(number)
+	}
+	// It's negative.
+	if number.IsInt64() {
+		return rpc.BlockNumber(number.Int64()).String()
+	}
+	// It's negative and large, which is invalid.
+	return fmt.Sprintf("<invalid %d>", number)
+}
+
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	return arg
+}
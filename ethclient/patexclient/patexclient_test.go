@@ -0,0 +1,303 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package patexclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr    = crypto.PubkeyToAddress(testKey.PublicKey)
+	testBalance = big.NewInt(params.Ether)
+	testDad     = common.HexToAddress("0x0000000000000000000000000000000000000dad")
+
+	// The L1 fee parameters, both in the L1 block contract for execution and
+	// in the L1 info deposit for deriving the receipt fields.
+	testL1BaseFee = big.NewInt(1000)
+	testOverhead  = big.NewInt(2100)
+	testScalar    = big.NewInt(1_000_000)
+)
+
+// historyService fails every request as a node without historical RPC endpoint
+// does for pre-Bedrock blocks.
+type historyService struct{}
+
+func (historyService) Block() error { return rpc.ErrNoHistoricalFallback }
+
+func newTestBackend(t *testing.T) (*node.Node, *types.Transaction, *types.Transaction) {
+	config := *params.AllEthashProtocolChanges
+	config.TerminalTotalDifficulty = common.Big0
+	config.TerminalTotalDifficultyPassed = true
+	config.BedrockBlock = common.Big0
+	config.RegolithTime = new(uint64)
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	l1Info := make([]byte, 4+32*8)
+	testL1BaseFee.FillBytes(l1Info[4+32*2 : 4+32*3])
+	testOverhead.FillBytes(l1Info[4+32*6 : 4+32*7])
+	testScalar.FillBytes(l1Info[4+32*7 : 4+32*8])
+
+	genesis := &core.Genesis{
+		Config:   &config,
+		GasLimit: 11500000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			testAddr: {Balance: testBalance},
+			types.L1BlockAddr: {
+				Nonce:   1, // Not empty, otherwise the storage is wiped
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot: common.BigToHash(testL1BaseFee),
+					types.OverheadSlot:  common.BigToHash(testOverhead),
+					types.ScalarSlot:    common.BigToHash(testScalar),
+				},
+			},
+		},
+	}
+	// Create node
+	n, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("can't create new node: %v", err)
+	}
+	// Create Ethereum Service
+	ethConf := &ethconfig.Config{Genesis: genesis}
+	ethConf.Ethash.PowMode = ethash.ModeFake
+	ethservice, err := eth.New(n, ethConf)
+	if err != nil {
+		t.Fatalf("can't create new ethereum service: %v", err)
+	}
+	n.RegisterAPIs([]rpc.API{{Namespace: "history", Service: historyService{}}})
+
+	// Generate a block with the L1 info deposit, a user deposit and a
+	// user transaction.
+	var deposit, tx *types.Transaction
+	signer := types.LatestSigner(genesis.Config)
+	blocks, _ := core.GenerateChain(genesis.Config, ethservice.BlockChain().Genesis(), ethservice.Engine(), ethservice.ChainDb(), 1, func(i int, gen *core.BlockGen) {
+		gen.AddTx(types.NewTx(&types.DepositTx{To: &types.L1BlockAddr, Value: new(big.Int), Gas: 1_000_000, Data: l1Info}))
+		deposit = types.NewTx(&types.DepositTx{SourceHash: common.Hash{1}, From: testDad, To: &testDad, Mint: big.NewInt(100), Value: new(big.Int), Gas: 100000})
+		gen.AddTx(deposit)
+		tx, _ = types.SignNewTx(testKey, signer, &types.LegacyTx{To: &testDad, Gas: 100000, GasPrice: big.NewInt(2 * params.InitialBaseFee)})
+		gen.AddTx(tx)
+	})
+	// Import the test chain.
+	if err := n.Start(); err != nil {
+		t.Fatalf("can't start test node: %v", err)
+	}
+	if _, err := ethservice.BlockChain().InsertChain(blocks); err != nil {
+		t.Fatalf("can't import test blocks: %v", err)
+	}
+	return n, deposit, tx
+}
+
+func TestPatexClient(t *testing.T) {
+	backend, deposit, tx := newTestBackend(t)
+	client, err := backend.Attach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	defer client.Close()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"TestBalanceValues",
+			func(t *testing.T) { testBalanceValues(t, client) },
+		}, {
+			"TestTransactionByHash",
+			func(t *testing.T) { testTransactionByHash(t, client, deposit, tx) },
+		}, {
+			"TestTransactionReceipt",
+			func(t *testing.T) { testTransactionReceipt(t, client, deposit, tx) },
+		}, {
+			"TestDeposits",
+			func(t *testing.T) { testDeposits(t, client, deposit) },
+		}, {
+			"TestFeeStats",
+			func(t *testing.T) { testFeeStats(t, client) },
+		}, {
+			"TestEstimateL1Fee",
+			func(t *testing.T) { testEstimateL1Fee(t, client) },
+		}, {
+			"TestNoHistoricalFallback",
+			func(t *testing.T) { testNoHistoricalFallback(t, client) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.test)
+	}
+}
+
+func testBalanceValues(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	values, err := ec.BalanceValuesAt(context.Background(), testAddr, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a share price, the balance of an automatic yield account is
+	// held entirely as remainder.
+	if values.Flags != types.YieldAutomatic || values.Fixed.Sign() != 0 || values.Shares.Sign() != 0 {
+		t.Fatalf("unexpected balance values: %+v", values)
+	}
+	if values.Remainder.Cmp(testBalance) != 0 {
+		t.Fatalf("remainder mismatch: have %v, want %v", values.Remainder, testBalance)
+	}
+}
+
+func testTransactionByHash(t *testing.T, client *rpc.Client, deposit, tx *types.Transaction) {
+	ec := New(client)
+	res, err := ec.TransactionByHash(context.Background(), deposit.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Deposit == nil {
+		t.Fatal("deposit fields not decoded")
+	}
+	if res.Tx.Hash() != deposit.Hash() {
+		t.Fatalf("deposit hash mismatch: have %x, want %x", res.Tx.Hash(), deposit.Hash())
+	}
+	if res.Deposit.SourceHash != (common.Hash{1}) || res.Deposit.Mint.Cmp(big.NewInt(100)) != 0 || res.Deposit.From != testDad {
+		t.Fatalf("unexpected deposit: %+v", res.Deposit)
+	}
+	if res.Nonce == nil || *res.Nonce != 0 {
+		t.Fatalf("unexpected deposit nonce: %v", res.Nonce)
+	}
+	if res.BlockNumber == nil || res.BlockNumber.Uint64() != 1 || res.Index == nil || *res.Index != 1 {
+		t.Fatalf("unexpected deposit position: block %v, index %v", res.BlockNumber, res.Index)
+	}
+	res, err = ec.TransactionByHash(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Deposit != nil || res.Tx.Hash() != tx.Hash() || res.From != testAddr {
+		t.Fatalf("unexpected transaction: %+v", res)
+	}
+	if _, err := ec.TransactionByHash(context.Background(), common.Hash{}); err != ethereum.NotFound {
+		t.Fatalf("want %v, have %v", ethereum.NotFound, err)
+	}
+}
+
+func testTransactionReceipt(t *testing.T, client *rpc.Client, deposit, tx *types.Transaction) {
+	ec := New(client)
+	receipt, err := ec.TransactionReceipt(context.Background(), deposit.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.DepositNonce == nil || *receipt.DepositNonce != 0 {
+		t.Fatalf("unexpected deposit nonce: %v", receipt.DepositNonce)
+	}
+	if receipt.L1Fee != nil {
+		t.Fatalf("deposit charged L1 fee: %v", receipt.L1Fee)
+	}
+	receipt, err = ec.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.From != testAddr || receipt.To == nil || *receipt.To != testDad {
+		t.Fatalf("unexpected receipt sender or recipient: %v, %v", receipt.From, receipt.To)
+	}
+	if receipt.L1GasPrice.Cmp(testL1BaseFee) != 0 || receipt.L1GasUsed.Uint64() != 0x59c || receipt.L1Fee.Uint64() != 0x35f480 {
+		t.Fatalf("unexpected L1 fee fields: price %v, gas %v, fee %v", receipt.L1GasPrice, receipt.L1GasUsed, receipt.L1Fee)
+	}
+	if scalar, _ := receipt.FeeScalar.Float64(); scalar != 1 {
+		t.Fatalf("fee scalar mismatch: have %v, want 1", receipt.FeeScalar)
+	}
+}
+
+func testDeposits(t *testing.T, client *rpc.Client, deposit *types.Transaction) {
+	ec := New(client)
+	res, err := ec.DepositBySourceHash(context.Background(), common.Hash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TransactionHash != deposit.Hash() || res.Mint.Cmp(big.NewInt(100)) != 0 || res.BlockNumber != 1 {
+		t.Fatalf("unexpected deposit: %+v", res)
+	}
+	if res.Status == nil || *res.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("unexpected deposit status: %v", res.Status)
+	}
+	if _, err := ec.DepositBySourceHash(context.Background(), common.Hash{2}); err != ethereum.NotFound {
+		t.Fatalf("want %v, have %v", ethereum.NotFound, err)
+	}
+	block, err := ec.BlockDeposits(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.BlockNumber != 1 || len(block.Deposits) != 2 || block.TotalMint.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("unexpected block deposits: %+v", block)
+	}
+}
+
+func testFeeStats(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	block, err := ec.BlockFeeStats(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.DepositCount != 2 || block.UserTxCount != 1 || block.L1Fee.Uint64() != 0x35f480 {
+		t.Fatalf("unexpected block fee stats: %+v", block)
+	}
+	stats, err := ec.FeeStatsRange(context.Background(), big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.FromBlock != 1 || stats.ToBlock != 1 || stats.UserTxCount != 1 || stats.L1Fee.Cmp(block.L1Fee) != 0 {
+		t.Fatalf("unexpected range fee stats: %+v", stats)
+	}
+}
+
+func testEstimateL1Fee(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	estimate, err := ec.EstimateL1Fee(context.Background(), ethereum.CallMsg{From: testAddr, To: &testDad, Data: []byte{1, 2, 3}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.L1BaseFee.Cmp(testL1BaseFee) != 0 || estimate.Overhead.Cmp(testOverhead) != 0 || estimate.Scalar.Cmp(testScalar) != 0 {
+		t.Fatalf("unexpected L1 fee parameters: %+v", estimate)
+	}
+	if estimate.DataGas == 0 || estimate.L1Fee.Sign() <= 0 {
+		t.Fatalf("unexpected L1 fee: %+v", estimate)
+	}
+}
+
+func testNoHistoricalFallback(t *testing.T, client *rpc.Client) {
+	err := client.CallContext(context.Background(), nil, "history_block")
+	if !IsNoHistoricalFallback(err) {
+		t.Fatalf("historical fallback error not detected: %v", err)
+	}
+	if IsNoHistoricalFallback(errors.New("other error")) {
+		t.Fatal("unrelated error detected as historical fallback error")
+	}
+}
@@ -89,11 +89,27 @@ func (a *Account) getState(ctx context.Context) (*state.StateDB, error) {
 	return state, err
 }
 
+// historicalCall routes a request about the state of a pre-Bedrock block to the
+// historical backends, appending the block number to the arguments. It reports
+// whether the block predates Bedrock.
+func historicalCall(ctx context.Context, backend ethapi.Backend, blockNrOrHash rpc.BlockNumberOrHash, result interface{}, method string, args ...interface{}) (bool, error) {
+	header, err := backend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil || header == nil || !backend.ChainConfig().IsPatexPreBedrock(header.Number) {
+		return false, nil
+	}
+	number := header.Number.Uint64()
+	return true, backend.HistoricalRouter().Call(ctx, result, number, method, append(args, hexutil.Uint64(number))...)
+}
+
 func (a *Account) Address(ctx context.Context) (common.Address, error) {
 	return a.address, nil
 }
 
 func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
+	var res hexutil.Big
+	if routed, err := historicalCall(ctx, a.r.backend, a.blockNrOrHash, &res, "eth_getBalance", a.address); routed {
+		return res, err
+	}
 	state, err := a.getState(ctx)
 	if err != nil {
 		return hexutil.Big{}, err
@@ -114,6 +130,10 @@ func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error)
 		}
 		return hexutil.Uint64(nonce), nil
 	}
+	var res hexutil.Uint64
+	if routed, err := historicalCall(ctx, a.r.backend, a.blockNrOrHash, &res, "eth_getTransactionCount", a.address); routed {
+		return res, err
+	}
 	state, err := a.getState(ctx)
 	if err != nil {
 		return 0, err
@@ -122,6 +142,10 @@ func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error)
 }
 
 func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
+	var res hexutil.Bytes
+	if routed, err := historicalCall(ctx, a.r.backend, a.blockNrOrHash, &res, "eth_getCode", a.address); routed {
+		return res, err
+	}
 	state, err := a.getState(ctx)
 	if err != nil {
 		return hexutil.Bytes{}, err
@@ -130,6 +154,10 @@ func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
 }
 
 func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash }) (common.Hash, error) {
+	var res hexutil.Bytes
+	if routed, err := historicalCall(ctx, a.r.backend, a.blockNrOrHash, &res, "eth_getStorageAt", a.address, args.Slot); routed {
+		return common.BytesToHash(res), err
+	}
 	state, err := a.getState(ctx)
 	if err != nil {
 		return common.Hash{}, err
@@ -137,6 +165,55 @@ func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash })
 	return state.GetState(a.address, args.Slot), nil
 }
 
+// getBalanceValues fetches the yield representation of the account balance.
+func (a *Account) getBalanceValues(ctx context.Context) (*state.BalanceValues, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return state.GetBalanceValues(a.address), nil
+}
+
+func (a *Account) Flags(ctx context.Context) (int32, error) {
+	values, err := a.getBalanceValues(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(values.Flags), nil
+}
+
+func (a *Account) Fixed(ctx context.Context) (hexutil.Big, error) {
+	values, err := a.getBalanceValues(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*values.Fixed), nil
+}
+
+func (a *Account) Shares(ctx context.Context) (hexutil.Big, error) {
+	values, err := a.getBalanceValues(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*values.Shares), nil
+}
+
+func (a *Account) Remainder(ctx context.Context) (hexutil.Big, error) {
+	values, err := a.getBalanceValues(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*values.Remainder), nil
+}
+
+func (a *Account) ClaimableAmount(ctx context.Context) (hexutil.Big, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*state.GetClaimableAmount(a.address)), nil
+}
+
 // Log represents an individual log message. All arguments are mandatory.
 type Log struct {
 	r           *Resolver
@@ -182,6 +259,33 @@ func (at *AccessTuple) StorageKeys(ctx context.Context) []common.Hash {
 	return at.storageKeys
 }
 
+// Deposit represents the deposit specific fields of a transaction.
+type Deposit struct {
+	transaction *Transaction
+	tx          *types.Transaction
+}
+
+func (d *Deposit) SourceHash(ctx context.Context) common.Hash {
+	return d.tx.SourceHash()
+}
+
+func (d *Deposit) Mint(ctx context.Context) *hexutil.Big {
+	return (*hexutil.Big)(d.tx.Mint())
+}
+
+func (d *Deposit) IsSystemTx(ctx context.Context) bool {
+	return d.tx.IsSystemTx()
+}
+
+func (d *Deposit) Nonce(ctx context.Context) (*Long, error) {
+	receipt, err := d.transaction.getReceipt(ctx)
+	if err != nil || receipt == nil || receipt.DepositNonce == nil {
+		return nil, err
+	}
+	ret := Long(*receipt.DepositNonce)
+	return &ret, nil
+}
+
 // Transaction represents an Ethereum transaction.
 // backend and hash are mandatory; all others will be fetched when required.
 type Transaction struct {
@@ -571,6 +675,38 @@ func (t *Transaction) RawReceipt(ctx context.Context) (hexutil.Bytes, error) {
 	return receipt.MarshalBinary()
 }
 
+func (t *Transaction) Deposit(ctx context.Context) (*Deposit, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil || !tx.IsDepositTx() {
+		return nil, err
+	}
+	return &Deposit{transaction: t, tx: tx}, nil
+}
+
+func (t *Transaction) L1Fee(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1Fee), nil
+}
+
+func (t *Transaction) L1GasUsed(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1GasUsed), nil
+}
+
+func (t *Transaction) L1GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1GasPrice), nil
+}
+
 type BlockType int
 
 // Block represents an Ethereum block.
@@ -1067,6 +1203,15 @@ func (c *CallResult) Status() Long {
 func (b *Block) Call(ctx context.Context, args struct {
 	Data ethapi.TransactionArgs
 }) (*CallResult, error) {
+	// Pre-Bedrock calls are executed by the historical backends, which don't
+	// report the gas used by a call.
+	var data hexutil.Bytes
+	if routed, err := historicalCall(ctx, b.r.backend, *b.numberOrHash, &data, "eth_call", args.Data); routed {
+		if err != nil {
+			return nil, err
+		}
+		return &CallResult{data: data, status: 1}, nil
+	}
 	result, err := ethapi.DoCall(ctx, b.r.backend, args.Data, *b.numberOrHash, nil, b.r.backend.RPCEVMTimeout(), b.r.backend.RPCGasCap())
 	if err != nil {
 		return nil, err
@@ -1086,6 +1231,10 @@ func (b *Block) Call(ctx context.Context, args struct {
 func (b *Block) EstimateGas(ctx context.Context, args struct {
 	Data ethapi.TransactionArgs
 }) (Long, error) {
+	var res hexutil.Uint64
+	if routed, err := historicalCall(ctx, b.r.backend, *b.numberOrHash, &res, "eth_estimateGas", args.Data); routed {
+		return Long(res), err
+	}
 	gas, err := ethapi.DoEstimateGas(ctx, b.r.backend, args.Data, *b.numberOrHash, b.r.backend.RPCGasCap())
 	return Long(gas), err
 }
@@ -23,6 +23,8 @@ import (
 	"io"
 	"math/big"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -270,6 +272,39 @@ func TestGraphQLHTTPOnSamePort_GQLRequest_Unsuccessful(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
+// Tests that GraphQL can't be enabled along with API keys, as its queries would
+// bypass the method policies of the keys.
+func TestGraphQLAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apikeys.json")
+	if err := os.WriteFile(path, []byte(`{"keys": [{"name": "team", "key": "secret", "allow": ["eth_*"]}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stack, err := node.New(&node.Config{
+		HTTPHost:     "127.0.0.1",
+		HTTPPort:     0,
+		HTTPTimeouts: node.DefaultConfig.HTTPTimeouts,
+		APIKeysFile:  path,
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer stack.Close()
+
+	if err := New(stack, nil, nil, nil, nil); err != errAPIKeys {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAPIKeys)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	body := strings.NewReader(`{"query": "{block{number}}","variables": null}`)
+	resp, err := http.Post(fmt.Sprintf("%s/graphql", stack.HTTPEndpoint()), "application/json", body)
+	if err != nil {
+		t.Fatalf("could not post: %v", err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
 func TestGraphQLConcurrentResolvers(t *testing.T) {
 	var (
 		key, _  = crypto.GenerateKey()
@@ -364,6 +399,95 @@ func TestGraphQLConcurrentResolvers(t *testing.T) {
 	}
 }
 
+func TestGraphQLPatexFields(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		dad    = common.HexToAddress("0x0000000000000000000000000000000000000dad")
+		config = *params.AllEthashProtocolChanges
+	)
+	config.TerminalTotalDifficulty = common.Big0
+	config.TerminalTotalDifficultyPassed = true
+	config.BedrockBlock = common.Big0
+	config.RegolithTime = new(uint64)
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	// The L1 fee parameters, both in the L1 block contract for execution and
+	// in the L1 info deposit for deriving the receipt fields.
+	var (
+		l1BaseFee = big.NewInt(1000)
+		overhead  = big.NewInt(2100)
+		scalar    = big.NewInt(1_000_000)
+		l1Info    = make([]byte, 4+32*8)
+	)
+	l1BaseFee.FillBytes(l1Info[4+32*2 : 4+32*3])
+	overhead.FillBytes(l1Info[4+32*6 : 4+32*7])
+	scalar.FillBytes(l1Info[4+32*7 : 4+32*8])
+
+	genesis := &core.Genesis{
+		Config:   &config,
+		GasLimit: 11500000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			addr: {Balance: big.NewInt(params.Ether)},
+			types.L1BlockAddr: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot: common.BigToHash(l1BaseFee),
+					types.OverheadSlot:  common.BigToHash(overhead),
+					types.ScalarSlot:    common.BigToHash(scalar),
+				},
+			},
+		},
+	}
+	signer := types.LatestSigner(genesis.Config)
+	stack := createNode(t)
+	defer stack.Close()
+
+	var deposit, tx *types.Transaction
+	handler, _ := newGQLService(t, stack, genesis, 1, func(i int, gen *core.BlockGen) {
+		gen.AddTx(types.NewTx(&types.DepositTx{To: &types.L1BlockAddr, Value: new(big.Int), Gas: 1_000_000, Data: l1Info}))
+		deposit = types.NewTx(&types.DepositTx{SourceHash: common.Hash{1}, From: dad, To: &dad, Mint: big.NewInt(100), Value: new(big.Int), Gas: 100000})
+		gen.AddTx(deposit)
+		tx, _ = types.SignNewTx(key, signer, &types.LegacyTx{To: &dad, Gas: 100000, GasPrice: big.NewInt(2 * params.InitialBaseFee)})
+		gen.AddTx(tx)
+	})
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: fmt.Sprintf(`{ transaction(hash: "%s") { deposit { sourceHash mint isSystemTx nonce } l1Fee } }`, deposit.Hash()),
+			want: `{"transaction":{"deposit":{"sourceHash":"0x0100000000000000000000000000000000000000000000000000000000000000","mint":"0x64","isSystemTx":false,"nonce":0},"l1Fee":null}}`,
+		},
+		{
+			body: fmt.Sprintf(`{ transaction(hash: "%s") { deposit { sourceHash } l1Fee l1GasUsed l1GasPrice } }`, tx.Hash()),
+			want: `{"transaction":{"deposit":null,"l1Fee":"0x35f480","l1GasUsed":"0x59c","l1GasPrice":"0x3e8"}}`,
+		},
+		// Without a share price, the balance of an automatic yield account
+		// is held entirely as remainder.
+		{
+			body: fmt.Sprintf(`{ block(number: 0) { account(address: "%s") { balance flags fixed shares remainder claimableAmount } } }`, addr),
+			want: `{"block":{"account":{"balance":"0xde0b6b3a7640000","flags":0,"fixed":"0x0","shares":"0x0","remainder":"0xde0b6b3a7640000","claimableAmount":"0x0"}}}`,
+		},
+	} {
+		res := handler.Schema.Exec(context.Background(), tt.body, "", map[string]interface{}{})
+		if res.Errors != nil {
+			t.Fatalf("failed to execute query for testcase #%d: %v", i, res.Errors)
+		}
+		have, err := json.Marshal(res.Data)
+		if err != nil {
+			t.Fatalf("failed to encode graphql response for testcase #%d: %s", i, err)
+		}
+		if string(have) != tt.want {
+			t.Errorf("response unmatch for testcase #%d.\nExpected:\n%s\nGot:\n%s\n", i, tt.want, have)
+		}
+	}
+}
+
 func createNode(t *testing.T) *node.Node {
 	stack, err := node.New(&node.Config{
 		HTTPHost:     "127.0.0.1",
@@ -397,8 +521,8 @@ func newGQLService(t *testing.T, stack *node.Node, gspec *core.Genesis, genBlock
 		t.Fatalf("could not create eth backend: %v", err)
 	}
 	// Create some blocks and import them
-	chain, _ := core.GenerateChain(params.AllEthashProtocolChanges, ethBackend.BlockChain().Genesis(),
-		ethash.NewFaker(), ethBackend.ChainDb(), genBlocks, genfunc)
+	chain, _ := core.GenerateChain(gspec.Config, ethBackend.BlockChain().Genesis(),
+		ethBackend.Engine(), ethBackend.ChainDb(), genBlocks, genfunc)
 	_, err = ethBackend.BlockChain().InsertChain(chain)
 	if err != nil {
 		t.Fatalf("could not create import blocks: %v", err)
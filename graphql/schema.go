@@ -52,6 +52,18 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # Flags is the yield mode of the account: 0 for automatic, 1 for
+        # disabled and 2 for claimable.
+        flags: Int!
+        # Fixed is the part of the balance held as a plain amount, in wei.
+        fixed: BigInt!
+        # Shares is the number of yield shares held by the account.
+        shares: BigInt!
+        # Remainder is the part of the balance held besides the shares, in wei.
+        remainder: BigInt!
+        # ClaimableAmount is the yield accrued by an account in claimable mode
+        # that has not been claimed yet, in wei. It is zero in the other modes.
+        claimableAmount: BigInt!
     }
 
     # Log is an Ethereum event log.
@@ -75,6 +87,20 @@ const schema string = `
         storageKeys : [Bytes32!]!
     }
 
+    # Deposit holds the fields specific to deposit transactions, which are
+    # derived from L1 and carry no signature.
+    type Deposit {
+        # SourceHash uniquely identifies the source of the deposit on L1.
+        sourceHash: Bytes32!
+        # Mint is the value, in wei, minted on L2 for the sender.
+        mint: BigInt
+        # IsSystemTx is true for deposits issued by the system rather than a user.
+        isSystemTx: Boolean!
+        # Nonce is the nonce the deposit was executed with. This will be null
+        # before Regolith or if the transaction has not yet been mined.
+        nonce: Long
+    }
+
     # Transaction is an Ethereum transaction.
     type Transaction {
         # Hash is the hash of this transaction.
@@ -147,6 +173,18 @@ const schema string = `
         # RawReceipt is the canonical encoding of the receipt. For post EIP-2718 typed transactions
         # this is equivalent to TxType || ReceiptEncoding.
         rawReceipt: Bytes!
+        # Deposit holds the fields of a deposit transaction. This will be null
+        # for other transactions.
+        deposit: Deposit
+        # L1Fee is the fee, in wei, charged for posting the transaction to L1.
+        # This will be null for deposits or if the transaction has not yet been mined.
+        l1Fee: BigInt
+        # L1GasUsed is the L1 gas the L1 fee was charged for. This will be null
+        # for deposits or if the transaction has not yet been mined.
+        l1GasUsed: BigInt
+        # L1GasPrice is the L1 base fee the L1 fee was priced at, in wei. This
+        # will be null for deposits or if the transaction has not yet been mined.
+        l1GasPrice: BigInt
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
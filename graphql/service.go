@@ -19,6 +19,7 @@ package graphql
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"sync"
@@ -103,8 +104,16 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// errAPIKeys is returned when enabling GraphQL on a node with API keys. GraphQL
+// is served next to the HTTP-RPC endpoint, but its queries aren't calls of RPC
+// methods, so the method policies of the keys can't be applied to them.
+var errAPIKeys = errors.New("GraphQL is not supported with API keys")
+
 // New constructs a new GraphQL service instance.
 func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) error {
+	if stack.Config().APIKeysFile != "" {
+		return errAPIKeys
+	}
 	_, err := newHandler(stack, backend, filterSystem, cors, vhosts)
 	return err
 }
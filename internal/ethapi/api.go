@@ -89,11 +89,16 @@ type feeHistoryResult struct {
 	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
 	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
 	GasUsedRatio []float64        `json:"gasUsedRatio"`
+
+	// L1 data fee parameters of each block, only reported on rollup chains
+	L1BaseFee  []*hexutil.Big `json:"l1BaseFee,omitempty"`
+	L1Overhead []*hexutil.Big `json:"l1Overhead,omitempty"`
+	L1Scalar   []*hexutil.Big `json:"l1Scalar,omitempty"`
 }
 
 // FeeHistory returns the fee market history.
 func (s *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
-	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, uint64(blockCount), lastBlock, rewardPercentiles)
+	oldest, reward, baseFee, gasUsed, l1History, err := s.b.FeeHistory(ctx, uint64(blockCount), lastBlock, rewardPercentiles)
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +121,16 @@ func (s *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDecim
 			results.BaseFee[i] = (*hexutil.Big)(v)
 		}
 	}
+	if l1History != nil {
+		results.L1BaseFee = make([]*hexutil.Big, len(l1History.L1BaseFee))
+		results.L1Overhead = make([]*hexutil.Big, len(l1History.Overhead))
+		results.L1Scalar = make([]*hexutil.Big, len(l1History.Scalar))
+		for i := range l1History.L1BaseFee {
+			results.L1BaseFee[i] = (*hexutil.Big)(l1History.L1BaseFee[i])
+			results.L1Overhead[i] = (*hexutil.Big)(l1History.Overhead[i])
+			results.L1Scalar[i] = (*hexutil.Big)(l1History.Scalar[i])
+		}
+	}
 	return results, nil
 }
 
@@ -640,16 +655,11 @@ func (s *BlockChainAPI) GetBalance(ctx context.Context, address common.Address,
 	}
 
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res hexutil.Big
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getBalance", address, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res hexutil.Big
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_getBalance", address, blockNrOrHash); err != nil {
+			return nil, err
 		}
+		return &res, nil
 	}
 
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -724,16 +734,11 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 		return nil, err
 	}
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res AccountResult
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getProof", address, storageKeys, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res AccountResult
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_getProof", address, storageKeys, blockNrOrHash); err != nil {
+			return nil, err
 		}
+		return &res, nil
 	}
 	var (
 		keys         = make([]common.Hash, len(storageKeys))
@@ -836,6 +841,10 @@ func decodeHash(s string) (h common.Hash, inputLength int, err error) {
 // * When blockNr is -1 the chain head is returned.
 // * When blockNr is -2 the pending chain head is returned.
 func (s *BlockChainAPI) GetHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if routed, err := historicalBlockCall(ctx, s.b, number, &res, "eth_getHeaderByNumber", number); routed {
+		return res, err
+	}
 	header, err := s.b.HeaderByNumber(ctx, number)
 	if header != nil && err == nil {
 		response := s.rpcMarshalHeader(ctx, header)
@@ -853,6 +862,15 @@ func (s *BlockChainAPI) GetHeaderByNumber(ctx context.Context, number rpc.BlockN
 // GetHeaderByHash returns the requested header by hash.
 func (s *BlockChainAPI) GetHeaderByHash(ctx context.Context, hash common.Hash) map[string]interface{} {
 	header, _ := s.b.HeaderByHash(ctx, hash)
+
+	var number *big.Int
+	if header != nil {
+		number = header.Number
+	}
+	var res map[string]interface{}
+	if routed, _ := historicalCall(ctx, s.b, number, &res, "eth_getHeaderByHash", hash); routed {
+		return res
+	}
 	if header != nil {
 		return s.rpcMarshalHeader(ctx, header)
 	}
@@ -865,6 +883,10 @@ func (s *BlockChainAPI) GetHeaderByHash(ctx context.Context, hash common.Hash) m
 //   - When fullTx is true all transactions in the block are returned, otherwise
 //     only the transaction hash is returned.
 func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if routed, err := historicalBlockCall(ctx, s.b, number, &res, "eth_getBlockByNumber", number, fullTx); routed {
+		return res, err
+	}
 	block, err := s.b.BlockByNumber(ctx, number)
 	if block != nil && err == nil {
 		response, err := s.rpcMarshalBlock(ctx, block, true, fullTx)
@@ -883,6 +905,11 @@ func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNu
 // detail, otherwise only the transaction hash is returned.
 func (s *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
 	block, err := s.b.BlockByHash(ctx, hash)
+
+	var res map[string]interface{}
+	if routed, err := historicalCall(ctx, s.b, blockNumberOf(block), &res, "eth_getBlockByHash", hash, fullTx); routed {
+		return res, err
+	}
 	if block != nil {
 		return s.rpcMarshalBlock(ctx, block, true, fullTx)
 	}
@@ -891,6 +918,10 @@ func (s *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fu
 
 // GetUncleByBlockNumberAndIndex returns the uncle block for the given block hash and index.
 func (s *BlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if routed, err := historicalBlockCall(ctx, s.b, blockNr, &res, "eth_getUncleByBlockNumberAndIndex", blockNr, index); routed {
+		return res, err
+	}
 	block, err := s.b.BlockByNumber(ctx, blockNr)
 	if block != nil {
 		uncles := block.Uncles()
@@ -907,6 +938,11 @@ func (s *BlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context, block
 // GetUncleByBlockHashAndIndex returns the uncle block for the given block hash and index.
 func (s *BlockChainAPI) GetUncleByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) (map[string]interface{}, error) {
 	block, err := s.b.BlockByHash(ctx, blockHash)
+
+	var res map[string]interface{}
+	if routed, err := historicalCall(ctx, s.b, blockNumberOf(block), &res, "eth_getUncleByBlockHashAndIndex", blockHash, index); routed {
+		return res, err
+	}
 	if block != nil {
 		uncles := block.Uncles()
 		if index >= hexutil.Uint(len(uncles)) {
@@ -921,6 +957,10 @@ func (s *BlockChainAPI) GetUncleByBlockHashAndIndex(ctx context.Context, blockHa
 
 // GetUncleCountByBlockNumber returns number of uncles in the block for the given block number
 func (s *BlockChainAPI) GetUncleCountByBlockNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
+	var res *hexutil.Uint
+	if routed, _ := historicalBlockCall(ctx, s.b, blockNr, &res, "eth_getUncleCountByBlockNumber", blockNr); routed {
+		return res
+	}
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
 		n := hexutil.Uint(len(block.Uncles()))
 		return &n
@@ -930,7 +970,13 @@ func (s *BlockChainAPI) GetUncleCountByBlockNumber(ctx context.Context, blockNr
 
 // GetUncleCountByBlockHash returns number of uncles in the block for the given block hash
 func (s *BlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint {
-	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
+	block, _ := s.b.BlockByHash(ctx, blockHash)
+
+	var res *hexutil.Uint
+	if routed, _ := historicalCall(ctx, s.b, blockNumberOf(block), &res, "eth_getUncleCountByBlockHash", blockHash); routed {
+		return res
+	}
+	if block != nil {
 		n := hexutil.Uint(len(block.Uncles()))
 		return &n
 	}
@@ -945,16 +991,11 @@ func (s *BlockChainAPI) GetCode(ctx context.Context, address common.Address, blo
 	}
 
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res hexutil.Bytes
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getCode", address, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res hexutil.Bytes
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_getCode", address, blockNrOrHash); err != nil {
+			return nil, err
 		}
+		return res, nil
 	}
 
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -976,16 +1017,11 @@ func (s *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Address
 	}
 
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res hexutil.Bytes
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getStorageAt", address, hexKey, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res hexutil.Bytes
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_getStorageAt", address, hexKey, blockNrOrHash); err != nil {
+			return nil, err
 		}
+		return res, nil
 	}
 
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -1013,6 +1049,42 @@ func headerByNumberOrHash(ctx context.Context, b Backend, blockNrOrHash rpc.Bloc
 	return header, err
 }
 
+// historicalCall routes a request about the block with the given number to the
+// historical backends if the block predates Bedrock. A nil number means that the
+// block or transaction the request is about is unknown locally, in which case
+// the historical backends are searched for it. It reports whether the request
+// was answered by the historical backends.
+func historicalCall(ctx context.Context, b Backend, number *big.Int, result interface{}, method string, args ...interface{}) (bool, error) {
+	router := b.HistoricalRouter()
+	if router == nil {
+		return false, nil
+	}
+	if number == nil {
+		return router.CallByHash(ctx, result, method, args...)
+	}
+	if !b.ChainConfig().IsPatexPreBedrock(number) {
+		return false, nil
+	}
+	return true, router.Call(ctx, result, number.Uint64(), method, args...)
+}
+
+// historicalBlockCall is historicalCall for requests addressing a block by its
+// number. The block tags besides earliest always refer to post-Bedrock blocks.
+func historicalBlockCall(ctx context.Context, b Backend, number rpc.BlockNumber, result interface{}, method string, args ...interface{}) (bool, error) {
+	if number < 0 {
+		return false, nil
+	}
+	return historicalCall(ctx, b, big.NewInt(number.Int64()), result, method, args...)
+}
+
+// blockNumberOf returns the number of the given block, or nil if it's unknown.
+func blockNumberOf(block *types.Block) *big.Int {
+	if block == nil {
+		return nil
+	}
+	return block.Number()
+}
+
 // OverrideAccount indicates the overriding fields of account during the execution
 // of a message call.
 // Note, state and stateDiff can't be specified at the same time. If state is
@@ -1206,16 +1278,11 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 	}
 
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res hexutil.Bytes
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_call", args, blockNrOrHash, overrides)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res hexutil.Bytes
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_call", args, blockNrOrHash, overrides); err != nil {
+			return nil, err
 		}
+		return res, nil
 	}
 
 	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
@@ -1267,7 +1334,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	}
 	// Recap the highest gas limit with account's available balance.
 	if feeCap.BitLen() != 0 {
-		state, _, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+		state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 		if err != nil {
 			return 0, err
 		}
@@ -1279,6 +1346,18 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 			}
 			available.Sub(available, args.Value.ToInt())
 		}
+		// The L1 data fee is charged on top of the L2 gas, so it is not available for gas either.
+		estimate, err := estimateL1Cost(ctx, b, state, header, &args, hi, suggestsL1BaseFee(blockNrOrHash))
+		if err != nil {
+			return 0, err
+		}
+		if estimate != nil && estimate.L1Fee.ToInt().Sign() > 0 {
+			l1Cost := estimate.L1Fee.ToInt()
+			if l1Cost.Cmp(available) >= 0 {
+				return 0, fmt.Errorf("%w: address %v have %v want L1 fee %v", core.ErrInsufficientFunds, args.From.Hex(), available, l1Cost)
+			}
+			available.Sub(available, l1Cost)
+		}
 		allowance := new(big.Int).Div(available, feeCap)
 
 		// If the allowance is larger than maximum uint64, skip checking
@@ -1363,21 +1442,104 @@ func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, b
 	}
 
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res hexutil.Uint64
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_estimateGas", args, blockNrOrHash)
-			if err != nil {
-				return 0, fmt.Errorf("historical backend error: %w", err)
-			}
-			return res, nil
-		} else {
-			return 0, rpc.ErrNoHistoricalFallback
+		var res hexutil.Uint64
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_estimateGas", args, blockNrOrHash); err != nil {
+			return 0, err
 		}
+		return res, nil
 	}
 
 	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
 }
 
+// L1FeeEstimate is the breakdown of the L1 data fee a transaction is charged,
+// using the same parameters the state transition reads from the L1Block predeploy.
+type L1FeeEstimate struct {
+	DataGas   hexutil.Uint64 `json:"dataGas"`
+	L1BaseFee *hexutil.Big   `json:"l1BaseFee"`
+	Overhead  *hexutil.Big   `json:"overhead"`
+	Scalar    *hexutil.Big   `json:"scalar"`
+	L1Fee     *hexutil.Big   `json:"l1Fee"`
+}
+
+// estimateL1Cost returns the breakdown of the L1 data fee the transaction described
+// by args would be charged on top of the given header. The gas limit is used for
+// the encoding of the transaction if args does not specify one. Nil is returned if
+// the chain does not charge an L1 fee.
+//
+// If suggest is set and the backend suggests an L1 base fee higher than the
+// current one, the fee is priced with the suggested value instead.
+func estimateL1Cost(ctx context.Context, b Backend, state *state.StateDB, header *types.Header, args *TransactionArgs, gas uint64, suggest bool) (*L1FeeEstimate, error) {
+	config := b.ChainConfig()
+	if config.Patex == nil {
+		return nil, nil
+	}
+	rollupDataGas, err := args.rollupDataGas(config.ChainID, state.GetNonce(args.from()), gas)
+	if err != nil {
+		return nil, err
+	}
+	// Price the data with the suggested L1 base fee if it exceeds the current one
+	l1BaseFee, overhead, scalar := types.ReadL1CostParams(state)
+	if suggest {
+		suggested, err := b.SuggestL1BaseFee(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if suggested != nil && suggested.Cmp(l1BaseFee) > 0 {
+			l1BaseFee = suggested
+		}
+	}
+	// Transactions without data gas are not charged, like in the state transition
+	l1Fee, dataGas := new(big.Int), rollupDataGas.DataGas(header.Time, config)
+	if dataGas != 0 {
+		l1Fee = types.L1Cost(dataGas, l1BaseFee, overhead, scalar)
+	}
+	return &L1FeeEstimate{
+		DataGas:   hexutil.Uint64(dataGas),
+		L1BaseFee: (*hexutil.Big)(l1BaseFee),
+		Overhead:  (*hexutil.Big)(overhead),
+		Scalar:    (*hexutil.Big)(scalar),
+		L1Fee:     (*hexutil.Big)(l1Fee),
+	}, nil
+}
+
+// suggestsL1BaseFee reports whether the L1 data fee of transactions on top of the
+// given block is priced with the suggested L1 base fee. Only estimates for the
+// upcoming blocks are, historical ones are priced like consensus charged them.
+func suggestsL1BaseFee(blockNrOrHash rpc.BlockNumberOrHash) bool {
+	number, ok := blockNrOrHash.Number()
+	return ok && (number == rpc.PendingBlockNumber || number == rpc.LatestBlockNumber)
+}
+
+// EstimateL1Fee returns the breakdown of the L1 data fee the given unsigned transaction
+// would be charged if it were included on top of the given block, the pending block by
+// default: its rollup data gas, the L1 base fee, overhead and scalar it is priced with,
+// and the resulting fee. The transaction is assumed to carry a signature of maximal size.
+// If the gas price oracle is L1-aware, the suggested L1 base fee is used when it exceeds
+// the current one, for estimates on top of the pending or latest block only.
+func (s *BlockChainAPI) EstimateL1Fee(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*L1FeeEstimate, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	config := s.b.ChainConfig()
+	if config.Patex == nil {
+		return nil, errors.New("chain does not charge an L1 data fee")
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if config.IsPatexPreBedrock(header.Number) {
+		return nil, errors.New("L1 fee estimation is not supported for pre-bedrock blocks")
+	}
+	estimate, err := estimateL1Cost(ctx, s.b, state, header, &args, header.GasLimit, suggestsL1BaseFee(bNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	return estimate, state.Error()
+}
+
 // RPCMarshalHeader converts the given header to the RPC output .
 func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 	result := map[string]interface{}{
@@ -1655,16 +1817,11 @@ func (s *BlockChainAPI) CreateAccessList(ctx context.Context, args TransactionAr
 
 	header, err := headerByNumberOrHash(ctx, s.b, bNrOrHash)
 	if err == nil && header != nil && s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res accessListResult
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_createAccessList", args, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res accessListResult
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_createAccessList", args, blockNrOrHash); err != nil {
+			return nil, err
 		}
+		return &res, nil
 	}
 
 	acl, gasUsed, vmerr, err := AccessList(ctx, s.b, bNrOrHash, args)
@@ -1761,6 +1918,10 @@ func NewTransactionAPI(b Backend, nonceLock *AddrLocker) *TransactionAPI {
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
 func (s *TransactionAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
+	var res *hexutil.Uint
+	if routed, _ := historicalBlockCall(ctx, s.b, blockNr, &res, "eth_getBlockTransactionCountByNumber", blockNr); routed {
+		return res
+	}
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
 		n := hexutil.Uint(len(block.Transactions()))
 		return &n
@@ -1770,7 +1931,13 @@ func (s *TransactionAPI) GetBlockTransactionCountByNumber(ctx context.Context, b
 
 // GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
 func (s *TransactionAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint {
-	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
+	block, _ := s.b.BlockByHash(ctx, blockHash)
+
+	var res *hexutil.Uint
+	if routed, _ := historicalCall(ctx, s.b, blockNumberOf(block), &res, "eth_getBlockTransactionCountByHash", blockHash); routed {
+		return res
+	}
+	if block != nil {
 		n := hexutil.Uint(len(block.Transactions()))
 		return &n
 	}
@@ -1779,6 +1946,10 @@ func (s *TransactionAPI) GetBlockTransactionCountByHash(ctx context.Context, blo
 
 // GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
 func (s *TransactionAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
+	var res *RPCTransaction
+	if routed, _ := historicalBlockCall(ctx, s.b, blockNr, &res, "eth_getTransactionByBlockNumberAndIndex", blockNr, index); routed {
+		return res
+	}
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
 		return newRPCTransactionFromBlockIndex(ctx, block, uint64(index), s.b)
 	}
@@ -1787,7 +1958,13 @@ func (s *TransactionAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context
 
 // GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
 func (s *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) *RPCTransaction {
-	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
+	block, _ := s.b.BlockByHash(ctx, blockHash)
+
+	var res *RPCTransaction
+	if routed, _ := historicalCall(ctx, s.b, blockNumberOf(block), &res, "eth_getTransactionByBlockHashAndIndex", blockHash, index); routed {
+		return res
+	}
+	if block != nil {
 		return newRPCTransactionFromBlockIndex(ctx, block, uint64(index), s.b)
 	}
 	return nil
@@ -1795,6 +1972,10 @@ func (s *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context,
 
 // GetRawTransactionByBlockNumberAndIndex returns the bytes of the transaction for the given block number and index.
 func (s *TransactionAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) hexutil.Bytes {
+	var res hexutil.Bytes
+	if routed, _ := historicalBlockCall(ctx, s.b, blockNr, &res, "eth_getRawTransactionByBlockNumberAndIndex", blockNr, index); routed {
+		return res
+	}
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
 		return newRPCRawTransactionFromBlockIndex(block, uint64(index))
 	}
@@ -1803,7 +1984,13 @@ func (s *TransactionAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Cont
 
 // GetRawTransactionByBlockHashAndIndex returns the bytes of the transaction for the given block hash and index.
 func (s *TransactionAPI) GetRawTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) hexutil.Bytes {
-	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
+	block, _ := s.b.BlockByHash(ctx, blockHash)
+
+	var res hexutil.Bytes
+	if routed, _ := historicalCall(ctx, s.b, blockNumberOf(block), &res, "eth_getRawTransactionByBlockHashAndIndex", blockHash, index); routed {
+		return res
+	}
+	if block != nil {
 		return newRPCRawTransactionFromBlockIndex(block, uint64(index))
 	}
 	return nil
@@ -1826,16 +2013,11 @@ func (s *TransactionAPI) GetTransactionCount(ctx context.Context, address common
 	}
 
 	if s.b.ChainConfig().IsPatexPreBedrock(header.Number) {
-		if s.b.HistoricalRPCService() != nil {
-			var res hexutil.Uint64
-			err := s.b.HistoricalRPCService().CallContext(ctx, &res, "eth_getTransactionCount", address, blockNrOrHash)
-			if err != nil {
-				return nil, fmt.Errorf("historical backend error: %w", err)
-			}
-			return &res, nil
-		} else {
-			return nil, rpc.ErrNoHistoricalFallback
+		var res hexutil.Uint64
+		if err := s.b.HistoricalRouter().Call(ctx, &res, header.Number.Uint64(), "eth_getTransactionCount", address, blockNrOrHash); err != nil {
+			return nil, err
 		}
+		return &res, nil
 	}
 
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -1855,6 +2037,10 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 		return nil, err
 	}
 	if tx != nil {
+		var res *RPCTransaction
+		if routed, err := historicalCall(ctx, s.b, new(big.Int).SetUint64(blockNumber), &res, "eth_getTransactionByHash", hash); routed {
+			return res, err
+		}
 		header, err := s.b.HeaderByHash(ctx, blockHash)
 		if err != nil {
 			return nil, err
@@ -1866,23 +2052,33 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
 		return NewRPCPendingTransaction(tx, s.b.CurrentHeader(), s.b.ChainConfig()), nil
 	}
-
-	// Transaction unknown, return as such
+	// Transaction unknown, it may be a legacy transaction of the historical backends
+	var res *RPCTransaction
+	if found, err := historicalCall(ctx, s.b, nil, &res, "eth_getTransactionByHash", hash); found || err != nil {
+		return res, err
+	}
 	return nil, nil
 }
 
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
 func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	// Retrieve a finalized transaction, or a pooled otherwise
-	tx, _, _, _, err := s.b.GetTransaction(ctx, hash)
+	tx, _, blockNumber, _, err := s.b.GetTransaction(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
-	if tx == nil {
-		if tx = s.b.GetPoolTransaction(hash); tx == nil {
-			// Transaction not found anywhere, abort
-			return nil, nil
+	if tx != nil {
+		var res hexutil.Bytes
+		if routed, err := historicalCall(ctx, s.b, new(big.Int).SetUint64(blockNumber), &res, "eth_getRawTransactionByHash", hash); routed {
+			return res, err
+		}
+	} else if tx = s.b.GetPoolTransaction(hash); tx == nil {
+		// Transaction not found locally, try the historical backends
+		var res hexutil.Bytes
+		if _, err := historicalCall(ctx, s.b, nil, &res, "eth_getRawTransactionByHash", hash); err != nil {
+			return nil, err
 		}
+		return res, nil
 	}
 	// Serialize to RLP and return
 	return tx.MarshalBinary()
@@ -1896,6 +2092,20 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.
 		// as per specification.
 		return nil, nil
 	}
+	var number *big.Int
+	if tx != nil {
+		number = new(big.Int).SetUint64(blockNumber)
+	} else if s.b.GetPoolTransaction(hash) != nil {
+		// Pending transactions have no receipt yet, nor are they legacy ones
+		return nil, nil
+	}
+	var res map[string]interface{}
+	if routed, err := historicalCall(ctx, s.b, number, &res, "eth_getTransactionReceipt", hash); routed || err != nil {
+		return res, err
+	}
+	if tx == nil {
+		return nil, nil
+	}
 
 	receipts, err := s.b.GetReceipts(ctx, blockHash)
 	if err != nil {
@@ -2056,6 +2266,47 @@ func (s *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil.B
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// conditionalFailedError is an API error returned if the conditional of a
+// transaction is not met by the latest block.
+type conditionalFailedError struct {
+	error
+}
+
+// ErrorCode returns the JSON error code for an unmet conditional.
+func (e *conditionalFailedError) ErrorCode() int {
+	return -32003
+}
+
+// SendRawTransactionConditional will add the signed transaction to the transaction
+// pool, to be included only in a block meeting the given conditional. The
+// transaction is dropped from the pool once the conditional can no longer be met.
+// As checking conditionals reads state, nodes have to opt in to serving them.
+func (s *TransactionAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	if !s.b.ConditionalTxsAllowed() {
+		return common.Hash{}, errors.New("conditional transactions are disabled")
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, err
+	}
+	// Reject the transaction early if the latest block already rules it out
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	if cond.Expired(header) {
+		return common.Hash{}, &conditionalFailedError{errors.New("block range expired")}
+	}
+	if err := state.CheckKnownAccounts(cond.KnownAccounts); err != nil {
+		return common.Hash{}, &conditionalFailedError{err}
+	}
+	tx.SetConditional(&cond)
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
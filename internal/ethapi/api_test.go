@@ -17,15 +17,23 @@
 package ethapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -274,3 +282,176 @@ func allTransactionTypes(addr common.Address, config *params.ChainConfig) []type
 		},
 	}
 }
+
+// testBackend is a backend serving the state of a local chain, with the other
+// methods of the Backend interface mocked.
+type testBackend struct {
+	*backendMock
+	chain     *core.BlockChain
+	l1BaseFee *big.Int // Suggested L1 base fee
+}
+
+func newTestBackend(t *testing.T, genesis *core.Genesis) *testBackend {
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	return &testBackend{backendMock: newBackendMock(), chain: chain}
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
+func (b *testBackend) CurrentHeader() *types.Header     { return b.chain.CurrentHeader() }
+func (b *testBackend) CurrentBlock() *types.Header      { return b.chain.CurrentBlock() }
+func (b *testBackend) RPCGasCap() uint64                { return 50_000_000 }
+func (b *testBackend) SuggestL1BaseFee(ctx context.Context) (*big.Int, error) {
+	return b.l1BaseFee, nil
+}
+func (b *testBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		return b.chain.GetHeaderByHash(hash), nil
+	}
+	number, _ := blockNrOrHash.Number()
+	if number < 0 {
+		return b.chain.CurrentHeader(), nil // Latest and pending
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+func (b *testBackend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
+	header, _ := b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil {
+		return nil, nil
+	}
+	return b.chain.GetBlock(header.Hash(), header.Number.Uint64()), nil
+}
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	header, _ := b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+func (b *testBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	context := core.NewEVMBlockContext(header, b.chain, nil, b.chain.Config(), state)
+	return vm.NewEVM(context, core.NewEVMTxContext(msg), state, b.chain.Config(), *vmConfig), state.Error, nil
+}
+
+// newPatexTestBackend creates a backend of a Patex chain charging an L1 data fee,
+// with the given account funded.
+func newPatexTestBackend(t *testing.T, account common.Address, balance *big.Int) *testBackend {
+	config := *params.TestChainConfig
+	config.BedrockBlock = big.NewInt(0)
+	config.Patex = &params.PatexConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}
+
+	return newTestBackend(t, &core.Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			account: {Balance: balance},
+			types.L1BlockAddr: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					types.L1BaseFeeSlot: common.BigToHash(big.NewInt(params.GWei)),
+					types.OverheadSlot:  common.BigToHash(big.NewInt(2100)),
+					types.ScalarSlot:    common.BigToHash(big.NewInt(1_000_000)),
+				},
+			},
+		},
+	})
+}
+
+func TestEstimateL1Fee(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		from    = common.HexToAddress("0x1000")
+		to      = common.HexToAddress("0x2000")
+		backend = newPatexTestBackend(t, from, big.NewInt(params.Ether))
+		api     = NewBlockChainAPI(backend)
+		data    = hexutil.Bytes(common.FromHex("0x00000000deadbeef"))
+		args    = TransactionArgs{From: &from, To: &to, Data: &data}
+	)
+	estimate, err := api.EstimateL1Fee(ctx, args, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	if estimate.L1BaseFee.ToInt().Int64() != params.GWei || estimate.Overhead.ToInt().Int64() != 2100 || estimate.Scalar.ToInt().Int64() != 1_000_000 {
+		t.Errorf("L1 fee parameters mismatch: have %v/%v/%v", estimate.L1BaseFee, estimate.Overhead, estimate.Scalar)
+	}
+	if estimate.DataGas == 0 {
+		t.Error("no data gas estimated")
+	}
+	want := types.L1Cost(uint64(estimate.DataGas), big.NewInt(params.GWei), big.NewInt(2100), big.NewInt(1_000_000))
+	if estimate.L1Fee.ToInt().Cmp(want) != 0 {
+		t.Errorf("L1 fee mismatch: have %v, want %v", estimate.L1Fee, want)
+	}
+	// A higher suggested L1 base fee is used for the estimate, a lower one is not.
+	backend.l1BaseFee = big.NewInt(3 * params.GWei)
+	if estimate, err = api.EstimateL1Fee(ctx, args, nil); err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	want = types.L1Cost(uint64(estimate.DataGas), big.NewInt(3*params.GWei), big.NewInt(2100), big.NewInt(1_000_000))
+	if estimate.L1BaseFee.ToInt().Cmp(backend.l1BaseFee) != 0 || estimate.L1Fee.ToInt().Cmp(want) != 0 {
+		t.Errorf("L1 fee mismatch with suggested base fee: have %v at %v, want %v", estimate.L1Fee, estimate.L1BaseFee, want)
+	}
+	// Estimates at an explicit block are priced like consensus charged them.
+	number := rpc.BlockNumberOrHashWithNumber(0)
+	if estimate, err = api.EstimateL1Fee(ctx, args, &number); err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	if estimate.L1BaseFee.ToInt().Int64() != params.GWei {
+		t.Errorf("L1 base fee mismatch at explicit block: have %v, want %v", estimate.L1BaseFee, params.GWei)
+	}
+	backend.l1BaseFee = big.NewInt(1)
+	if estimate, err = api.EstimateL1Fee(ctx, args, nil); err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	if estimate.L1BaseFee.ToInt().Int64() != params.GWei {
+		t.Errorf("L1 base fee mismatch with lower suggestion: have %v, want %v", estimate.L1BaseFee, params.GWei)
+	}
+}
+
+func TestEstimateGasL1Fee(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		from     = common.HexToAddress("0x1000")
+		to       = common.HexToAddress("0x2000")
+		gasPrice = big.NewInt(params.GWei)
+		args     = TransactionArgs{From: &from, To: &to, GasPrice: (*hexutil.Big)(gasPrice)}
+	)
+	// Determine the L1 fee of a transfer, which does not depend on the balance.
+	estimate, err := NewBlockChainAPI(newPatexTestBackend(t, from, big.NewInt(params.Ether))).EstimateL1Fee(ctx, args, nil)
+	if err != nil {
+		t.Fatalf("failed to estimate L1 fee: %v", err)
+	}
+	l1Fee := estimate.L1Fee.ToInt()
+	if l1Fee.Sign() == 0 {
+		t.Fatal("no L1 fee estimated")
+	}
+	transfer := new(big.Int).Mul(gasPrice, big.NewInt(int64(params.TxGas)))
+
+	tests := []struct {
+		balance *big.Int
+		want    hexutil.Uint64
+		err     error
+	}{
+		// Enough to pay for both the gas and the L1 fee.
+		{new(big.Int).Add(transfer, l1Fee), hexutil.Uint64(params.TxGas), nil},
+		// The L2 gas can no longer be paid once the L1 fee is deducted.
+		{new(big.Int).Sub(new(big.Int).Add(transfer, l1Fee), common.Big1), 0, errors.New("gas required exceeds allowance (20999)")},
+		// Not even the L1 fee can be paid.
+		{l1Fee, 0, core.ErrInsufficientFunds},
+	}
+	for i, tt := range tests {
+		api := NewBlockChainAPI(newPatexTestBackend(t, from, tt.balance))
+		have, err := api.EstimateGas(ctx, args, nil)
+		switch {
+		case tt.err == nil && err != nil:
+			t.Errorf("test %d: failed to estimate gas: %v", i, err)
+		case tt.err != nil && (err == nil || !errors.Is(err, tt.err) && err.Error() != tt.err.Error()):
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		case have != tt.want:
+			t.Errorf("test %d: gas mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
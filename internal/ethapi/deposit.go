@@ -0,0 +1,108 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// RPCDeposit represents an included deposit transaction together with the
+// outcome of its execution, as far as the receipt is available.
+type RPCDeposit struct {
+	SourceHash       common.Hash     `json:"sourceHash"`
+	TransactionHash  common.Hash     `json:"transactionHash"`
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	From             common.Address  `json:"from"`
+	To               *common.Address `json:"to"`
+	Mint             *hexutil.Big    `json:"mint"`
+	Value            *hexutil.Big    `json:"value"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	IsSystemTx       bool            `json:"isSystemTx"`
+	Status           *hexutil.Uint64 `json:"status,omitempty"`
+	DepositNonce     *hexutil.Uint64 `json:"depositNonce,omitempty"`
+}
+
+// NewRPCDeposit returns the RPC representation of a deposit transaction. The
+// receipt is optional.
+func NewRPCDeposit(tx *types.Transaction, receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, index uint64, config *params.ChainConfig) *RPCDeposit {
+	signer := types.MakeSigner(config, new(big.Int).SetUint64(blockNumber))
+	from, _ := types.Sender(signer, tx)
+
+	mint := new(big.Int)
+	if tx.Mint() != nil {
+		mint.Set(tx.Mint())
+	}
+	result := &RPCDeposit{
+		SourceHash:       tx.SourceHash(),
+		TransactionHash:  tx.Hash(),
+		BlockHash:        blockHash,
+		BlockNumber:      hexutil.Uint64(blockNumber),
+		TransactionIndex: hexutil.Uint64(index),
+		From:             from,
+		To:               tx.To(),
+		Mint:             (*hexutil.Big)(mint),
+		Value:            (*hexutil.Big)(tx.Value()),
+		Gas:              hexutil.Uint64(tx.Gas()),
+		IsSystemTx:       tx.IsSystemTx(),
+	}
+	if receipt != nil {
+		status := hexutil.Uint64(receipt.Status)
+		result.Status = &status
+		result.DepositNonce = (*hexutil.Uint64)(receipt.DepositNonce)
+	}
+	return result
+}
+
+// RPCBlockDeposits lists the deposits of a block and the value they minted.
+type RPCBlockDeposits struct {
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Deposits    []*RPCDeposit  `json:"deposits"`
+	TotalMint   *hexutil.Big   `json:"totalMint"`
+}
+
+// NewRPCBlockDeposits returns the deposits of the given block. The receipts are
+// optional, but must match the block's transactions if given.
+func NewRPCBlockDeposits(block *types.Block, receipts types.Receipts, config *params.ChainConfig) *RPCBlockDeposits {
+	result := &RPCBlockDeposits{
+		BlockHash:   block.Hash(),
+		BlockNumber: hexutil.Uint64(block.NumberU64()),
+		Deposits:    []*RPCDeposit{},
+	}
+	total := new(big.Int)
+	for i, tx := range block.Transactions() {
+		if !tx.IsDepositTx() {
+			continue
+		}
+		var receipt *types.Receipt
+		if i < len(receipts) {
+			receipt = receipts[i]
+		}
+		deposit := NewRPCDeposit(tx, receipt, block.Hash(), block.NumberU64(), uint64(i), config)
+		total.Add(total, deposit.Mint.ToInt())
+		result.Deposits = append(result.Deposits, deposit)
+	}
+	result.TotalMint = (*hexutil.Big)(total)
+	return result
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -329,3 +330,31 @@ func (args *TransactionArgs) toTransaction() *types.Transaction {
 func (args *TransactionArgs) ToTransaction() *types.Transaction {
 	return args.toTransaction()
 }
+
+// rollupDataGas returns the rollup data gas of the transaction described by the
+// arguments once it is signed. Missing nonce and gas fields are substituted with
+// the given values, missing fee fields are encoded as zero, and the signature is
+// assumed to be of maximal encoded size, so the result is an upper bound.
+func (args *TransactionArgs) rollupDataGas(chainID *big.Int, nonce uint64, gas uint64) (types.RollupGasData, error) {
+	filled := *args
+	filled.ChainID = (*hexutil.Big)(chainID)
+	if filled.Nonce == nil {
+		filled.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if filled.Gas == nil {
+		filled.Gas = (*hexutil.Uint64)(&gas)
+	}
+	if filled.GasPrice == nil && filled.MaxFeePerGas == nil {
+		filled.MaxFeePerGas = new(hexutil.Big)
+	}
+	if filled.MaxFeePerGas != nil && filled.MaxPriorityFeePerGas == nil {
+		filled.MaxPriorityFeePerGas = new(hexutil.Big)
+	}
+	sig := bytes.Repeat([]byte{0xff}, crypto.SignatureLength)
+	sig[crypto.RecoveryIDOffset] = 1
+	tx, err := filled.toTransaction().WithSignature(types.LatestSignerForChainID(chainID), sig)
+	if err != nil {
+		return types.RollupGasData{}, err
+	}
+	return tx.RollupDataGas(), nil
+}
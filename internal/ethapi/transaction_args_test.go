@@ -31,11 +31,14 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -207,6 +210,43 @@ func TestSetFeeDefaults(t *testing.T) {
 	}
 }
 
+// TestRollupDataGas tests that the rollup data gas estimated for unsigned
+// arguments bounds the rollup data gas of the signed transaction.
+func TestRollupDataGas(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		chainID = big.NewInt(789)
+		to      = common.HexToAddress("0x1234")
+		signer  = types.LatestSignerForChainID(chainID)
+	)
+	txs := []types.TxData{
+		&types.DynamicFeeTx{ChainID: chainID, Nonce: 7, To: &to, Gas: 50000, GasFeeCap: big.NewInt(2e9), GasTipCap: big.NewInt(1e9), Value: big.NewInt(1), Data: []byte{0, 1, 2, 3}},
+		&types.LegacyTx{Nonce: 7, To: &to, Gas: 50000, GasPrice: big.NewInt(2e9), Value: big.NewInt(1), Data: []byte{0, 1, 2, 3}},
+	}
+	for i, inner := range txs {
+		tx := types.MustSignNewTx(key, signer, inner)
+		args := TransactionArgs{
+			To:    tx.To(),
+			Value: (*hexutil.Big)(tx.Value()),
+			Data:  (*hexutil.Bytes)(&[]byte{0, 1, 2, 3}),
+		}
+		if tx.Type() == types.LegacyTxType {
+			args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		} else {
+			args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		}
+		have, err := args.rollupDataGas(chainID, tx.Nonce(), tx.Gas())
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		want := tx.RollupDataGas()
+		if have.Zeroes+have.Ones < want.Zeroes+want.Ones || have.Ones < want.Ones {
+			t.Errorf("test %d: estimate below signed transaction: have %+v, want %+v", i, have, want)
+		}
+	}
+}
+
 type backendMock struct {
 	current *types.Header
 	config  *params.ChainConfig
@@ -257,9 +297,10 @@ func (b *backendMock) CurrentHeader() *types.Header     { return b.current }
 func (b *backendMock) ChainConfig() *params.ChainConfig { return b.config }
 
 // Other methods needed to implement Backend interface.
-func (b *backendMock) SyncProgress() ethereum.SyncProgress { return ethereum.SyncProgress{} }
-func (b *backendMock) FeeHistory(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
-	return nil, nil, nil, nil, nil
+func (b *backendMock) SyncProgress() ethereum.SyncProgress                    { return ethereum.SyncProgress{} }
+func (b *backendMock) SuggestL1BaseFee(ctx context.Context) (*big.Int, error) { return nil, nil }
+func (b *backendMock) FeeHistory(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, *types.L1FeeHistory, error) {
+	return nil, nil, nil, nil, nil, nil
 }
 func (b *backendMock) ChainDb() ethdb.Database           { return nil }
 func (b *backendMock) AccountManager() *accounts.Manager { return nil }
@@ -268,6 +309,7 @@ func (b *backendMock) RPCGasCap() uint64                 { return 0 }
 func (b *backendMock) RPCEVMTimeout() time.Duration      { return time.Second }
 func (b *backendMock) RPCTxFeeCap() float64              { return 0 }
 func (b *backendMock) UnprotectedAllowed() bool          { return false }
+func (b *backendMock) ConditionalTxsAllowed() bool       { return false }
 func (b *backendMock) SetHead(number uint64)             {}
 func (b *backendMock) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
 	return nil, nil
@@ -342,6 +384,7 @@ func (b *backendMock) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent)
 	return nil
 }
 
-func (b *backendMock) Engine() consensus.Engine          { return nil }
-func (b *backendMock) HistoricalRPCService() *rpc.Client { return nil }
-func (b *backendMock) Genesis() *types.Block             { return nil }
+func (b *backendMock) Engine() consensus.Engine             { return nil }
+func (b *backendMock) HistoricalRouter() *historical.Router { return nil }
+func (b *backendMock) LogIndex() *logindex.Indexer          { return nil }
+func (b *backendMock) Genesis() *types.Block                { return nil }
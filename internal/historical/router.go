@@ -0,0 +1,282 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package historical routes RPC requests about pre-Bedrock blocks to legacy
+// nodes serving the chain history from before the Bedrock upgrade.
+package historical
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultCacheSize is the default size of the responses kept by a router, in
+// megabytes.
+const DefaultCacheSize = 16
+
+// missingCacheSize is the number of hash lookups unknown to all endpoints that
+// are remembered by a router.
+const missingCacheSize = 65536
+
+// Endpoint is a legacy node serving the blocks in the range [From, To). A To of
+// zero means the range is open ended, in which case the router only consults
+// the endpoint for pre-Bedrock blocks anyway.
+type Endpoint struct {
+	URL  string
+	From uint64
+	To   uint64
+}
+
+// ParseEndpoints parses a comma separated list of endpoints. Every entry is
+// either a bare URL, serving all pre-Bedrock blocks, or a URL prefixed with the
+// block range it serves as in "<from>-<to>@<url>", where either bound may be
+// omitted.
+func ParseEndpoints(input string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	for _, entry := range strings.Split(input, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		endpoint := Endpoint{URL: entry}
+		if at := strings.Index(entry, "@"); at > 0 {
+			if from, to, ok := strings.Cut(entry[:at], "-"); ok && isNumberOrEmpty(from) && isNumberOrEmpty(to) {
+				endpoint.URL = entry[at+1:]
+				endpoint.From, _ = strconv.ParseUint(from, 10, 64)
+				endpoint.To, _ = strconv.ParseUint(to, 10, 64)
+				if endpoint.To != 0 && endpoint.To <= endpoint.From {
+					return nil, fmt.Errorf("invalid block range %q of historical endpoint", entry[:at])
+				}
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+func isNumberOrEmpty(s string) bool {
+	if s == "" {
+		return true
+	}
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+// route is a connected endpoint.
+type route struct {
+	from, to uint64 // Served block range, to is exclusive
+	client   *rpc.Client
+}
+
+func (r *route) contains(number uint64) bool {
+	return number >= r.from && number < r.to
+}
+
+// Router routes requests about pre-Bedrock blocks to the endpoint serving the
+// block and caches the responses. As the history before Bedrock is immutable,
+// responses never go stale, including the absence of a hash from all endpoints.
+// The absent hashes are remembered separately from the responses, so lookups of
+// random hashes can't evict the cached responses.
+//
+// All methods are safe to call on a nil router, which behaves as if no endpoint
+// was configured.
+type Router struct {
+	routes  []*route // Connected endpoints, sorted by block range
+	cache   *lru.SizeConstrainedCache[common.Hash, json.RawMessage]
+	missing *lru.Cache[common.Hash, struct{}] // Hash lookups unknown to all endpoints
+}
+
+// NewRouter dials the given endpoints. The block ranges of the endpoints must
+// not overlap. The cache size is given in megabytes.
+func NewRouter(endpoints []Endpoint, dialTimeout time.Duration, cacheSize int) (*Router, error) {
+	if cacheSize <= 0 {
+		cacheSize = DefaultCacheSize
+	}
+	router := &Router{
+		cache:   lru.NewSizeConstrainedCache[common.Hash, json.RawMessage](uint64(cacheSize) * 1024 * 1024),
+		missing: lru.NewCache[common.Hash, struct{}](missingCacheSize),
+	}
+	for _, endpoint := range endpoints {
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		client, err := rpc.DialContext(ctx, endpoint.URL)
+		cancel()
+		if err != nil {
+			router.Close()
+			return nil, err
+		}
+		to := endpoint.To
+		if to == 0 {
+			to = math.MaxUint64
+		}
+		router.routes = append(router.routes, &route{from: endpoint.From, to: to, client: client})
+	}
+	sort.Slice(router.routes, func(i, j int) bool { return router.routes[i].from < router.routes[j].from })
+	for i := 1; i < len(router.routes); i++ {
+		if router.routes[i].from < router.routes[i-1].to {
+			router.Close()
+			return nil, errors.New("block ranges of historical endpoints overlap")
+		}
+	}
+	return router, nil
+}
+
+// Close disconnects from all endpoints.
+func (r *Router) Close() {
+	if r == nil {
+		return
+	}
+	for _, route := range r.routes {
+		route.client.Close()
+	}
+}
+
+// Call performs a request about the given block on the endpoint serving it.
+func (r *Router) Call(ctx context.Context, result interface{}, number uint64, method string, args ...interface{}) error {
+	if r == nil {
+		return rpc.ErrNoHistoricalFallback
+	}
+	for _, route := range r.routes {
+		if route.contains(number) {
+			_, err := r.call(ctx, route, result, method, args...)
+			return err
+		}
+	}
+	return rpc.ErrNoHistoricalFallback
+}
+
+// CallByHash performs a request about a block or transaction only known by its
+// hash. The endpoints are asked newest range first until one of them returns a
+// non-null result. It reports whether any endpoint knew the hash. Hashes known
+// to none of the endpoints are remembered, so that repeated requests about
+// post-Bedrock hashes are not sent to the endpoints again. Callers should only
+// look up hashes unknown to the local chain and transaction pool.
+func (r *Router) CallByHash(ctx context.Context, result interface{}, method string, args ...interface{}) (bool, error) {
+	if r == nil {
+		return false, rpc.ErrNoHistoricalFallback
+	}
+	key, err := cacheKey(method, args)
+	if err != nil {
+		return false, err
+	}
+	if r.missing.Contains(key) {
+		return false, nil
+	}
+	for i := len(r.routes) - 1; i >= 0; i-- {
+		found, err := r.call(ctx, r.routes[i], result, method, args...)
+		if found || err != nil {
+			return found, err
+		}
+	}
+	r.missing.Add(key, struct{}{})
+	return false, nil
+}
+
+// Logs returns the logs matching the given criteria in the inclusive block
+// range [from, to], stitched together from all endpoints serving a part of it.
+func (r *Router) Logs(ctx context.Context, from, to uint64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	if r == nil {
+		return nil, rpc.ErrNoHistoricalFallback
+	}
+	var (
+		logs = []*types.Log{}
+		next = from
+	)
+	for _, route := range r.routes {
+		if next > to {
+			break
+		}
+		if !route.contains(next) {
+			if route.from > next {
+				break
+			}
+			continue
+		}
+		end := to
+		if route.to-1 < end {
+			end = route.to - 1
+		}
+		filter := map[string]interface{}{
+			"fromBlock": hexutil.Uint64(next),
+			"toBlock":   hexutil.Uint64(end),
+			"address":   addresses,
+			"topics":    topics,
+		}
+		var segment []*types.Log
+		if _, err := r.call(ctx, route, &segment, "eth_getLogs", filter); err != nil {
+			return nil, err
+		}
+		logs = append(logs, segment...)
+		next = end + 1
+	}
+	if next <= to {
+		return nil, fmt.Errorf("no historical endpoint serves block %d", next)
+	}
+	return logs, nil
+}
+
+// call performs a request on the given endpoint, answering it from the cache if
+// possible. It reports whether the result was non-null.
+func (r *Router) call(ctx context.Context, route *route, result interface{}, method string, args ...interface{}) (bool, error) {
+	key, err := cacheKey(method, args)
+	if err != nil {
+		return false, err
+	}
+	raw, ok := r.cache.Get(key)
+	if !ok {
+		if err := route.client.CallContext(ctx, &raw, method, args...); err != nil {
+			return false, fmt.Errorf("historical backend error: %w", err)
+		}
+		if isNull(raw) {
+			return false, nil
+		}
+		r.cache.Add(key, raw)
+	} else {
+		log.Trace("Served historical request from cache", "method", method)
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return false, fmt.Errorf("historical backend error: %w", err)
+	}
+	return true, nil
+}
+
+// cacheKey returns the key of the response to a request.
+func cacheKey(method string, args []interface{}) (common.Hash, error) {
+	blob, err := json.Marshal(args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte(method), blob), nil
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ratelimit implements rate limiting of many clients, such as the remote
+// hosts or the transaction senders submitting requests.
+package ratelimit
+
+import (
+	"net"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"golang.org/x/time/rate"
+)
+
+// cacheSize is the number of clients whose rates are tracked at once. The least
+// recently seen clients start over with a full bucket.
+const cacheSize = 16384
+
+// Limiter tracks the rate of every client in a token bucket.
+type Limiter[K comparable] struct {
+	limit rate.Limit
+	burst int
+
+	lock    sync.Mutex
+	clients lru.BasicLRU[K, *rate.Limiter]
+}
+
+// New creates a limiter allowing every client the given number of events per
+// second, with bursts of up to 'burst' events, at least one.
+func New[K comparable](limit float64, burst int) *Limiter[K] {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter[K]{
+		limit:   rate.Limit(limit),
+		burst:   burst,
+		clients: lru.NewBasicLRU[K, *rate.Limiter](cacheSize),
+	}
+}
+
+// Allow takes a token from the bucket of the given client, reporting whether one
+// was available.
+func (l *Limiter[K]) Allow(client K) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	limiter, ok := l.clients.Get(client)
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.clients.Add(client, limiter)
+	}
+	return limiter.Allow()
+}
+
+// Host returns the host of the given remote address, which identifies a client
+// as it may use any number of connections.
+func Host(remote string) string {
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ratelimit
+
+import "testing"
+
+func TestLimiter(t *testing.T) {
+	l := New[string](0.001, 2)
+	for i := 0; i < 2; i++ {
+		if !l.Allow("a") {
+			t.Fatalf("event %d: not allowed within burst", i)
+		}
+	}
+	if l.Allow("a") {
+		t.Fatal("event allowed beyond burst")
+	}
+	// Clients have separate buckets, with a burst of at least one event.
+	if !l.Allow("b") {
+		t.Fatal("event of other client not allowed")
+	}
+	if l := New[string](0.001, 0); !l.Allow("a") || l.Allow("a") {
+		t.Fatal("burst not raised to one event")
+	}
+}
+
+func TestHost(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.1:1234": "10.0.0.1",
+		"[::1]:8545":    "::1",
+		"10.0.0.1":      "10.0.0.1",
+		"":              "",
+	}
+	for remote, want := range tests {
+		if have := Host(remote); have != want {
+			t.Errorf("%q: have %q, want %q", remote, have, want)
+		}
+	}
+}
@@ -30,6 +30,7 @@ var Modules = map[string]string{
 	"txpool":   TxpoolJs,
 	"les":      LESJs,
 	"vflux":    VfluxJs,
+	"patex":    PatexJs,
 }
 
 const CliqueJs = `
@@ -189,6 +190,14 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'reloadAPIKeys',
+			call: 'admin_reloadAPIKeys'
+		}),
+		new web3._extend.Method({
+			name: 'apiKeyUsage',
+			call: 'admin_apiKeyUsage'
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -534,12 +543,23 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'estimateL1Fee',
+			call: 'eth_estimateL1Fee',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'eth_submitTransaction',
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendRawTransactionConditional',
+			call: 'eth_sendRawTransactionConditional',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'fillTransaction',
 			call: 'eth_fillTransaction',
@@ -883,3 +903,59 @@ web3._extend({
 	]
 });
 `
+
+const PatexJs = `
+web3._extend({
+	property: 'patex',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getSharePriceHistory',
+			call: 'patex_getSharePriceHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getAccruedYield',
+			call: 'patex_getAccruedYield',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getYieldModeChanges',
+			call: 'patex_getYieldModeChanges',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getGasParameters',
+			call: 'patex_getGasParameters',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDepositBySourceHash',
+			call: 'patex_getDepositBySourceHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBlockDeposits',
+			call: 'patex_getBlockDeposits',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockFeeStats',
+			call: 'patex_getBlockFeeStats',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFeeStatsRange',
+			call: 'patex_getFeeStatsRange',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
@@ -18,7 +18,6 @@
 package les
 
 import (
-	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -36,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/historical"
 	"github.com/ethereum/go-ethereum/internal/shutdowncheck"
 	"github.com/ethereum/go-ethereum/les/downloader"
 	"github.com/ethereum/go-ethereum/les/vflux"
@@ -68,8 +68,7 @@ type LightEthereum struct {
 	pruner             *pruner
 	merger             *consensus.Merger
 
-	seqRPCService        *rpc.Client
-	historicalRPCService *rpc.Client
+	historicalRouter *historical.Router
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
@@ -202,23 +201,21 @@ func New(stack *node.Node, config *ethconfig.Config) (*LightEthereum, error) {
 	}
 
 	if config.RollupSequencerHTTP != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		client, err := rpc.DialContext(ctx, config.RollupSequencerHTTP)
-		cancel()
-		if err != nil {
-			return nil, err
-		}
-		leth.seqRPCService = client
+		// Light clients relay transactions to their servers, which forward them
+		// to the sequencer themselves.
+		log.Warn("Sequencer forwarding is not supported by light clients")
 	}
 
 	if config.RollupHistoricalRPC != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), config.RollupHistoricalRPCTimeout)
-		client, err := rpc.DialContext(ctx, config.RollupHistoricalRPC)
-		cancel()
+		endpoints, err := historical.ParseEndpoints(config.RollupHistoricalRPC)
+		if err != nil {
+			return nil, err
+		}
+		router, err := historical.NewRouter(endpoints, config.RollupHistoricalRPCTimeout, config.RollupHistoricalRPCCacheSize)
 		if err != nil {
 			return nil, err
 		}
-		leth.historicalRPCService = client
+		leth.historicalRouter = router
 	}
 
 	leth.netRPCService = ethapi.NewNetAPI(leth.p2pServer, leth.config.NetworkId)
@@ -406,6 +403,7 @@ func (s *LightEthereum) Stop() error {
 	s.handler.stop()
 	s.txPool.Stop()
 	s.engine.Close()
+	s.historicalRouter.Close()
 	s.pruner.close()
 	s.eventMux.Stop()
 	// Clean shutdown marker as the last thing before closing db
@@ -62,6 +62,9 @@ type Config struct {
 	Noverify   bool           // Disable remote mining solution verification(only useful in ethash).
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+
+	TxOrdering      TxOrdering `toml:",omitempty"` // Policy ordering pool transactions in a block, price ordering if empty
+	MaxTxsPerSender int        `toml:",omitempty"` // Maximum number of pool transactions of a sender in a block (0 = unlimited)
 }
 
 // DefaultConfig contains default settings for miner.
@@ -75,6 +78,7 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+	TxOrdering:        OrderingPrice,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -257,6 +261,12 @@ func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscript
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
 
+// SubscribePayloadTxs starts delivering the transactions appended to payloads
+// while they are being built to the given channel.
+func (miner *Miner) SubscribePayloadTxs(ch chan<- PayloadTxEvent) event.Subscription {
+	return miner.worker.payloadTxFeed.Subscribe(ch)
+}
+
 // BuildPayload builds the payload according to the provided parameters.
 func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	return miner.worker.buildPayload(args)
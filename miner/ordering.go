@@ -0,0 +1,289 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxOrdering is the policy ordering pool transactions when filling a block.
+type TxOrdering string
+
+const (
+	// OrderingPrice includes transactions by effective miner tip, the time they
+	// were first seen breaking ties.
+	OrderingPrice TxOrdering = "price"
+
+	// OrderingArrival includes transactions first come first served, by the time
+	// they were first seen locally.
+	OrderingArrival TxOrdering = "arrival"
+
+	// OrderingL1AdjustedTip includes transactions by effective miner tip minus
+	// their L1 data fee spread over their gas limit, favouring transactions that
+	// are cheap to post to L1.
+	OrderingL1AdjustedTip TxOrdering = "l1tip"
+)
+
+// TxOrderings lists the supported transaction ordering policies.
+var TxOrderings = []TxOrdering{OrderingPrice, OrderingArrival, OrderingL1AdjustedTip}
+
+// ParseTxOrdering returns the ordering policy of the given name.
+func ParseTxOrdering(name string) (TxOrdering, error) {
+	for _, ordering := range TxOrderings {
+		if TxOrdering(name) == ordering {
+			return ordering, nil
+		}
+	}
+	return "", fmt.Errorf("unknown transaction ordering %q, want one of %v", name, TxOrderings)
+}
+
+// orderedTransactions is a set of transactions returned in the order of some
+// policy, while honouring the nonce order of each sender.
+type orderedTransactions interface {
+	// Peek returns the next transaction, nil if the set is exhausted.
+	Peek() *types.Transaction
+
+	// Shift replaces the next transaction with the next one from the same sender.
+	Shift()
+
+	// Pop removes the next transaction and all further ones from the same sender.
+	Pop()
+}
+
+// orderTransactions returns the given pending transactions in the order of the
+// configured policy, limited to the configured number of transactions per
+// sender in the block under construction.
+func (w *worker) orderTransactions(env *environment, txs map[common.Address]types.Transactions) orderedTransactions {
+	var set orderedTransactions
+	switch w.ordering {
+	case OrderingArrival:
+		set = newTransactionsByArrival(env.signer, txs, env.header.BaseFee)
+	case OrderingL1AdjustedTip:
+		l1Cost := types.NewL1CostFunc(w.chainConfig, env.state)
+		set = newTransactionsByL1AdjustedTip(env.signer, txs, env.header, l1Cost)
+	default:
+		set = types.NewTransactionsByPriceAndNonce(env.signer, txs, env.header.BaseFee)
+	}
+	if w.config.MaxTxsPerSender > 0 {
+		set = newSenderLimitedTransactions(set, env, w.config.MaxTxsPerSender)
+	}
+	return set
+}
+
+// orderedTx is a sender's next transaction in a transactionsByPolicy set.
+type orderedTx struct {
+	tx    *types.Transaction
+	from  common.Address
+	score *big.Int // Value of the transaction to the policy, higher goes first
+}
+
+// orderedTxHeap is a heap of transactions in the order given by less.
+type orderedTxHeap struct {
+	txs  []*orderedTx
+	less func(a, b *orderedTx) bool
+}
+
+func (h *orderedTxHeap) Len() int           { return len(h.txs) }
+func (h *orderedTxHeap) Less(i, j int) bool { return h.less(h.txs[i], h.txs[j]) }
+func (h *orderedTxHeap) Swap(i, j int)      { h.txs[i], h.txs[j] = h.txs[j], h.txs[i] }
+
+func (h *orderedTxHeap) Push(x interface{}) {
+	h.txs = append(h.txs, x.(*orderedTx))
+}
+
+func (h *orderedTxHeap) Pop() interface{} {
+	old := h.txs
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	h.txs = old[0 : n-1]
+	return x
+}
+
+// transactionsByPolicy orders the next transaction of every sender by a score
+// and comparison function given by the policy.
+type transactionsByPolicy struct {
+	txs   map[common.Address]types.Transactions // Per sender nonce-sorted list of transactions
+	heads *orderedTxHeap                        // Next transaction of each sender
+	score func(tx *types.Transaction) (*big.Int, error)
+}
+
+// newTransactionsByPolicy creates a transaction set ordered by the given score
+// and comparison functions. Senders whose next transaction cannot be scored
+// are dropped.
+//
+// Note, the input map is reowned so the caller should not interact any more with
+// it after providing it to the constructor.
+func newTransactionsByPolicy(signer types.Signer, txs map[common.Address]types.Transactions, score func(tx *types.Transaction) (*big.Int, error), less func(a, b *orderedTx) bool) *transactionsByPolicy {
+	heads := &orderedTxHeap{txs: make([]*orderedTx, 0, len(txs)), less: less}
+	for from, accTxs := range txs {
+		acc, _ := types.Sender(signer, accTxs[0])
+		value, err := score(accTxs[0])
+		if acc != from || err != nil {
+			delete(txs, from)
+			continue
+		}
+		heads.txs = append(heads.txs, &orderedTx{tx: accTxs[0], from: from, score: value})
+		txs[from] = accTxs[1:]
+	}
+	heap.Init(heads)
+
+	return &transactionsByPolicy{txs: txs, heads: heads, score: score}
+}
+
+// newTransactionsByArrival creates a transaction set returning transactions in
+// the order they were first seen locally, the hash breaking ties to keep the
+// order deterministic. Transactions not paying the base fee are dropped.
+func newTransactionsByArrival(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) *transactionsByPolicy {
+	score := func(tx *types.Transaction) (*big.Int, error) {
+		return tx.EffectiveGasTip(baseFee)
+	}
+	return newTransactionsByPolicy(signer, txs, score, lessByArrival)
+}
+
+// newTransactionsByL1AdjustedTip creates a transaction set returning transactions
+// by their effective miner tip less the L1 data fee per unit of gas limit.
+func newTransactionsByL1AdjustedTip(signer types.Signer, txs map[common.Address]types.Transactions, header *types.Header, l1Cost types.L1CostFunc) *transactionsByPolicy {
+	score := func(tx *types.Transaction) (*big.Int, error) {
+		tip, err := tx.EffectiveGasTip(header.BaseFee)
+		if err != nil {
+			return nil, err
+		}
+		cost := l1Cost(header.Number.Uint64(), header.Time, tx.RollupDataGas(), tx.IsDepositTx())
+		if cost == nil || tx.Gas() == 0 {
+			return tip, nil
+		}
+		return tip.Sub(tip, new(big.Int).Div(cost, new(big.Int).SetUint64(tx.Gas()))), nil
+	}
+	less := func(a, b *orderedTx) bool {
+		if cmp := a.score.Cmp(b.score); cmp != 0 {
+			return cmp > 0
+		}
+		return lessByArrival(a, b)
+	}
+	return newTransactionsByPolicy(signer, txs, score, less)
+}
+
+// lessByArrival orders transactions by the time they were first seen, then by
+// hash.
+func lessByArrival(a, b *orderedTx) bool {
+	if ta, tb := a.tx.Time(), b.tx.Time(); !ta.Equal(tb) {
+		return ta.Before(tb)
+	}
+	ha, hb := a.tx.Hash(), b.tx.Hash()
+	return bytes.Compare(ha[:], hb[:]) < 0
+}
+
+// Peek returns the next transaction of the policy.
+func (t *transactionsByPolicy) Peek() *types.Transaction {
+	if t.heads.Len() == 0 {
+		return nil
+	}
+	return t.heads.txs[0].tx
+}
+
+// Shift replaces the next transaction with the next one from the same sender.
+func (t *transactionsByPolicy) Shift() {
+	head := t.heads.txs[0]
+	if txs := t.txs[head.from]; len(txs) > 0 {
+		if value, err := t.score(txs[0]); err == nil {
+			head.tx, head.score, t.txs[head.from] = txs[0], value, txs[1:]
+			heap.Fix(t.heads, 0)
+			return
+		}
+	}
+	heap.Pop(t.heads)
+}
+
+// Pop removes the next transaction, *not* replacing it with the next one from
+// the same sender.
+func (t *transactionsByPolicy) Pop() {
+	heap.Pop(t.heads)
+}
+
+// senderLimitedTransactions caps the number of transactions a sender can have
+// in the block under construction, dropping the sender once it is reached.
+type senderLimitedTransactions struct {
+	orderedTransactions
+
+	env    *environment
+	limit  int
+	tcount int                    // Transaction count of env at the last shift
+	counts map[common.Address]int // Transactions included per sender
+}
+
+// newSenderLimitedTransactions limits the transactions of each sender returned
+// by the given set, counting those already included in the block.
+func newSenderLimitedTransactions(set orderedTransactions, env *environment, limit int) *senderLimitedTransactions {
+	counts := make(map[common.Address]int)
+	for _, tx := range env.txs {
+		if tx.IsDepositTx() {
+			continue
+		}
+		from, _ := types.Sender(env.signer, tx)
+		counts[from]++
+	}
+	t := &senderLimitedTransactions{
+		orderedTransactions: set,
+		env:                 env,
+		limit:               limit,
+		tcount:              env.tcount,
+		counts:              counts,
+	}
+	t.skipLimited()
+	return t
+}
+
+// Shift replaces the next transaction with the next one from the same sender,
+// unless the previous one was included and used up the sender's allowance.
+func (t *senderLimitedTransactions) Shift() {
+	if t.env.tcount != t.tcount {
+		t.tcount = t.env.tcount
+
+		from, _ := types.Sender(t.env.signer, t.Peek())
+		if t.counts[from]++; t.counts[from] >= t.limit {
+			t.orderedTransactions.Pop()
+			t.skipLimited()
+			return
+		}
+	}
+	t.orderedTransactions.Shift()
+	t.skipLimited()
+}
+
+// Pop removes the next transaction and all further ones of the same sender.
+func (t *senderLimitedTransactions) Pop() {
+	t.orderedTransactions.Pop()
+	t.skipLimited()
+}
+
+// skipLimited drops the senders at the head of the set whose allowance is used up.
+func (t *senderLimitedTransactions) skipLimited() {
+	for tx := t.Peek(); tx != nil; tx = t.Peek() {
+		from, _ := types.Sender(t.env.signer, tx)
+		if t.counts[from] < t.limit {
+			return
+		}
+		t.orderedTransactions.Pop()
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// makeOrderingTxs creates a few transactions for each of the given number of
+// senders, with tips and calldata varying by sender and nonce.
+func makeOrderingTxs(t *testing.T, signer types.Signer, senders int) map[common.Address]types.Transactions {
+	t.Helper()
+
+	txs := make(map[common.Address]types.Transactions)
+	for i := 0; i < senders; i++ {
+		key, _ := crypto.GenerateKey()
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			tip := big.NewInt(int64((i*7+int(nonce)*3)%10 + 1))
+			tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   signer.ChainID(),
+				Nonce:     nonce,
+				GasTipCap: tip,
+				GasFeeCap: new(big.Int).Add(tip, big.NewInt(100)),
+				Gas:       50_000,
+				To:        &common.Address{},
+				Data:      make([]byte, i*100),
+			})
+			if err != nil {
+				t.Fatalf("failed to sign tx: %v", err)
+			}
+			txs[from] = append(txs[from], tx)
+		}
+	}
+	return txs
+}
+
+// drainOrdering returns all transactions of the set, as if they were all included.
+func drainOrdering(set orderedTransactions, env *environment) []*types.Transaction {
+	var txs []*types.Transaction
+	for tx := set.Peek(); tx != nil; tx = set.Peek() {
+		txs = append(txs, tx)
+		env.txs = append(env.txs, tx)
+		env.tcount++
+		set.Shift()
+	}
+	return txs
+}
+
+// checkNonceOrder checks that each sender's transactions are returned in nonce order.
+func checkNonceOrder(t *testing.T, signer types.Signer, txs []*types.Transaction) {
+	t.Helper()
+
+	next := make(map[common.Address]uint64)
+	for i, tx := range txs {
+		from, _ := types.Sender(signer, tx)
+		if tx.Nonce() != next[from] {
+			t.Fatalf("tx %d: nonce mismatch for %x: have %d, want %d", i, from, tx.Nonce(), next[from])
+		}
+		next[from]++
+	}
+}
+
+func TestTransactionsByArrival(t *testing.T) {
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	txs := makeOrderingTxs(t, signer, 10)
+
+	env := &environment{signer: signer}
+	have := drainOrdering(newTransactionsByArrival(signer, txs, big.NewInt(10)), env)
+	if len(have) != 30 {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(have), 30)
+	}
+	checkNonceOrder(t, signer, have)
+	for i := 1; i < len(have); i++ {
+		if have[i].Time().Before(have[i-1].Time()) {
+			t.Fatalf("tx %d: seen before its predecessor", i)
+		}
+	}
+}
+
+func TestTransactionsByL1AdjustedTip(t *testing.T) {
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	txs := makeOrderingTxs(t, signer, 10)
+
+	pending := make(map[common.Address]types.Transactions)
+	for from, accTxs := range txs {
+		pending[from] = accTxs
+	}
+	// Charge 1000 wei per byte of calldata, making large transactions less
+	// attractive than their tip suggests.
+	l1Cost := func(blockNum uint64, blockTime uint64, dataGas types.RollupGasData, isDepositTx bool) *big.Int {
+		return new(big.Int).SetUint64(1000 * (dataGas.Zeroes + dataGas.Ones))
+	}
+	header := &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(10)}
+	score := func(tx *types.Transaction) *big.Int {
+		tip, _ := tx.EffectiveGasTip(header.BaseFee)
+		cost := l1Cost(0, 0, tx.RollupDataGas(), false)
+		return tip.Sub(tip, cost.Div(cost, new(big.Int).SetUint64(tx.Gas())))
+	}
+	env := &environment{signer: signer}
+	have := drainOrdering(newTransactionsByL1AdjustedTip(signer, txs, header, l1Cost), env)
+	if len(have) != 30 {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(have), 30)
+	}
+	checkNonceOrder(t, signer, have)
+
+	// Every transaction must be the best of the next transactions of all senders.
+	for i, tx := range have {
+		for _, accTxs := range pending {
+			if len(accTxs) > 0 && score(accTxs[0]).Cmp(score(tx)) > 0 {
+				t.Fatalf("tx %d: better transaction %x available", i, accTxs[0].Hash())
+			}
+		}
+		from, _ := types.Sender(signer, tx)
+		pending[from] = pending[from][1:]
+	}
+}
+
+func TestSenderLimitedTransactions(t *testing.T) {
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	txs := makeOrderingTxs(t, signer, 5)
+
+	// One transaction of the first sender is already in the block.
+	var (
+		env     = &environment{signer: signer}
+		initial common.Address
+	)
+	for from, accTxs := range txs {
+		initial = from
+		env.txs, env.tcount = accTxs[:1], 1
+		txs[from] = accTxs[1:]
+		break
+	}
+	set := newSenderLimitedTransactions(types.NewTransactionsByPriceAndNonce(signer, txs, big.NewInt(10)), env, 2)
+
+	counts := make(map[common.Address]int)
+	for _, tx := range drainOrdering(set, env) {
+		from, _ := types.Sender(signer, tx)
+		counts[from]++
+	}
+	if len(counts) != 5 {
+		t.Fatalf("sender count mismatch: have %d, want %d", len(counts), 5)
+	}
+	for from, count := range counts {
+		want := 2
+		if from == initial {
+			want = 1
+		}
+		if count != want {
+			t.Errorf("sender %x: transaction count mismatch: have %d, want %d", from, count, want)
+		}
+	}
+}
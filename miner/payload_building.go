@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
@@ -73,6 +74,27 @@ func (args *BuildPayloadArgs) Id() engine.PayloadID {
 	return out
 }
 
+// PayloadTxEvent is posted for every transaction appended to a payload while it
+// is being built. Every build of a payload starts over from the parent block,
+// so the transactions of a build supersede those of all earlier builds.
+//
+// Building never waits for the subscribers, events are dropped if they fall
+// behind, leaving gaps in the indexes of a build.
+type PayloadTxEvent struct {
+	PayloadID engine.PayloadID
+	Build     uint64 // Sequence number of the build, 0 being the initial empty payload
+	Index     int    // Position of the transaction in the payload
+	Tx        *types.Transaction
+	Receipt   *types.Receipt
+}
+
+// payloadBuild is a single attempt at building a payload.
+type payloadBuild struct {
+	id        engine.PayloadID
+	seq       uint64
+	interrupt *atomic.Int32 // Interrupts the build once the payload is delivered
+}
+
 // Payload wraps the built payload(block waiting for sealing). According to the
 // engine-api specification, EL should build the initial version of the payload
 // which has an empty transaction set and then keep update it in order to maximize
@@ -83,6 +105,7 @@ type Payload struct {
 	empty    *types.Block
 	full     *types.Block
 	fullFees *big.Int
+	building *payloadBuild // Build in progress, interrupted on delivery
 	stop     chan struct{}
 	lock     sync.Mutex
 	cond     *sync.Cond
@@ -125,6 +148,21 @@ func (payload *Payload) update(block *types.Block, fees *big.Int, elapsed time.D
 	payload.cond.Broadcast() // fire signal for notifying full block
 }
 
+// startBuild registers a new build of the payload, returning nil if the
+// payload was already delivered.
+func (payload *Payload) startBuild(seq uint64) *payloadBuild {
+	payload.lock.Lock()
+	defer payload.lock.Unlock()
+
+	select {
+	case <-payload.stop:
+		return nil
+	default:
+	}
+	payload.building = &payloadBuild{id: payload.id, seq: seq, interrupt: new(atomic.Int32)}
+	return payload.building
+}
+
 // Resolve returns the latest built payload and also terminates the background
 // thread for updating payload. It's safe to be called multiple times.
 func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
@@ -135,6 +173,11 @@ func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
 	case <-payload.stop:
 	default:
 		close(payload.stop)
+
+		// Abort the build in progress, its result would be rejected anyway.
+		if payload.building != nil {
+			payload.building.interrupt.Store(commitInterruptResolve)
+		}
 	}
 	if payload.full != nil {
 		return engine.BlockToExecutableData(payload.full, payload.fullFees)
@@ -173,12 +216,13 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	// Build the initial version with no transaction included. It should be fast
 	// enough to run. The empty payload can at least make sure there is something
 	// to deliver for not missing slot.
-	empty, _, err := w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, true, args.Transactions, args.GasLimit)
+	id := args.Id()
+	empty, _, err := w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, true, args.Transactions, args.GasLimit, &payloadBuild{id: id, interrupt: new(atomic.Int32)})
 	if err != nil {
 		return nil, err
 	}
 	// Construct a payload object for return.
-	payload := newPayload(empty, args.Id())
+	payload := newPayload(empty, id)
 	if args.NoTxPool { // don't start the background payload updating job if there is no tx pool to pull from
 		return payload, nil
 	}
@@ -196,11 +240,15 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 		// by the timestamp parameter.
 		endTimer := time.NewTimer(time.Second * 12)
 
-		for {
+		for seq := uint64(1); ; seq++ {
 			select {
 			case <-timer.C:
+				build := payload.startBuild(seq)
+				if build == nil {
+					continue // delivered, stop signal is pending
+				}
 				start := time.Now()
-				block, fees, err := w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, false, args.Transactions, args.GasLimit)
+				block, fees, err := w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, false, args.Transactions, args.GasLimit, build)
 				if err == nil {
 					payload.update(block, fees, time.Since(start))
 				}
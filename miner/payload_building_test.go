@@ -81,6 +81,97 @@ func TestBuildPayload(t *testing.T) {
 	}
 }
 
+func TestBuildPayloadStreaming(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	events := make(chan PayloadTxEvent, 16)
+	sub := w.payloadTxFeed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: common.HexToAddress("0xdeadbeef"),
+	}
+	payload, err := w.buildPayload(args)
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	payload.ResolveFull()
+
+	select {
+	case ev := <-events:
+		if ev.PayloadID != args.Id() {
+			t.Fatalf("Unexpected payload id %v, want %v", ev.PayloadID, args.Id())
+		}
+		if ev.Build != 1 || ev.Index != 0 {
+			t.Fatalf("Unexpected build %d, index %d", ev.Build, ev.Index)
+		}
+		if ev.Tx.Hash() != pendingTxs[0].Hash() {
+			t.Fatalf("Unexpected transaction %v, want %v", ev.Tx.Hash(), pendingTxs[0].Hash())
+		}
+		if ev.Receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatal("Unexpected receipt status")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No transaction streamed")
+	}
+}
+
+// Tests that a subscriber not reading the payload transactions does not stall
+// building payloads.
+func TestBuildPayloadSlowSubscriber(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	sub := w.payloadTxFeed.Subscribe(make(chan PayloadTxEvent))
+	defer sub.Unsubscribe()
+
+	// Fill the queue of undelivered events, the transactions of the payload
+	// have to be dropped.
+	for i := 0; i < payloadTxChanSize+1; i++ {
+		w.payloadTxCh <- PayloadTxEvent{}
+	}
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: common.HexToAddress("0xdeadbeef"),
+	}
+	payload, err := w.buildPayload(args)
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	done := make(chan *engine.ExecutionPayloadEnvelope)
+	go func() { done <- payload.ResolveFull() }()
+
+	select {
+	case full := <-done:
+		if len(full.ExecutionPayload.Transactions) != len(pendingTxs) {
+			t.Fatalf("Unexpected transaction count %d, want %d", len(full.ExecutionPayload.Transactions), len(pendingTxs))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Payload building stalled by subscriber")
+	}
+}
+
+func TestResolveInterruptsBuild(t *testing.T) {
+	empty := types.NewBlockWithHeader(&types.Header{Number: common.Big1, BaseFee: common.Big1})
+	payload := newPayload(empty, engine.PayloadID{1})
+
+	build := payload.startBuild(1)
+	if build == nil {
+		t.Fatal("Failed to start build")
+	}
+	payload.Resolve()
+	if signal := build.interrupt.Load(); signal != commitInterruptResolve {
+		t.Fatalf("Build not interrupted, signal %d", signal)
+	}
+	if payload.startBuild(2) != nil {
+		t.Fatal("Started build of delivered payload")
+	}
+}
+
 func TestPayloadId(t *testing.T) {
 	ids := make(map[string]int)
 	for i, tt := range []*BuildPayloadArgs{
@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -56,6 +57,10 @@ const (
 	// resubmitAdjustChanSize is the size of resubmitting interval adjustment channel.
 	resubmitAdjustChanSize = 10
 
+	// payloadTxChanSize is the size of the channel queueing payload transaction
+	// events for delivery to the subscribers.
+	payloadTxChanSize = 1024
+
 	// sealingLogAtDepth is the number of confirmations before logging successful sealing.
 	sealingLogAtDepth = 7
 
@@ -83,6 +88,11 @@ var (
 	errBlockInterruptedByNewHead  = errors.New("new head arrived while building block")
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
+	errBlockInterruptedByResolve  = errors.New("payload resolved while building block")
+
+	// payloadTxDroppedMeter counts the payload transaction events dropped as
+	// the subscribers fell behind.
+	payloadTxDroppedMeter = metrics.NewRegisteredMeter("miner/payloadtxs/dropped", nil)
 )
 
 // environment is the worker's current environment and holds all
@@ -100,7 +110,10 @@ type environment struct {
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
+	feeStats *types.BlockFeeStats
 	uncles   map[common.Hash]*types.Header
+
+	build *payloadBuild // Payload build the environment belongs to, if any
 }
 
 // copy creates a deep copy of environment.
@@ -114,6 +127,7 @@ func (env *environment) copy() *environment {
 		coinbase:  env.coinbase,
 		header:    types.CopyHeader(env.header),
 		receipts:  copyReceipts(env.receipts),
+		feeStats:  env.feeStats.Copy(),
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -152,6 +166,7 @@ func (env *environment) discard() {
 // task contains all information for consensus engine sealing and result submitting.
 type task struct {
 	receipts  []*types.Receipt
+	feeStats  *types.BlockFeeStats
 	state     *state.StateDB
 	block     *types.Block
 	createdAt time.Time
@@ -162,6 +177,7 @@ const (
 	commitInterruptNewHead
 	commitInterruptResubmit
 	commitInterruptTimeout
+	commitInterruptResolve
 )
 
 // newWorkReq represents a request for new sealing work submitting with relative interrupt notifier.
@@ -201,6 +217,7 @@ type worker struct {
 
 	// Feeds
 	pendingLogsFeed event.Feed
+	payloadTxFeed   event.Feed
 
 	// Subscriptions
 	mux          *event.TypeMux
@@ -220,6 +237,7 @@ type worker struct {
 	exitCh             chan struct{}
 	resubmitIntervalCh chan time.Duration
 	resubmitAdjustCh   chan *intervalAdjust
+	payloadTxCh        chan PayloadTxEvent
 
 	wg sync.WaitGroup
 
@@ -262,6 +280,9 @@ type worker struct {
 	// payload in proof-of-stake stage.
 	recommit time.Duration
 
+	// ordering is the policy ordering pool transactions in the sealing block.
+	ordering TxOrdering
+
 	// External functions
 	isLocalBlock func(header *types.Header) bool // Function used to determine whether the specified block is mined by local miner.
 
@@ -298,6 +319,7 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		exitCh:             make(chan struct{}),
 		resubmitIntervalCh: make(chan time.Duration),
 		resubmitAdjustCh:   make(chan *intervalAdjust, resubmitAdjustChanSize),
+		payloadTxCh:        make(chan PayloadTxEvent, payloadTxChanSize),
 	}
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
@@ -324,11 +346,23 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	worker.newpayloadTimeout = newpayloadTimeout
 
-	worker.wg.Add(4)
+	// Sanitize the transaction ordering policy.
+	ordering := worker.config.TxOrdering
+	if ordering == "" {
+		ordering = OrderingPrice
+	}
+	if _, err := ParseTxOrdering(string(ordering)); err != nil {
+		log.Warn("Sanitizing transaction ordering to default", "provided", ordering, "updated", OrderingPrice)
+		ordering = OrderingPrice
+	}
+	worker.ordering = ordering
+
+	worker.wg.Add(5)
 	go worker.mainLoop()
 	go worker.newWorkLoop(recommit)
 	go worker.resultLoop()
 	go worker.taskLoop()
+	go worker.payloadTxLoop()
 
 	// Submit first work to initialize pending state.
 	if init {
@@ -635,7 +669,7 @@ func (w *worker) mainLoop() {
 					acc, _ := types.Sender(w.current.signer, tx)
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee)
+				txset := w.orderTransactions(w.current, txs)
 				tcount := w.current.tcount
 				w.commitTransactions(w.current, txset, nil)
 
@@ -771,7 +805,7 @@ func (w *worker) resultLoop() {
 				logs = append(logs, receipt.Logs...)
 			}
 			// Commit block and state to database.
-			_, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.state, true)
+			_, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.feeStats, task.state, true)
 			if err != nil {
 				log.Error("Failed writing block to chain", "err", err)
 				continue
@@ -791,6 +825,20 @@ func (w *worker) resultLoop() {
 	}
 }
 
+// payloadTxLoop is a standalone goroutine delivering the transactions appended
+// to payloads to the subscribers, decoupling them from building the payloads.
+func (w *worker) payloadTxLoop() {
+	defer w.wg.Done()
+	for {
+		select {
+		case ev := <-w.payloadTxCh:
+			w.payloadTxFeed.Send(ev)
+		case <-w.exitCh:
+			return
+		}
+	}
+}
+
 // makeEnv creates a new environment for the sealing block.
 func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase common.Address) (*environment, error) {
 	// Retrieve the parent state to execute on top and start a prefetcher for
@@ -818,6 +866,7 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 		ancestors: mapset.NewSet[common.Hash](),
 		family:    mapset.NewSet[common.Hash](),
 		header:    header,
+		feeStats:  types.NewBlockFeeStats(),
 		uncles:    make(map[common.Hash]*types.Header),
 	}
 	// when 08 is processed ancestors contain 07 (quick block)
@@ -876,7 +925,7 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
 	)
-	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig())
+	receipt, err := core.ApplyTransactionWithFeeStats(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig(), env.feeStats)
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
@@ -885,10 +934,24 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*
 	env.txs = append(env.txs, tx)
 	env.receipts = append(env.receipts, receipt)
 
+	if env.build != nil {
+		// Never wait for slow subscribers while building, drop the event instead.
+		select {
+		case w.payloadTxCh <- PayloadTxEvent{
+			PayloadID: env.build.id,
+			Build:     env.build.seq,
+			Index:     len(env.txs) - 1,
+			Tx:        tx,
+			Receipt:   receipt,
+		}:
+		default:
+			payloadTxDroppedMeter.Mark(1)
+		}
+	}
 	return receipt.Logs, nil
 }
 
-func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+func (w *worker) commitTransactions(env *environment, txs orderedTransactions, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -924,6 +987,16 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 			txs.Pop()
 			continue
 		}
+		// Skip the sender if the inclusion requirements of the transaction are not
+		// met by this block. The pool drops it once they can no longer be met.
+		if cond := tx.Conditional(); cond != nil {
+			if err := checkConditional(env, cond); err != nil {
+				log.Trace("Skipping transaction with failed conditional", "hash", tx.Hash(), "err", err)
+
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -965,6 +1038,18 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 	return nil
 }
 
+// checkConditional checks whether the block under construction meets the
+// inclusion requirements of a transaction.
+func checkConditional(env *environment, cond *types.TransactionConditional) error {
+	if err := cond.CheckBlockNumber(env.header.Number); err != nil {
+		return err
+	}
+	if err := cond.CheckTimestamp(env.header.Time); err != nil {
+		return err
+	}
+	return env.state.CheckKnownAccounts(cond.KnownAccounts)
+}
+
 // generateParams wraps various of settings for generating sealing task.
 type generateParams struct {
 	timestamp   uint64            // The timstamp for sealing task
@@ -978,6 +1063,7 @@ type generateParams struct {
 
 	txs      types.Transactions // Deposit transactions to include at the start of the block
 	gasLimit *uint64            // Optional gas limit override
+	build    *payloadBuild      // Payload build the block is generated for, if any
 }
 
 // prepareWork constructs the sealing task according to the given parameters,
@@ -1070,8 +1156,7 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 }
 
 // fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// into the given sealing block, in the order of the configured ordering policy.
 func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) error {
 	// Split the pending transactions into locals and remotes
 	// Fill the block with all available pending transactions.
@@ -1084,13 +1169,13 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 		}
 	}
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, env.header.BaseFee)
+		txs := w.orderTransactions(env, localTxs)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, env.header.BaseFee)
+		txs := w.orderTransactions(env, remoteTxs)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
@@ -1108,6 +1193,7 @@ func (w *worker) generateWork(genParams *generateParams) (*types.Block, *big.Int
 	if work.gasPool == nil {
 		work.gasPool = new(core.GasPool).AddGas(work.header.GasLimit)
 	}
+	work.build = genParams.build
 
 	for _, tx := range genParams.txs {
 		from, _ := types.Sender(work.signer, tx)
@@ -1122,14 +1208,20 @@ func (w *worker) generateWork(genParams *generateParams) (*types.Block, *big.Int
 	// forced transactions done, fill rest of block with transactions
 	if !genParams.noTxs {
 		interrupt := new(atomic.Int32)
+		if genParams.build != nil {
+			interrupt = genParams.build.interrupt
+		}
 		timer := time.AfterFunc(w.newpayloadTimeout, func() {
-			interrupt.Store(commitInterruptTimeout)
+			interrupt.CompareAndSwap(commitInterruptNone, commitInterruptTimeout)
 		})
 		defer timer.Stop()
 
 		err := w.fillTransactions(interrupt, work)
-		if errors.Is(err, errBlockInterruptedByTimeout) {
+		switch {
+		case errors.Is(err, errBlockInterruptedByTimeout):
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(w.newpayloadTimeout))
+		case errors.Is(err, errBlockInterruptedByResolve):
+			log.Debug("Block building is interrupted by payload delivery", "id", genParams.build.id)
 		}
 	}
 	block, err := w.engine.FinalizeAndAssemble(w.chain, work.header, work.state, work.txs, work.unclelist(), work.receipts, genParams.withdrawals)
@@ -1225,7 +1317,7 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		// If we're post merge, just ignore
 		if !w.isTTDReached(block.Header()) {
 			select {
-			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
+			case w.taskCh <- &task{receipts: env.receipts, feeStats: env.feeStats, state: env.state, block: block, createdAt: time.Now()}:
 				w.unconfirmed.Shift(block.NumberU64() - 1)
 
 				fees := totalFees(block, env.receipts)
@@ -1248,8 +1340,9 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 
 // getSealingBlock generates the sealing block based on the given parameters.
 // The generation result will be passed back via the given channel no matter
-// the generation itself succeeds or not.
-func (w *worker) getSealingBlock(parent common.Hash, timestamp uint64, coinbase common.Address, random common.Hash, withdrawals types.Withdrawals, noTxs bool, transactions types.Transactions, gasLimit *uint64) (*types.Block, *big.Int, error) {
+// the generation itself succeeds or not. If the block is built for a payload,
+// the build allows interrupting it and streams its transactions.
+func (w *worker) getSealingBlock(parent common.Hash, timestamp uint64, coinbase common.Address, random common.Hash, withdrawals types.Withdrawals, noTxs bool, transactions types.Transactions, gasLimit *uint64, build *payloadBuild) (*types.Block, *big.Int, error) {
 	req := &getWorkReq{
 		params: &generateParams{
 			timestamp:   timestamp,
@@ -1262,6 +1355,7 @@ func (w *worker) getSealingBlock(parent common.Hash, timestamp uint64, coinbase
 			noTxs:       noTxs,
 			txs:         transactions,
 			gasLimit:    gasLimit,
+			build:       build,
 		},
 		result: make(chan *newPayloadResult, 1),
 	}
@@ -1322,6 +1416,8 @@ func signalToErr(signal int32) error {
 		return errBlockInterruptedByRecommit
 	case commitInterruptTimeout:
 		return errBlockInterruptedByTimeout
+	case commitInterruptResolve:
+		return errBlockInterruptedByResolve
 	default:
 		panic(fmt.Errorf("undefined signal %d", signal))
 	}
@@ -132,7 +132,7 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 	default:
 		t.Fatalf("unexpected consensus engine type: %T", engine)
 	}
-	chain, err := core.NewBlockChain(db, &core.CacheConfig{TrieDirtyDisabled: true}, gspec, nil, engine, vm.Config{}, nil, nil)
+	chain, err := core.NewBlockChain(db, &core.CacheConfig{TrieDirtyDisabled: true, FeeStats: true}, gspec, nil, engine, vm.Config{}, nil, nil)
 	if err != nil {
 		t.Fatalf("core.NewBlockChain failed: %v", err)
 	}
@@ -261,6 +261,15 @@ func testGenerateBlockAndImport(t *testing.T, isClique bool) {
 			if _, err := chain.InsertChain([]*types.Block{block}); err != nil {
 				t.Fatalf("failed to insert new mined block %d: %v", block.NumberU64(), err)
 			}
+			// The fee stats collected while assembling the block must be stored
+			// along with it.
+			stats := rawdb.ReadBlockFeeStats(db, block.Hash(), block.NumberU64())
+			if stats == nil {
+				t.Fatalf("fee stats of mined block %d missing", block.NumberU64())
+			}
+			if stats.UserTxs != uint64(len(block.Transactions())) || stats.UserGasUsed != block.GasUsed() {
+				t.Errorf("fee stats of mined block %d mismatch: have %d txs using %d gas, want %d using %d", block.NumberU64(), stats.UserTxs, stats.UserGasUsed, len(block.Transactions()), block.GasUsed())
+			}
 		case <-time.After(3 * time.Second): // Worker needs 1s to include new changes.
 			t.Fatalf("timeout")
 		}
@@ -635,7 +644,7 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 
 	// This API should work even when the automatic sealing is not enabled
 	for _, c := range cases {
-		block, _, err := w.getSealingBlock(c.parent, timestamp, c.coinbase, c.random, nil, false, nil, nil)
+		block, _, err := w.getSealingBlock(c.parent, timestamp, c.coinbase, c.random, nil, false, nil, nil, nil)
 		if c.expectErr {
 			if err == nil {
 				t.Error("Expect error but get nil")
@@ -651,7 +660,7 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 	// This API should work even when the automatic sealing is enabled
 	w.start()
 	for _, c := range cases {
-		block, _, err := w.getSealingBlock(c.parent, timestamp, c.coinbase, c.random, nil, false, nil, nil)
+		block, _, err := w.getSealingBlock(c.parent, timestamp, c.coinbase, c.random, nil, false, nil, nil, nil)
 		if c.expectErr {
 			if err == nil {
 				t.Error("Expect error but get nil")
@@ -664,3 +673,39 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 		}
 	}
 }
+
+// Tests that transactions are only included in blocks meeting their conditionals.
+func TestConditionalTransactions(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	timestamp := uint64(time.Now().Unix())
+	minimum := timestamp + 10
+
+	tx := types.MustSignNewTx(testBankKey, types.LatestSigner(ethashChainConfig), &types.LegacyTx{
+		Nonce:    1,
+		To:       &testUserAddress,
+		Value:    big.NewInt(1000),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	})
+	tx.SetConditional(&types.TransactionConditional{TimestampMin: &minimum})
+	if err := b.txPool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	for _, test := range []struct {
+		timestamp uint64
+		txs       int
+	}{{timestamp, 1}, {minimum, 2}} {
+		block, _, err := w.getSealingBlock(b.chain.Genesis().Hash(), test.timestamp, testBankAddress, common.Hash{}, nil, false, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to build block: %v", err)
+		}
+		if len(block.Transactions()) != test.txs {
+			t.Errorf("timestamp %d: transaction count mismatch: have %d, want %d", test.timestamp, len(block.Transactions()), test.txs)
+		}
+	}
+}
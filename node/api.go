@@ -176,6 +176,7 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		CorsAllowedOrigins: api.node.config.HTTPCors,
 		Vhosts:             api.node.config.HTTPVirtualHosts,
 		Modules:            api.node.config.HTTPModules,
+		rpcEndpointConfig:  api.node.rpcEndpointConfig(),
 	}
 	if cors != nil {
 		config.CorsAllowedOrigins = nil
@@ -250,6 +251,7 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 		Modules: api.node.config.WSModules,
 		Origins: api.node.config.WSOrigins,
 		// ExposeAll: api.node.config.WSExposeAll,
+		rpcEndpointConfig: api.node.rpcEndpointConfig(),
 	}
 	if apis != nil {
 		config.Modules = nil
@@ -287,6 +289,27 @@ func (api *adminAPI) StopWS() (bool, error) {
 	return true, nil
 }
 
+// ReloadAPIKeys reads the API key file again, replacing the keys and policies of
+// the public RPC endpoints.
+func (api *adminAPI) ReloadAPIKeys() (bool, error) {
+	if api.node.apiKeys == nil {
+		return false, errNoAPIKeys
+	}
+	if err := api.node.apiKeys.reload(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// APIKeyUsage returns the number of calls made with every API key since the node
+// started, including keys that were removed since.
+func (api *adminAPI) APIKeyUsage() (map[string]APIKeyUsage, error) {
+	if api.node.apiKeys == nil {
+		return nil, errNoAPIKeys
+	}
+	return api.node.apiKeys.usageStats(), nil
+}
+
 // Peers retrieves all the information we know about each individual peer at the
 // protocol granularity.
 func (api *adminAPI) Peers() ([]*p2p.PeerInfo, error) {
@@ -0,0 +1,260 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// apiKeyHeader is the HTTP header carrying the API key of a request. The key may
+// also be sent as the path segment following the RPC path prefix.
+const apiKeyHeader = "X-API-Key"
+
+// methodPolicy is a set of RPC methods. Entries are full method names, such as
+// "debug_traceTransaction", whole namespaces, such as "eth_*", or "*" for all
+// methods. Denied methods take precedence over allowed ones.
+type methodPolicy struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// permits reports whether the policy allows calling the given method.
+func (p *methodPolicy) permits(method string) bool {
+	return matchMethod(p.Allow, method) && !matchMethod(p.Deny, method)
+}
+
+// matchMethod reports whether any of the patterns matches the given method.
+func matchMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == method {
+			return true
+		}
+		if strings.HasSuffix(pattern, "_*") && strings.HasPrefix(method, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyFile is the format of the API key file, for example:
+//
+//	{
+//	  "anonymous": {"allow": ["eth_*", "net_*", "web3_*"]},
+//	  "keys": [
+//	    {"name": "indexer", "key": "...", "allow": ["eth_*", "debug_*"], "deny": ["debug_setHead"]}
+//	  ]
+//	}
+//
+// Requests without API key are subject to the anonymous policy. If there is none,
+// they may not call any method.
+type apiKeyFile struct {
+	Anonymous *methodPolicy `json:"anonymous"`
+	Keys      []struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+		methodPolicy
+	} `json:"keys"`
+}
+
+// APIKeyUsage is the number of calls made with an API key since the node started.
+type APIKeyUsage struct {
+	Calls  uint64 `json:"calls"`  // Calls permitted by the policy of the key
+	Denied uint64 `json:"denied"` // Calls rejected by the policy of the key
+}
+
+// apiKeyUsage accounts for the calls made with an API key.
+type apiKeyUsage struct {
+	calls, denied          uint64
+	callMeter, deniedMeter metrics.Meter
+}
+
+func newAPIKeyUsage(name string) *apiKeyUsage {
+	return &apiKeyUsage{
+		callMeter:   metrics.GetOrRegisterMeter("rpc/apikeys/"+name+"/calls", nil),
+		deniedMeter: metrics.GetOrRegisterMeter("rpc/apikeys/"+name+"/denied", nil),
+	}
+}
+
+// apiKeyStore authenticates the clients of the public RPC endpoints by their API
+// keys and authorizes their calls by the policies of the keys. The keys are read
+// from a file that can be reloaded while the node is running.
+type apiKeyStore struct {
+	path string
+
+	lock      sync.RWMutex
+	secrets   map[string]string        // API key names by secret
+	policies  map[string]*methodPolicy // Policies by API key name
+	anonymous *methodPolicy            // Policy of requests without API key, nil if denied
+	usage     map[string]*apiKeyUsage  // Usage by API key name, kept across reloads
+}
+
+// newAPIKeyStore creates a store of the API keys in the given file.
+func newAPIKeyStore(path string) (*apiKeyStore, error) {
+	s := &apiKeyStore{
+		path:  path,
+		usage: make(map[string]*apiKeyUsage),
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload reads the API key file again, replacing all keys and policies. Clients
+// connected with a key that is no longer present are denied further calls.
+func (s *apiKeyStore) reload() error {
+	blob, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var file apiKeyFile
+	if err := json.Unmarshal(blob, &file); err != nil {
+		return fmt.Errorf("invalid API key file %s: %v", s.path, err)
+	}
+	var (
+		secrets  = make(map[string]string, len(file.Keys))
+		policies = make(map[string]*methodPolicy, len(file.Keys))
+	)
+	for i, key := range file.Keys {
+		switch {
+		case key.Name == "" || key.Key == "":
+			return fmt.Errorf("API key #%d has no name or key", i)
+		case policies[key.Name] != nil:
+			return fmt.Errorf("duplicate API key name %q", key.Name)
+		case secrets[key.Key] != "":
+			return fmt.Errorf("API keys %q and %q are the same", secrets[key.Key], key.Name)
+		}
+		policy := key.methodPolicy
+		secrets[key.Key], policies[key.Name] = key.Name, &policy
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.secrets, s.policies, s.anonymous = secrets, policies, file.Anonymous
+	for name := range policies {
+		if s.usage[name] == nil {
+			s.usage[name] = newAPIKeyUsage(name)
+		}
+	}
+	log.Info("Loaded RPC API keys", "path", s.path, "keys", len(policies), "anonymous", file.Anonymous != nil)
+	return nil
+}
+
+// authenticate resolves the API key of an HTTP request to the RPC endpoint at the
+// given path prefix. The key is taken from the API key header or, failing that,
+// from the path segment following the prefix, which is then removed from the
+// path. It returns the request carrying the name of the key, or false if the
+// request was answered as its key is unknown.
+func (s *apiKeyStore) authenticate(w http.ResponseWriter, r *http.Request, prefix string) (*http.Request, bool) {
+	if s == nil {
+		return r, true
+	}
+	secret := r.Header.Get(apiKeyHeader)
+	if secret == "" {
+		base := strings.TrimSuffix(prefix, "/")
+		if segment := strings.TrimPrefix(r.URL.Path, base+"/"); segment != r.URL.Path && segment != "" && !strings.Contains(segment, "/") {
+			url := *r.URL
+			if url.Path = base; url.Path == "" {
+				url.Path = "/"
+			}
+			url.RawPath = ""
+			r = r.Clone(r.Context())
+			r.URL, secret = &url, segment
+		}
+	}
+	if secret == "" {
+		return r, true // anonymous request
+	}
+	s.lock.RLock()
+	name, ok := s.secrets[secret]
+	s.lock.RUnlock()
+
+	if !ok {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+		return nil, false
+	}
+	return r.WithContext(rpc.WithAPIKey(r.Context(), name)), true
+}
+
+// authorize permits a call of the given method if the policy of the API key of
+// the client allows it. It implements rpc.Authorizer.
+func (s *apiKeyStore) authorize(ctx context.Context, method string) error {
+	name := rpc.PeerInfoFromContext(ctx).HTTP.APIKey
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	policy := s.anonymous
+	if name != "" {
+		policy = s.policies[name]
+	}
+	permitted := policy != nil && policy.permits(method)
+	if usage := s.usage[name]; usage != nil {
+		if permitted {
+			atomic.AddUint64(&usage.calls, 1)
+			usage.callMeter.Mark(1)
+		} else {
+			atomic.AddUint64(&usage.denied, 1)
+			usage.deniedMeter.Mark(1)
+		}
+	}
+	if !permitted {
+		return &methodDeniedError{method: method}
+	}
+	return nil
+}
+
+// usageStats returns the usage of all API keys since the node started.
+func (s *apiKeyStore) usageStats() map[string]APIKeyUsage {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	stats := make(map[string]APIKeyUsage, len(s.usage))
+	for name, usage := range s.usage {
+		stats[name] = APIKeyUsage{
+			Calls:  atomic.LoadUint64(&usage.calls),
+			Denied: atomic.LoadUint64(&usage.denied),
+		}
+	}
+	return stats
+}
+
+// errNoAPIKeys is returned by the API key admin methods if the node was not
+// configured with an API key file.
+var errNoAPIKeys = errors.New("API keys not enabled")
+
+// methodDeniedError is returned to clients calling a method that the policy of
+// their API key doesn't allow.
+type methodDeniedError struct{ method string }
+
+func (e *methodDeniedError) ErrorCode() int { return -32004 }
+
+func (e *methodDeniedError) Error() string {
+	return fmt.Sprintf("method %s is not allowed for this API key", e.method)
+}
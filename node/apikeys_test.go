@@ -0,0 +1,148 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchMethod(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		method   string
+		want     bool
+	}{
+		{[]string{"*"}, "debug_setHead", true},
+		{[]string{"debug_traceTransaction"}, "debug_traceTransaction", true},
+		{[]string{"debug_traceTransaction"}, "debug_setHead", false},
+		{[]string{"debug_*"}, "debug_setHead", true},
+		{[]string{"debug_*"}, "debugx_setHead", false},
+		{[]string{"eth_*", "net_version"}, "net_version", true},
+		{nil, "eth_chainId", false},
+	}
+	for i, tt := range tests {
+		if have := matchMethod(tt.patterns, tt.method); have != tt.want {
+			t.Errorf("test %d: %v matching %s: have %t, want %t", i, tt.patterns, tt.method, have, tt.want)
+		}
+	}
+}
+
+// apiKeyCall performs a call of the given method and returns the HTTP status and
+// the JSON-RPC error code, zero if the call succeeded.
+func apiKeyCall(t *testing.T, url, method string, extraHeaders ...string) (int, int) {
+	t.Helper()
+
+	resp := rpcRequest(t, url, method, extraHeaders...)
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, 0
+	}
+	var result struct {
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Error != nil {
+		return resp.StatusCode, result.Error.Code
+	}
+	return resp.StatusCode, 0
+}
+
+func TestAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apikeys.json")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"keys": [{"name": "team", "key": "secret", "allow": ["test_*"], "deny": ["test_sleep"]}]}`)
+
+	keys, err := newAPIKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := createAndStartServer(t, &httpConfig{rpcEndpointConfig: rpcEndpointConfig{apiKeys: keys}}, false, &wsConfig{}, nil)
+	defer srv.stop()
+	url := "http://" + srv.listenAddr()
+
+	tests := []struct {
+		url     string
+		method  string
+		headers []string
+		status  int
+		code    int
+	}{
+		// The key is sent in the header or the path.
+		{url: url, method: "test_greet", headers: []string{apiKeyHeader, "secret"}, status: http.StatusOK},
+		{url: url + "/secret", method: "test_greet", status: http.StatusOK},
+		// Denied methods take precedence.
+		{url: url, method: "test_sleep", headers: []string{apiKeyHeader, "secret"}, status: http.StatusOK, code: -32004},
+		// Unknown keys are rejected, anonymous requests may call nothing.
+		{url: url, method: "test_greet", headers: []string{apiKeyHeader, "other"}, status: http.StatusUnauthorized},
+		{url: url + "/other", method: "test_greet", status: http.StatusUnauthorized},
+		{url: url, method: "test_greet", status: http.StatusOK, code: -32004},
+	}
+	for i, tt := range tests {
+		status, code := apiKeyCall(t, tt.url, tt.method, tt.headers...)
+		if status != tt.status || code != tt.code {
+			t.Errorf("test %d: have status %d, code %d, want status %d, code %d", i, status, code, tt.status, tt.code)
+		}
+	}
+	usage := keys.usageStats()["team"]
+	if usage.Calls != 2 || usage.Denied != 1 {
+		t.Errorf("wrong usage: have %+v, want 2 calls, 1 denied", usage)
+	}
+
+	// Reload the keys, removing the team key and permitting anonymous requests.
+	write(`{"anonymous": {"allow": ["test_greet"]}}`)
+	if err := keys.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if status, code := apiKeyCall(t, url, "test_greet"); status != http.StatusOK || code != 0 {
+		t.Errorf("anonymous call failed: status %d, code %d", status, code)
+	}
+	if status, _ := apiKeyCall(t, url, "test_greet", apiKeyHeader, "secret"); status != http.StatusUnauthorized {
+		t.Errorf("removed key accepted: status %d", status)
+	}
+	if _, ok := keys.usageStats()["team"]; !ok {
+		t.Error("usage of removed key dropped")
+	}
+}
+
+func TestAPIKeyFileErrors(t *testing.T) {
+	tests := []string{
+		`{"keys": [{"name": "a", "allow": ["*"]}]}`,
+		`{"keys": [{"name": "a", "key": "x"}, {"name": "a", "key": "y"}]}`,
+		`{"keys": [{"name": "a", "key": "x"}, {"name": "b", "key": "x"}]}`,
+		`{"keys": {}}`,
+	}
+	for i, content := range tests {
+		path := filepath.Join(t.TempDir(), "apikeys.json")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newAPIKeyStore(path); err == nil {
+			t.Errorf("test %d: invalid key file accepted", i)
+		}
+	}
+}
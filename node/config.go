@@ -175,6 +175,33 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// BatchRequestLimit is the maximum number of requests in a batch served by the
+	// HTTP and websocket RPC interfaces. Zero means no limit.
+	BatchRequestLimit int `toml:",omitempty"`
+
+	// BatchResponseMaxSize is the maximum number of bytes returned from a batched
+	// call on the HTTP and websocket RPC interfaces. Zero means no limit. The
+	// responses of calls sent on their own are not limited in size.
+	BatchResponseMaxSize int `toml:",omitempty"`
+
+	// RPCMethodQuotas limits the number of calls of the given methods that the
+	// HTTP and websocket RPC interfaces execute concurrently.
+	RPCMethodQuotas map[string]int `toml:",omitempty"`
+
+	// RPCRateLimit is the number of calls per second each client may make to the
+	// HTTP and websocket RPC interfaces. Zero means no limit.
+	RPCRateLimit float64 `toml:",omitempty"`
+
+	// RPCRateLimitBurst is the number of calls a client may make in a burst above
+	// the rate limit.
+	RPCRateLimitBurst int `toml:",omitempty"`
+
+	// APIKeysFile is the path of a JSON file listing the API keys of the HTTP and
+	// websocket RPC interfaces, along with the methods each key may call. The file
+	// can be reloaded through admin_reloadAPIKeys. Empty disables API keys.
+	// GraphQL can't be enabled along with API keys.
+	APIKeysFile string `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -64,6 +64,8 @@ type Node struct {
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
+	apiKeys *apiKeyStore // API keys of the public RPC endpoints, nil if not enabled
+
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
 
@@ -145,6 +147,12 @@ func New(conf *Config) (*Node, error) {
 	if err := validatePrefix("WebSocket", conf.WSPathPrefix); err != nil {
 		return nil, err
 	}
+	// Load the API keys of the public RPC endpoints.
+	if conf.APIKeysFile != "" {
+		if node.apiKeys, err = newAPIKeyStore(conf.APIKeysFile); err != nil {
+			return nil, err
+		}
+	}
 
 	// Configure RPC servers.
 	node.http = newHTTPServer(node.log, conf.HTTPTimeouts)
@@ -372,6 +380,20 @@ func (n *Node) obtainJWTSecret(cliParam string) ([]byte, error) {
 	return jwtSecret, nil
 }
 
+// rpcEndpointConfig returns the access and resource limits of the public RPC
+// endpoints. The authenticated and local endpoints serve trusted clients and are
+// not limited.
+func (n *Node) rpcEndpointConfig() rpcEndpointConfig {
+	return rpcEndpointConfig{
+		batchItemLimit:         n.config.BatchRequestLimit,
+		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		methodQuotas:           n.config.RPCMethodQuotas,
+		rateLimit:              n.config.RPCRateLimit,
+		rateLimitBurst:         n.config.RPCRateLimitBurst,
+		apiKeys:                n.apiKeys,
+	}
+}
+
 // startRPC is a helper method to configure all the various RPC endpoints during node
 // startup. It's not meant to be called at any time afterwards as it makes certain
 // assumptions about the state of the node.
@@ -401,6 +423,7 @@ func (n *Node) startRPC() error {
 	var (
 		servers           []*httpServer
 		openAPIs, allAPIs = n.getAPIs()
+		rpcConfig         = n.rpcEndpointConfig()
 	)
 
 	initHttp := func(server *httpServer, port int) error {
@@ -412,6 +435,7 @@ func (n *Node) startRPC() error {
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			rpcEndpointConfig:  rpcConfig,
 		}); err != nil {
 			return err
 		}
@@ -425,9 +449,10 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(openAPIs, wsConfig{
-			Modules: n.config.WSModules,
-			Origins: n.config.WSOrigins,
-			prefix:  n.config.WSPathPrefix,
+			Modules:           n.config.WSModules,
+			Origins:           n.config.WSOrigins,
+			prefix:            n.config.WSPathPrefix,
+			rpcEndpointConfig: rpcConfig,
 		}); err != nil {
 			return err
 		}
@@ -592,6 +617,8 @@ func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
 //
 // The name of the handler is shown in a log message when the HTTP server starts
 // and should be a descriptive term for the service provided by the handler.
+//
+// Requests of the handler are not checked against the API keys of the node.
 func (n *Node) RegisterHandler(name, path string, handler http.Handler) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
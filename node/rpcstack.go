@@ -42,6 +42,7 @@ type httpConfig struct {
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
 	jwtSecret          []byte // optional JWT secret
+	rpcEndpointConfig
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
@@ -50,6 +51,27 @@ type wsConfig struct {
 	Modules   []string
 	prefix    string // path prefix on which to mount ws handler
 	jwtSecret []byte // optional JWT secret
+	rpcEndpointConfig
+}
+
+// rpcEndpointConfig are the access and resource limits of a JSON-RPC endpoint.
+type rpcEndpointConfig struct {
+	batchItemLimit         int
+	batchResponseSizeLimit int
+	methodQuotas           map[string]int
+	rateLimit              float64
+	rateLimitBurst         int
+	apiKeys                *apiKeyStore // optional API key authentication
+}
+
+// apply sets the limits on the given RPC server.
+func (c rpcEndpointConfig) apply(srv *rpc.Server) {
+	srv.SetBatchLimits(c.batchItemLimit, c.batchResponseSizeLimit)
+	srv.SetMethodQuotas(c.methodQuotas)
+	srv.SetRateLimit(c.rateLimit, c.rateLimitBurst)
+	if c.apiKeys != nil {
+		srv.SetAuthorizer(c.apiKeys.authorize)
+	}
 }
 
 type rpcHandler struct {
@@ -192,7 +214,8 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// check if ws request and serve if ws enabled
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil && isWebsocket(r) {
-		if checkPath(r, h.wsConfig.prefix) {
+		r, ok := h.wsConfig.apiKeys.authenticate(w, r, h.wsConfig.prefix)
+		if ok && checkPath(r, h.wsConfig.prefix) {
 			ws.ServeHTTP(w, r)
 		}
 		return
@@ -211,6 +234,10 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		r, ok := h.httpConfig.apiKeys.authenticate(w, r, h.httpConfig.prefix)
+		if !ok {
+			return
+		}
 		if checkPath(r, h.httpConfig.prefix) {
 			rpc.ServeHTTP(w, r)
 			return
@@ -297,6 +324,7 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
+	config.apply(srv)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -328,6 +356,7 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	}
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
+	config.apply(srv)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
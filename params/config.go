@@ -18,8 +18,11 @@ package params
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"sort"
 
 	"golang.org/x/crypto/sha3"
 
@@ -457,6 +460,8 @@ type ChainConfig struct {
 	BedrockBlock *big.Int `json:"bedrockBlock,omitempty"` // Bedrock switch block (nil = no fork, 0 = already on patex bedrock)
 	RegolithTime *uint64  `json:"regolithTime,omitempty"` // Regolith switch time (nil = no fork, 0 = already on patex regolith)
 
+	YieldEventsTime *uint64 `json:"yieldEventsTime,omitempty"` // Yield events switch time (nil = no fork, 0 = already emitting yield events)
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -497,6 +502,100 @@ func (c *CliqueConfig) String() string {
 type PatexConfig struct {
 	EIP1559Elasticity  uint64 `json:"eip1559Elasticity"`
 	EIP1559Denominator uint64 `json:"eip1559Denominator"`
+
+	// FeeSplit is the priority fee distribution in effect from genesis. If nil,
+	// the legacy coinbase/staking rewarder split selected by chain ID is used.
+	FeeSplit *FeeSplitConfig `json:"feeSplit,omitempty"`
+	// FeeSplitUpdates schedules replacements of the fee split at fork timestamps,
+	// ordered by ascending activation time.
+	FeeSplitUpdates []FeeSplitUpdate `json:"feeSplitUpdates,omitempty"`
+}
+
+// FeeSplitRecipient is a single destination of a share of the priority fee.
+type FeeSplitRecipient struct {
+	Address common.Address `json:"address"`
+	Weight  uint64         `json:"weight"`
+}
+
+// FeeSplitConfig describes how the priority fee of a transaction is divided
+// between the block coinbase and a list of fixed recipients. Every party
+// receives a share proportional to its weight; the rounding remainder goes to
+// the last recipient (or to the coinbase if there are no recipients).
+type FeeSplitConfig struct {
+	CoinbaseWeight uint64              `json:"coinbaseWeight"`
+	Recipients     []FeeSplitRecipient `json:"recipients,omitempty"`
+}
+
+// FeeSplitUpdate activates a new fee split at the given block timestamp.
+type FeeSplitUpdate struct {
+	Time     uint64          `json:"time"`
+	FeeSplit *FeeSplitConfig `json:"feeSplit"`
+}
+
+// TotalWeight returns the sum of the coinbase and recipient weights.
+func (f *FeeSplitConfig) TotalWeight() uint64 {
+	total := f.CoinbaseWeight
+	for _, r := range f.Recipients {
+		total += r.Weight
+	}
+	return total
+}
+
+// Split divides fee according to the configured weights. The returned coinbase
+// share and recipient shares always add up to fee.
+func (f *FeeSplitConfig) Split(fee *big.Int) (*big.Int, []*big.Int) {
+	var (
+		total     = new(big.Int).SetUint64(f.TotalWeight())
+		remaining = new(big.Int).Set(fee)
+		shares    = make([]*big.Int, len(f.Recipients))
+	)
+	if len(f.Recipients) == 0 || total.Sign() == 0 {
+		return remaining, shares
+	}
+	coinbase := new(big.Int).SetUint64(f.CoinbaseWeight)
+	coinbase.Mul(coinbase, fee).Div(coinbase, total)
+	remaining.Sub(remaining, coinbase)
+
+	last := len(f.Recipients) - 1
+	for i, r := range f.Recipients[:last] {
+		share := new(big.Int).SetUint64(r.Weight)
+		share.Mul(share, fee).Div(share, total)
+		remaining.Sub(remaining, share)
+		shares[i] = share
+	}
+	shares[last] = remaining
+	return coinbase, shares
+}
+
+// validate checks that the fee split can distribute a fee.
+func (f *FeeSplitConfig) validate() error {
+	total := f.CoinbaseWeight
+	for i, r := range f.Recipients {
+		if r.Weight == 0 {
+			return fmt.Errorf("fee split recipient %d (%v) has zero weight", i, r.Address)
+		}
+		if total+r.Weight < total {
+			return errors.New("fee split weights overflow")
+		}
+		total += r.Weight
+	}
+	if total == 0 {
+		return errors.New("fee split has zero total weight")
+	}
+	return nil
+}
+
+// LegacyFeeSplit returns the fixed 50/50 coinbase and staking rewarder split
+// that was hardcoded before fee splits became configurable.
+func LegacyFeeSplit(chainID *big.Int) *FeeSplitConfig {
+	rewarder := PATEXTestnetStakingRewarderFeeRecipient
+	if chainID != nil && chainID.Cmp(MainnetChainID) == 0 {
+		rewarder = PATEXMainnetStakingRewarderFeeRecipient
+	}
+	return &FeeSplitConfig{
+		CoinbaseWeight: 1,
+		Recipients:     []FeeSplitRecipient{{Address: rewarder, Weight: 1}},
+	}
 }
 
 // String implements the stringer interface, returning the patex fee config details.
@@ -595,6 +694,9 @@ func (c *ChainConfig) Description() string {
 	if c.RegolithTime != nil {
 		banner += fmt.Sprintf(" - Regolith:                    @%-10v\n", *c.RegolithTime)
 	}
+	if c.YieldEventsTime != nil {
+		banner += fmt.Sprintf(" - Yield events:                @%-10v\n", *c.YieldEventsTime)
+	}
 	return banner
 }
 
@@ -702,6 +804,11 @@ func (c *ChainConfig) IsRegolith(time uint64) bool {
 	return isTimestampForked(c.RegolithTime, time)
 }
 
+// IsYieldEvents returns whether time is either equal to the yield events fork time or greater.
+func (c *ChainConfig) IsYieldEvents(time uint64) bool {
+	return isTimestampForked(c.YieldEventsTime, time)
+}
+
 // IsPatex returns whether the node is an patex node or not.
 func (c *ChainConfig) IsPatex() bool {
 	return c.Patex != nil
@@ -716,11 +823,32 @@ func (c *ChainConfig) IsPatexRegolith(time uint64) bool {
 	return c.IsPatex() && c.IsRegolith(time)
 }
 
+// IsPatexYieldEvents returns true iff this is an patex node & account yield
+// configuration changes and claims are reported as logs.
+func (c *ChainConfig) IsPatexYieldEvents(time uint64) bool {
+	return c.IsPatex() && c.IsYieldEvents(time)
+}
+
 // IsPatexPreBedrock returns true iff this is an patex node & bedrock is not yet active
 func (c *ChainConfig) IsPatexPreBedrock(num *big.Int) bool {
 	return c.IsPatex() && !c.IsBedrock(num)
 }
 
+// PatexFeeSplit returns the priority fee split active at the given block time.
+func (c *ChainConfig) PatexFeeSplit(time uint64) *FeeSplitConfig {
+	if c.Patex != nil {
+		for i := len(c.Patex.FeeSplitUpdates) - 1; i >= 0; i-- {
+			if update := c.Patex.FeeSplitUpdates[i]; update.Time <= time {
+				return update.FeeSplit
+			}
+		}
+		if c.Patex.FeeSplit != nil {
+			return c.Patex.FeeSplit
+		}
+	}
+	return LegacyFeeSplit(c.ChainID)
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64, time uint64) *ConfigCompatError {
@@ -810,6 +938,34 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	if c.Patex != nil {
+		if err := c.Patex.checkFeeSplits(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFeeSplits verifies that every configured fee split is usable and that
+// the scheduled updates are in ascending time order.
+func (o *PatexConfig) checkFeeSplits() error {
+	if o.FeeSplit != nil {
+		if err := o.FeeSplit.validate(); err != nil {
+			return fmt.Errorf("invalid patex feeSplit: %w", err)
+		}
+	}
+	for i, update := range o.FeeSplitUpdates {
+		if update.FeeSplit == nil {
+			return fmt.Errorf("patex feeSplitUpdates[%d] at timestamp %d has no fee split", i, update.Time)
+		}
+		if err := update.FeeSplit.validate(); err != nil {
+			return fmt.Errorf("invalid patex feeSplitUpdates[%d]: %w", i, err)
+		}
+		if i > 0 && o.FeeSplitUpdates[i-1].Time >= update.Time {
+			return fmt.Errorf("unsupported patex fee split ordering: update at timestamp %d follows update at timestamp %d",
+				update.Time, o.FeeSplitUpdates[i-1].Time)
+		}
+	}
 	return nil
 }
 
@@ -878,9 +1034,38 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.PragueTime, newcfg.PragueTime, headTimestamp) {
 		return newTimestampCompatError("Prague fork timestamp", c.PragueTime, newcfg.PragueTime)
 	}
+	if isForkTimestampIncompatible(c.YieldEventsTime, newcfg.YieldEventsTime, headTimestamp) {
+		return newTimestampCompatError("Yield events fork timestamp", c.YieldEventsTime, newcfg.YieldEventsTime)
+	}
+	if time, incompatible := isFeeSplitIncompatible(c, newcfg, headTimestamp); incompatible {
+		return newTimestampCompatError("Patex fee split", &time, &time)
+	}
 	return nil
 }
 
+// isFeeSplitIncompatible returns the earliest timestamp at or before head at
+// which the two configs distribute priority fees differently.
+func isFeeSplitIncompatible(c1, c2 *ChainConfig, head uint64) (uint64, bool) {
+	times := []uint64{0}
+	for _, c := range []*ChainConfig{c1, c2} {
+		if c.Patex != nil {
+			for _, update := range c.Patex.FeeSplitUpdates {
+				times = append(times, update.Time)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, time := range times {
+		if time > head {
+			break
+		}
+		if !reflect.DeepEqual(c1.PatexFeeSplit(time), c2.PatexFeeSplit(time)) {
+			return time, true
+		}
+	}
+	return 0, false
+}
+
 // BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
 func (c *ChainConfig) BaseFeeChangeDenominator() uint64 {
 	if c.Patex != nil {
@@ -1029,7 +1214,7 @@ type Rules struct {
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
-	IsPatexBedrock, IsPatexRegolith                         bool
+	IsPatexBedrock, IsPatexRegolith, IsPatexYieldEvents     bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1055,7 +1240,8 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsCancun:         c.IsCancun(timestamp),
 		IsPrague:         c.IsPrague(timestamp),
 		// Patex
-		IsPatexBedrock:  c.IsPatexBedrock(num),
-		IsPatexRegolith: c.IsPatexRegolith(timestamp),
+		IsPatexBedrock:     c.IsPatexBedrock(num),
+		IsPatexRegolith:    c.IsPatexRegolith(timestamp),
+		IsPatexYieldEvents: c.IsBerlin(num) && c.IsPatexYieldEvents(timestamp), // The fork extends the Berlin precompiles
 	}
 }
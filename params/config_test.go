@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 )
 
@@ -155,3 +156,93 @@ func TestConfigRulesRegolith(t *testing.T) {
 		t.Errorf("expected %v to be regolith", stamp)
 	}
 }
+
+func TestConfigRulesYieldEvents(t *testing.T) {
+	c := &ChainConfig{
+		BerlinBlock:     big.NewInt(10),
+		YieldEventsTime: newUint64(0),
+		Patex:           &PatexConfig{},
+	}
+	if r := c.Rules(big.NewInt(0), true, 0); r.IsPatexYieldEvents {
+		t.Errorf("expected yield events to require berlin")
+	}
+	if r := c.Rules(big.NewInt(10), true, 0); !r.IsPatexYieldEvents {
+		t.Errorf("expected yield events once berlin is active")
+	}
+}
+
+func TestPatexFeeSplit(t *testing.T) {
+	var (
+		recipientA = common.HexToAddress("0xaaaa")
+		recipientB = common.HexToAddress("0xbbbb")
+		base       = &FeeSplitConfig{CoinbaseWeight: 1, Recipients: []FeeSplitRecipient{{Address: recipientA, Weight: 1}}}
+		updated    = &FeeSplitConfig{CoinbaseWeight: 2, Recipients: []FeeSplitRecipient{{Address: recipientA, Weight: 1}, {Address: recipientB, Weight: 1}}}
+	)
+	c := &ChainConfig{
+		ChainID: big.NewInt(901),
+		Patex: &PatexConfig{
+			FeeSplit:        base,
+			FeeSplitUpdates: []FeeSplitUpdate{{Time: 100, FeeSplit: updated}},
+		},
+	}
+	if err := c.CheckConfigForkOrder(); err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	if split := c.PatexFeeSplit(99); split != base {
+		t.Errorf("expected base fee split before update, got %+v", split)
+	}
+	if split := c.PatexFeeSplit(100); split != updated {
+		t.Errorf("expected updated fee split at update time, got %+v", split)
+	}
+
+	coinbase, shares := updated.Split(big.NewInt(101))
+	if coinbase.Cmp(big.NewInt(50)) != 0 || shares[0].Cmp(big.NewInt(25)) != 0 || shares[1].Cmp(big.NewInt(26)) != 0 {
+		t.Errorf("unexpected split: coinbase %v, shares %v", coinbase, shares)
+	}
+
+	// Configs without an explicit split keep the legacy chain ID based routing.
+	legacy := &ChainConfig{ChainID: MainnetChainID, Patex: &PatexConfig{}}
+	split := legacy.PatexFeeSplit(0)
+	if len(split.Recipients) != 1 || split.Recipients[0].Address != PATEXMainnetStakingRewarderFeeRecipient {
+		t.Errorf("unexpected legacy fee split: %+v", split)
+	}
+	coinbase, shares = split.Split(big.NewInt(5))
+	if coinbase.Cmp(big.NewInt(2)) != 0 || shares[0].Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("unexpected legacy split: coinbase %v, shares %v", coinbase, shares)
+	}
+}
+
+func TestPatexFeeSplitValidation(t *testing.T) {
+	tests := []*PatexConfig{
+		{FeeSplit: &FeeSplitConfig{}},
+		{FeeSplit: &FeeSplitConfig{CoinbaseWeight: 1, Recipients: []FeeSplitRecipient{{Weight: 0}}}},
+		{FeeSplitUpdates: []FeeSplitUpdate{{Time: 10}}},
+		{FeeSplitUpdates: []FeeSplitUpdate{
+			{Time: 10, FeeSplit: &FeeSplitConfig{CoinbaseWeight: 1}},
+			{Time: 10, FeeSplit: &FeeSplitConfig{CoinbaseWeight: 1}},
+		}},
+	}
+	for i, patex := range tests {
+		c := &ChainConfig{ChainID: big.NewInt(901), Patex: patex}
+		if err := c.CheckConfigForkOrder(); err == nil {
+			t.Errorf("test %d: expected fee split validation error", i)
+		}
+	}
+}
+
+func TestPatexFeeSplitCompatible(t *testing.T) {
+	stored := &ChainConfig{ChainID: big.NewInt(901), Patex: &PatexConfig{}}
+	changed := &ChainConfig{ChainID: big.NewInt(901), Patex: &PatexConfig{
+		FeeSplitUpdates: []FeeSplitUpdate{{Time: 100, FeeSplit: &FeeSplitConfig{CoinbaseWeight: 1}}},
+	}}
+	if err := stored.CheckCompatible(changed, 0, 99); err != nil {
+		t.Errorf("unexpected error for future fee split update: %v", err)
+	}
+	err := stored.CheckCompatible(changed, 0, 200)
+	if err == nil {
+		t.Fatal("expected error for past fee split update")
+	}
+	if err.RewindToTime != 99 {
+		t.Errorf("unexpected rewind time: have %d, want 99", err.RewindToTime)
+	}
+}
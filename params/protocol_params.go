@@ -37,6 +37,7 @@ var (
 	PatexSharesAddress               = common.HexToAddress("0x0F2395DD2Dde5A0E905B35491Fe38873B65Bb16B")
 	PatexGasAddress                  = common.HexToAddress("0x17ca24570E9A78e1A3B72f81c61b13D08CE541cD")
 	PatexAccountConfigurationAddress = common.HexToAddress("0x2546E425567AC9fc9e698D76D973d8E1329A5b90")
+	PatexPrecompileAddress           = common.BytesToAddress([]byte{1, 0})
 )
 
 const (
@@ -61,12 +61,18 @@ const (
 	errcodeDefault                  = -32000
 	errcodeNotificationsUnsupported = -32001
 	errcodeTimeout                  = -32002
+	errcodeResponseTooLarge         = -32003
+	errcodeLimitExceeded            = -32005
 	errcodePanic                    = -32603
 	errcodeMarshalError             = -32603
 )
 
 const (
-	errMsgTimeout = "request timed out"
+	errMsgTimeout          = "request timed out"
+	errMsgBatchTooLarge    = "batch too large"
+	errMsgResponseTooLarge = "response too large"
+	errMsgQuotaExceeded    = "method concurrency quota exceeded"
+	errMsgRateLimited      = "request rate limit exceeded"
 )
 
 var ErrNoHistoricalFallback = NoHistoricalFallbackError{}
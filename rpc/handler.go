@@ -60,6 +60,7 @@ type handler struct {
 	conn           jsonWriter                     // where responses will be sent
 	log            log.Logger
 	allowSubscribe bool
+	limits         *serverLimits // limits of serving calls, nil if unlimited
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -70,7 +71,7 @@ type callProc struct {
 	notifiers []*Notifier
 }
 
-func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry) *handler {
+func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, limits *serverLimits) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 	h := &handler{
 		reg:            reg,
@@ -83,6 +84,7 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 		allowSubscribe: true,
 		serverSubs:     make(map[ID]*Subscription),
 		log:            log.Root(),
+		limits:         limits,
 	}
 	if conn.remoteAddr() != "" {
 		h.log = h.log.New("conn", conn.remoteAddr())
@@ -137,13 +139,18 @@ func (b *batchCallBuffer) write(ctx context.Context, conn jsonWriter) {
 // timeout sends the responses added so far. For the remaining unanswered call
 // messages, it sends a timeout error response.
 func (b *batchCallBuffer) timeout(ctx context.Context, conn jsonWriter) {
+	b.respondWithError(ctx, conn, &internalServerError{errcodeTimeout, errMsgTimeout})
+}
+
+// respondWithError sends the responses added so far. For the remaining unanswered
+// call messages, it responds with the given error.
+func (b *batchCallBuffer) respondWithError(ctx context.Context, conn jsonWriter, err error) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
 	for _, msg := range b.calls {
 		if !msg.isNotification() {
-			resp := msg.errorResponse(&internalServerError{errcodeTimeout, errMsgTimeout})
-			b.resp = append(b.resp, resp)
+			b.resp = append(b.resp, msg.errorResponse(err))
 		}
 	}
 	b.doWrite(ctx, conn, true)
@@ -171,6 +178,14 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		})
 		return
 	}
+	// Apply the limit on the number of items before processing any of them.
+	if h.limits != nil && h.limits.batchItemLimit != 0 && len(msgs) > h.limits.batchItemLimit {
+		rpcBatchTooLargeMeter.Mark(1)
+		h.startCallProc(func(cp *callProc) {
+			h.respondWithBatchTooLarge(cp, msgs)
+		})
+		return
+	}
 
 	// Handle non-call messages first:
 	calls := make([]*jsonrpcMessage, 0, len(msgs))
@@ -203,6 +218,7 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 			})
 		}
 
+		var responseBytes int
 		for {
 			// No need to handle rest of calls if timed out.
 			if cp.ctx.Err() != nil {
@@ -213,6 +229,17 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 				break
 			}
 			resp := h.handleCallMsg(cp, msg)
+			if resp != nil && h.limits != nil && h.limits.batchResponseLimit != 0 {
+				// Once the limit is hit, the call crossing it and all remaining
+				// ones are answered with an error.
+				if responseBytes += len(resp.Result); responseBytes > h.limits.batchResponseLimit {
+					rpcResponseTooLargeMeter.Mark(1)
+					err := &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+					callBuffer.pushResponse(msg.errorResponse(err))
+					callBuffer.respondWithError(cp.ctx, h.conn, err)
+					break
+				}
+			}
 			callBuffer.pushResponse(resp)
 		}
 		if timer != nil {
@@ -226,6 +253,20 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	})
 }
 
+// respondWithBatchTooLarge answers a batch exceeding the item limit with a single
+// error. The protocol has no way of reporting an error for the entire batch, so the
+// error carries the ID of the first call.
+func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage) {
+	resp := errorMessage(&invalidRequestError{errMsgBatchTooLarge})
+	for _, msg := range batch {
+		if msg.isCall() {
+			resp.ID = msg.ID
+			break
+		}
+	}
+	h.conn.writeJSON(cp.ctx, []*jsonrpcMessage{resp}, true)
+}
+
 // handleMsg handles a single message.
 func (h *handler) handleMsg(msg *jsonrpcMessage) {
 	if ok := h.handleImmediate(msg); ok {
@@ -439,6 +480,13 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if !msg.isUnsubscribe() {
+		release, err := h.limits.acquire(cp.ctx, msg.Method)
+		if err != nil {
+			return msg.errorResponse(err)
+		}
+		defer release()
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
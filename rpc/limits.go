@@ -0,0 +1,67 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/internal/ratelimit"
+)
+
+// serverLimits are the access and resource limits a server applies to the
+// requests it serves. The zero value applies no limits.
+type serverLimits struct {
+	authorize Authorizer // Authorization of method calls, nil if all are permitted
+
+	batchItemLimit     int // Maximum number of items in a batch, unlimited if zero
+	batchResponseLimit int // Maximum number of response bytes of a batch, unlimited if zero
+
+	quotas   map[string]chan struct{}   // Semaphores of the methods with a concurrency quota
+	requests *ratelimit.Limiter[string] // Token buckets of the client hosts, nil if unlimited
+}
+
+// acquire authorizes a call of the given method to the client of the given call
+// context and reserves the resources for serving it. On success, the returned
+// function must be called once the call is served.
+func (l *serverLimits) acquire(ctx context.Context, method string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if l.authorize != nil {
+		if err := l.authorize(ctx, method); err != nil {
+			return nil, err
+		}
+	}
+	if l.requests != nil {
+		// Requests of unknown origin, i.e. in-process ones, are not limited.
+		if remote := PeerInfoFromContext(ctx).RemoteAddr; remote != "" && !l.requests.Allow(ratelimit.Host(remote)) {
+			rpcRateLimitedMeter.Mark(1)
+			return nil, &internalServerError{errcodeLimitExceeded, errMsgRateLimited}
+		}
+	}
+	quota, ok := l.quotas[method]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case quota <- struct{}{}:
+		return func() { <-quota }, nil
+	default:
+		rpcQuotaExceededMeter.Mark(1)
+		return nil, &internalServerError{errcodeLimitExceeded, errMsgQuotaExceeded}
+	}
+}
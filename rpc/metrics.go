@@ -32,6 +32,11 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	rpcBatchTooLargeMeter    = metrics.NewRegisteredMeter("rpc/limits/batchitems", nil)
+	rpcResponseTooLargeMeter = metrics.NewRegisteredMeter("rpc/limits/batchresponse", nil)
+	rpcQuotaExceededMeter    = metrics.NewRegisteredMeter("rpc/limits/quota", nil)
+	rpcRateLimitedMeter      = metrics.NewRegisteredMeter("rpc/limits/ratelimit", nil)
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
@@ -22,6 +22,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/ethereum/go-ethereum/internal/ratelimit"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -49,6 +50,7 @@ type Server struct {
 	mutex  sync.Mutex
 	codecs map[ServerCodec]struct{}
 	run    int32
+	limits serverLimits
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -73,6 +75,60 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetBatchLimits sets limits applied to batch requests. There are two limits: 'itemLimit'
+// is the maximum number of items in a batch. 'maxResponseSize' is the maximum number of
+// response bytes across all requests in a batch. Zero disables the respective limit.
+// The responses of requests sent on their own are not limited in size.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetBatchLimits(itemLimit, maxResponseSize int) {
+	s.limits.batchItemLimit = itemLimit
+	s.limits.batchResponseLimit = maxResponseSize
+}
+
+// SetMethodQuotas limits the number of calls of the given methods the server
+// executes concurrently, across all connections. Calls exceeding the quota of
+// their method are rejected rather than queued.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetMethodQuotas(quotas map[string]int) {
+	s.limits.quotas = make(map[string]chan struct{}, len(quotas))
+	for method, quota := range quotas {
+		if quota > 0 {
+			s.limits.quotas[method] = make(chan struct{}, quota)
+		}
+	}
+}
+
+// SetRateLimit limits the number of calls per second each client may make, with
+// bursts of up to 'burst' calls. Clients are identified by their remote host, every
+// item of a batch counts as a call. A non-positive limit disables rate limiting.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRateLimit(limit float64, burst int) {
+	s.limits.requests = nil
+	if limit > 0 {
+		s.limits.requests = ratelimit.New[string](limit, burst)
+	}
+}
+
+// Authorizer decides whether the client of the given request context may call
+// the given method. The returned error is sent to the client in place of the
+// result. The client is described by the PeerInfo of the context.
+type Authorizer func(ctx context.Context, method string) error
+
+// SetAuthorizer installs a function authorizing every method call served by the
+// server, except for unsubscribing. A nil authorizer permits all calls.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetAuthorizer(authorize Authorizer) {
+	s.limits.authorize = authorize
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -86,7 +142,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	}
 	defer s.untrackCodec(codec)
 
-	c := initClient(codec, s.idgen, &s.services)
+	c := initClient(codec, s.idgen, &s.services, &s.limits)
 	<-codec.closed()
 	c.Close()
 }
@@ -118,7 +174,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services)
+	h := newHandler(ctx, codec, s.idgen, &s.services, &s.limits)
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
@@ -191,11 +247,29 @@ type PeerInfo struct {
 		UserAgent string
 		Origin    string
 		Host      string
+		// Name of the API key the client authenticated with, empty if none.
+		// It is set by the HTTP server through WithAPIKey.
+		APIKey string
 	}
 }
 
 type peerInfoContextKey struct{}
 
+type apiKeyContextKey struct{}
+
+// WithAPIKey returns a copy of the HTTP request context carrying the name of the
+// API key the request was authenticated with. Requests served with such a context
+// report the name in PeerInfo.
+func WithAPIKey(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, name)
+}
+
+// apiKeyFromContext returns the name of the API key set through WithAPIKey.
+func apiKeyFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyContextKey{}).(string)
+	return name
+}
+
 // PeerInfoFromContext returns information about the client's network connection.
 // Use this with the context passed to RPC method handler functions.
 //
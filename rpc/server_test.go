@@ -19,8 +19,14 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -152,3 +158,130 @@ func TestServerShortLivedConn(t *testing.T) {
 		}
 	}
 }
+
+// postBatch sends the given calls of test_echo as a batch over HTTP and returns
+// the responses.
+func postBatch(t *testing.T, url string, calls int) []*jsonrpcMessage {
+	t.Helper()
+
+	batch := make([]string, calls)
+	for i := range batch {
+		batch[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"test_echo","params":["x",1]}`, i+1)
+	}
+	resp, err := http.Post(url, contentType, strings.NewReader("["+strings.Join(batch, ",")+"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var msgs []*jsonrpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msgs); err != nil {
+		t.Fatal(err)
+	}
+	return msgs
+}
+
+func TestServerBatchItemLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetBatchLimits(3, 0)
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	if resps := postBatch(t, httpsrv.URL, 3); len(resps) != 3 {
+		t.Fatalf("wrong number of responses: %d", len(resps))
+	}
+	// A batch exceeding the limit is answered with a single error.
+	resps := postBatch(t, httpsrv.URL, 4)
+	if len(resps) != 1 {
+		t.Fatalf("wrong number of responses: %d", len(resps))
+	}
+	if resps[0].Error == nil || resps[0].Error.Code != -32600 || resps[0].Error.Message != errMsgBatchTooLarge {
+		t.Fatalf("wrong error: %v", resps[0].Error)
+	}
+	if string(resps[0].ID) != "1" {
+		t.Fatalf("wrong response ID: %s", resps[0].ID)
+	}
+}
+
+func TestServerBatchResponseSizeLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	// The result of every call is 34 bytes, {"String":"x","Int":1,"Args":null}.
+	server.SetBatchLimits(0, 50)
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	resps := postBatch(t, httpsrv.URL, 3)
+	if len(resps) != 3 {
+		t.Fatalf("wrong number of responses: %d", len(resps))
+	}
+	if resps[0].Error != nil || len(resps[0].Result) != 34 {
+		t.Fatalf("wrong first response: result %s, error %v", resps[0].Result, resps[0].Error)
+	}
+	for _, resp := range resps[1:] {
+		if resp.Error == nil || resp.Error.Code != errcodeResponseTooLarge || resp.Result != nil {
+			t.Fatalf("wrong response %s: result %s, error %v", resp.ID, resp.Result, resp.Error)
+		}
+	}
+}
+
+func TestServerMethodQuotas(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetMethodQuotas(map[string]int{"test_block": 1})
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Occupy the quota of test_block until the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- client.CallContext(ctx, nil, "test_block") }()
+
+	for quota := server.limits.quotas["test_block"]; len(quota) == 0; {
+		time.Sleep(time.Millisecond)
+	}
+	var rpcErr Error
+	err := client.Call(nil, "test_block")
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("wrong error: %v", err)
+	}
+	// Methods without quota are not affected.
+	if err := client.Call(nil, "test_null"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	<-done
+}
+
+func TestServerRateLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetRateLimit(0.001, 2)
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	// Every item of a batch takes a token.
+	resps := postBatch(t, httpsrv.URL, 3)
+	if len(resps) != 3 {
+		t.Fatalf("wrong number of responses: %d", len(resps))
+	}
+	for i, resp := range resps {
+		limited := resp.Error != nil && resp.Error.Code == errcodeLimitExceeded
+		if limited != (i == 2) {
+			t.Fatalf("wrong response %s: result %s, error %v", resp.ID, resp.Result, resp.Error)
+		}
+	}
+	// Calls of unknown origin are not limited.
+	client := DialInProc(server)
+	defer client.Close()
+
+	if err := client.Call(nil, "test_null"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
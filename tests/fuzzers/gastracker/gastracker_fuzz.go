@@ -0,0 +1,130 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gastracker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type fuzzer struct {
+	input     io.Reader
+	exhausted bool
+}
+
+func (f *fuzzer) read(size int) []byte {
+	out := make([]byte, size)
+	if _, err := f.input.Read(out); err != nil {
+		f.exhausted = true
+	}
+	return out
+}
+
+func (f *fuzzer) readUint64() uint64 {
+	var a uint64
+	if err := binary.Read(f.input, binary.LittleEndian, &a); err != nil {
+		f.exhausted = true
+	}
+	return a
+}
+
+func (f *fuzzer) readByte() byte {
+	return f.read(1)[0]
+}
+
+// Fuzz feeds arbitrary gas attributions, refunds, gas prices, timestamps and
+// raw gas parameter slots into the gas tracker. Gas tracking runs inside block
+// processing, so it must never panic, and every scaled gas unit must be paid
+// out either to a contract or to the base fee recipient.
+//
+// Fuzz function must return
+//
+//   - 1 if the fuzzer should increase priority of the
+//     given input during subsequent fuzzing (for example, the input is lexically
+//     correct and was parsed successfully);
+//   - -1 if the input must not be added to corpus even if gives new coverage; and
+//   - 0 otherwise
+//
+// other values are reserved for future use.
+func Fuzz(data []byte) int {
+	f := fuzzer{input: bytes.NewReader(data)}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	var (
+		tracker  = vm.NewGasTracker()
+		contract = make([]common.Address, 1+f.readByte()%8)
+	)
+	for i := range contract {
+		contract[i] = common.BytesToAddress([]byte{byte(i + 1)})
+		// Arbitrary, possibly extreme, gas parameters
+		slot := crypto.Keccak256Hash(contract[i].Bytes(), []byte("parameters"))
+		statedb.SetState(params.PatexGasAddress, slot, common.BytesToHash(f.read(32)))
+	}
+	for ops := f.readByte(); ops > 0 && !f.exhausted; ops-- {
+		addr := contract[int(f.readByte())%len(contract)]
+		amount := f.readUint64() % (1 << 40)
+		if f.readByte()%4 == 0 {
+			tracker.RefundGas(addr, amount)
+		} else {
+			tracker.UseGas(addr, amount)
+		}
+	}
+	if f.exhausted {
+		return 0
+	}
+	var (
+		gasPrice  = new(big.Int).SetBytes(f.read(16))
+		refund    = f.readUint64() % (tracker.GetGasUsed() + 1)
+		timestamp = f.readUint64()
+	)
+	allocation, err := tracker.AllocateDevGas(gasPrice, refund, statedb, timestamp)
+	if err != nil {
+		return 0
+	}
+	if tracker.GetGasUsed() <= refund {
+		return 1
+	}
+	paid := new(big.Int).Add(allocation.ClaimableFee, allocation.UnallocatedFee)
+	want := new(big.Int).Mul(new(big.Int).SetUint64(tracker.GetGasUsed()-refund), gasPrice)
+	if paid.Cmp(want) != 0 {
+		panic(fmt.Sprintf("fees not conserved: paid %v, want %v", paid, want))
+	}
+	credited := new(big.Int)
+	for _, c := range allocation.Contracts {
+		credited.Add(credited, c.Fee)
+	}
+	if credited.Cmp(allocation.ClaimableFee) != 0 {
+		panic(fmt.Sprintf("claimable fee mismatch: credited %v, claimable %v", credited, allocation.ClaimableFee))
+	}
+	for _, c := range allocation.Contracts {
+		if _, err := vm.ReadGasParameters(statedb, c.Address); err != nil {
+			panic(fmt.Sprintf("unreadable gas parameters of %v: %v", c.Address, err))
+		}
+	}
+	return 1
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gastracker
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFuzzer(t *testing.T) {
+	// Saturated gas parameters, maximal gas price and timestamp
+	test := append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 64)...)
+	test = append(test, 0x04, 0x00)
+	test = append(test, bytes.Repeat([]byte{0xff}, 8)...)
+	test = append(test, 0x01, 0x01)
+	test = append(test, bytes.Repeat([]byte{0x7f}, 8)...)
+	test = append(test, 0x01, 0x00)
+	test = append(test, bytes.Repeat([]byte{0x7f}, 8)...)
+	test = append(test, 0x01, 0x01, 0x00)
+	test = append(test, bytes.Repeat([]byte{0x01}, 8)...)
+	test = append(test, 0x00)
+	test = append(test, bytes.Repeat([]byte{0xff}, 40)...)
+	if Fuzz(test) != 1 {
+		t.Fatal("seed input rejected")
+	}
+	Fuzz([]byte("0000000000000000000000000000000000000000000000000000000000000000000000000000000"))
+}